@@ -19,12 +19,16 @@ package cli
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"os"
+	"slices"
 	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -71,10 +75,24 @@ var (
 	minBackupInterval   time.Duration
 	minRetentionTime    time.Duration
 	minRetentionCount   = 1
+	removeBackupTimeout time.Duration
 	initialBackup       bool
 	allowFirstBackup    bool
 	restartBeforeBackup bool
 	upgradeSafe         bool
+	verifyAfterBackup   bool
+	backupLabel         string
+	restoreToBackup     string
+	restoreToPos        string
+	listBackups         bool
+	exitCodeOnNoop      int
+	requireCaughtUp     bool
+
+	forbiddenSourceCells      []string
+	minBackupAgeBeforeRestore time.Duration
+
+	initialBackupCompressionEngine string
+	initialBackupSkipCompress      bool
 
 	// vttablet-like flags
 	initDbNameOverride string
@@ -91,6 +109,7 @@ var (
 	initDBSQLFile        string
 	detachedMode         bool
 	keepAliveTimeout     time.Duration
+	keepDataOnFailure    bool
 	disableRedoLog       bool
 
 	collationEnv *collations.Environment
@@ -194,10 +213,22 @@ func init() {
 	Main.Flags().DurationVar(&minBackupInterval, "min_backup_interval", minBackupInterval, "Only take a new backup if it's been at least this long since the most recent backup.")
 	Main.Flags().DurationVar(&minRetentionTime, "min_retention_time", minRetentionTime, "Keep each old backup for at least this long before removing it. Set to 0 to disable pruning of old backups.")
 	Main.Flags().IntVar(&minRetentionCount, "min_retention_count", minRetentionCount, "Always keep at least this many of the most recent backups in this backup storage location, even if some are older than the min_retention_time. This must be at least 1 since a backup must always exist to allow new backups to be made")
+	Main.Flags().DurationVar(&removeBackupTimeout, "remove_backup_timeout", removeBackupTimeout, "How long to wait for each old backup to be removed when pruning. A backup that takes longer is skipped and pruning continues with the others; it will be retried on the next run. Set to 0 to not limit the time taken per backup.")
+	Main.Flags().DurationVar(&minBackupAgeBeforeRestore, "min_backup_age_before_restore", minBackupAgeBeforeRestore, "Ignore backups that are newer than this as restore candidates, treating them as potentially incomplete. Set to 0 to consider all complete backups.")
 	Main.Flags().BoolVar(&initialBackup, "initial_backup", initialBackup, "Instead of restoring from backup, initialize an empty database with the provided init_db_sql_file and upload a backup of that for the shard, if the shard has no backups yet. This can be used to seed a brand new shard with an initial, empty backup. If any backups already exist for the shard, this will be considered a successful no-op. This can only be done before the shard exists in topology (i.e. before any tablets are deployed).")
+	Main.Flags().StringVar(&initialBackupCompressionEngine, "initial_backup_compression_engine", initialBackupCompressionEngine, "Compression engine to use for the backup taken in --initial_backup mode instead of --compression-engine-name. Only honored by the builtin backup engine.")
+	Main.Flags().BoolVar(&initialBackupSkipCompress, "initial_backup_skip_compress", initialBackupSkipCompress, "Do not compress the backup taken in --initial_backup mode, regardless of --backup_storage_compress. Only honored by the builtin backup engine.")
 	Main.Flags().BoolVar(&allowFirstBackup, "allow_first_backup", allowFirstBackup, "Allow this job to take the first backup of an existing shard.")
 	Main.Flags().BoolVar(&restartBeforeBackup, "restart_before_backup", restartBeforeBackup, "Perform a mysqld clean/full restart after applying binlogs, but before taking the backup. Only makes sense to work around xtrabackup bugs.")
 	Main.Flags().BoolVar(&upgradeSafe, "upgrade-safe", upgradeSafe, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	Main.Flags().BoolVar(&verifyAfterBackup, "verify_after_backup", verifyAfterBackup, "After taking a new backup, check that its MANIFEST can be read from the backup storage and that it has the replication position the backup was taken at, failing the run if not. This does not restore the backup.")
+	Main.Flags().StringVar(&backupLabel, "backup_label", backupLabel, "An optional label, such as a release or the reason for the backup, to append to the backup name. Any characters other than letters, digits, dashes and underscores are replaced with underscores.")
+	Main.Flags().StringVar(&restoreToBackup, "restore_to_backup", restoreToBackup, "Restore-only mode: restore the backup with the given name and exit without catching up on replication or taking a new backup. mysqld is left running with the restored data.")
+	Main.Flags().StringSliceVar(&forbiddenSourceCells, "forbidden_source_cells", forbiddenSourceCells, "Comma-separated list of cells that vtbackup must never replicate from. If the tablet that would be used as the replication source is in one of these cells, vtbackup fails instead.")
+	Main.Flags().BoolVar(&listBackups, "list_backups", listBackups, "List the backups for the shard, with the time, engine, and position of each, and exit without restoring, taking, or pruning any backups.")
+	Main.Flags().StringVar(&restoreToPos, "restore_to_pos", restoreToPos, "Restore-only mode: run a point in time recovery, using one full backup followed by zero or more incremental backups, that ends with the given position. Exits without catching up on replication or taking a new backup. mysqld is left running with the restored data.")
+	Main.Flags().IntVar(&exitCodeOnNoop, "exit_code_on_noop", exitCodeOnNoop, "Exit with this code instead of 0 when no backup was needed, because the most recent backup is newer than --min_backup_interval or, with --initial_backup, because a backup already exists. Old backups are still pruned. Must be between 0 and 255, and not 1, which is used for failures.")
+	Main.Flags().BoolVar(&requireCaughtUp, "require_caught_up", requireCaughtUp, "Only take a backup if replication catches up to the primary's position. If it doesn't, fail without taking a backup, instead of taking one anyway to save partial progress.")
 
 	// vttablet-like flags
 	Main.Flags().StringVar(&initDbNameOverride, "init_db_name_override", initDbNameOverride, "(init parameter) override the name of the db used by vttablet")
@@ -213,7 +244,8 @@ func init() {
 	Main.Flags().DurationVar(&mysqlShutdownTimeout, "mysql-shutdown-timeout", mysqlShutdownTimeout, "how long to wait for mysqld shutdown")
 	Main.Flags().StringVar(&initDBSQLFile, "init_db_sql_file", initDBSQLFile, "path to .sql file to run after mysql_install_db")
 	Main.Flags().BoolVar(&detachedMode, "detach", detachedMode, "detached mode - run backups detached from the terminal")
-	Main.Flags().DurationVar(&keepAliveTimeout, "keep-alive-timeout", keepAliveTimeout, "Wait until timeout elapses after a successful backup before shutting down.")
+	Main.Flags().DurationVar(&keepAliveTimeout, "keep-alive-timeout", keepAliveTimeout, "Wait until timeout elapses after a successful backup, or restore in restore-only mode, before shutting down.")
+	Main.Flags().BoolVar(&keepDataOnFailure, "keep_data_on_failure", keepDataOnFailure, "If the backup fails, leave mysqld running and keep its temporary data directory, for debugging.")
 	Main.Flags().BoolVar(&disableRedoLog, "disable-redo-log", disableRedoLog, "Disable InnoDB redo log during replication-from-primary phase of backup.")
 
 	acl.RegisterFlags(Main.Flags())
@@ -252,6 +284,24 @@ func run(cc *cobra.Command, args []string) error {
 		log.Errorf("min_retention_count must be at least 1 to allow restores to succeed")
 		exit.Return(1)
 	}
+	if restoreToBackup != "" && restoreToPos != "" {
+		return fmt.Errorf("--restore_to_backup and --restore_to_pos are mutually exclusive")
+	}
+	if initialBackup && restoreOnlyMode() {
+		return fmt.Errorf("--initial_backup cannot be used together with --restore_to_backup or --restore_to_pos")
+	}
+	if minBackupAgeBeforeRestore < 0 {
+		return fmt.Errorf("--min_backup_age_before_restore must not be negative")
+	}
+	if listBackups && (initialBackup || restoreOnlyMode()) {
+		return fmt.Errorf("--list_backups cannot be used together with --initial_backup, --restore_to_backup, or --restore_to_pos")
+	}
+	if err := validateExitCodeOnNoop(); err != nil {
+		return err
+	}
+	if err := validateInitialBackupCompressionEngine(); err != nil {
+		return err
+	}
 
 	// Open connection backup storage.
 	backupStorage, err := backupstorage.GetBackupStorage()
@@ -259,10 +309,19 @@ func run(cc *cobra.Command, args []string) error {
 		return fmt.Errorf("Can't get backup storage: %w", err)
 	}
 	defer backupStorage.Close()
+
+	if listBackups {
+		return printBackups(ctx, os.Stdout, backupStorage, mysqlctl.GetBackupDir(initKeyspace, initShard))
+	}
+
 	// Open connection to topology server.
 	topoServer := topo.Open()
 	defer topoServer.Close()
 
+	if err := validateForbiddenSourceCells(ctx, topoServer); err != nil {
+		return err
+	}
+
 	// Initialize stats.
 	for _, phaseName := range phaseNames {
 		phase.Set(phaseName, int64(0))
@@ -277,6 +336,17 @@ func run(cc *cobra.Command, args []string) error {
 	// Skip pruning if backup wasn't fully successful. We don't want to be
 	// deleting things if the backup process is not healthy.
 	backupDir := mysqlctl.GetBackupDir(initKeyspace, initShard)
+	if restoreOnlyMode() {
+		// In restore-only mode we only restore the requested backup (and any
+		// incremental backups on top of it). No new backup is taken, so there
+		// is nothing new to account for when pruning either.
+		if err := takeBackup(ctx, cc.Context(), topoServer, backupStorage); err != nil {
+			return fmt.Errorf("Failed to restore backup: %w", err)
+		}
+		log.Info("Restore-only mode: restore was successful, not taking a new backup.")
+		waitForKeepAlive(ctx, "Restore")
+		return nil
+	}
 	doBackup, err := shouldBackup(ctx, topoServer, backupStorage, backupDir)
 	if err != nil {
 		return fmt.Errorf("Can't take backup: %w", err)
@@ -288,23 +358,73 @@ func run(cc *cobra.Command, args []string) error {
 	}
 
 	// Prune old backups.
-	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
+	pruned, err := pruneBackups(ctx, backupStorage, backupDir)
+	if len(pruned.removed) > 0 || len(pruned.skipped) > 0 {
+		log.Infof("Pruning old backups: %v", pruned)
+	}
+	if err != nil {
 		return fmt.Errorf("Couldn't prune old backups: %w", err)
 	}
 
-	if keepAliveTimeout > 0 {
-		log.Infof("Backup was successful, waiting %s before exiting (or until context expires).", keepAliveTimeout)
-		select {
-		case <-time.After(keepAliveTimeout):
-		case <-ctx.Done():
-		}
+	waitForKeepAlive(ctx, "Backup")
+	if code := noopExitCode(doBackup); code != 0 {
+		log.Infof("No backup was needed, exiting with code %d.", code)
+		exit.Return(code)
 	}
 	log.Info("Exiting.")
 
 	return nil
 }
 
-func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage) error {
+// checkCaughtUp returns an error if a backup should not be taken at the
+// replication position pos, after restoring to restorePos and trying to catch
+// up to the goal of primaryPos.
+func checkCaughtUp(pos, restorePos, primaryPos replication.Position) error {
+	if pos.AtLeast(primaryPos) {
+		return nil
+	}
+	if pos.Equal(restorePos) {
+		return fmt.Errorf("not taking backup: replication did not make any progress from restore point: %v", restorePos)
+	}
+	if requireCaughtUp {
+		return fmt.Errorf("not taking backup: replication caught up to %v but didn't make it to the goal of %v, and --require_caught_up is set", pos, primaryPos)
+	}
+	return nil
+}
+
+// validateExitCodeOnNoop checks that the --exit_code_on_noop is a valid exit
+// code that can't be confused with a failure.
+func validateExitCodeOnNoop() error {
+	if exitCodeOnNoop < 0 || exitCodeOnNoop > 255 || exitCodeOnNoop == 1 {
+		return fmt.Errorf("--exit_code_on_noop must be between 0 and 255, and not 1")
+	}
+	return nil
+}
+
+// validateInitialBackupCompressionEngine checks the
+// --initial_backup_compression_engine when an initial backup is requested,
+// so that an invalid engine is reported before mysqld is started rather than
+// when the backup's first file is compressed.
+func validateInitialBackupCompressionEngine() error {
+	if !initialBackup {
+		return nil
+	}
+	if err := mysqlctl.ValidateCompressionEngine(initialBackupCompressionEngine); err != nil {
+		return fmt.Errorf("invalid --initial_backup_compression_engine: %w", err)
+	}
+	return nil
+}
+
+// noopExitCode returns the code to exit with after a successful run, which
+// is the --exit_code_on_noop if no backup was needed.
+func noopExitCode(doBackup bool) int {
+	if doBackup {
+		return 0
+	}
+	return exitCodeOnNoop
+}
+
+func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, backupStorage backupstorage.BackupStorage) (err error) {
 	// This is an imaginary tablet alias. The value doesn't matter for anything,
 	// except that we generate a random UID to ensure the target backup
 	// directory is unique if multiple vtbackup instances are launched for the
@@ -323,12 +443,7 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	// every invocation of vtbackup starts with a clean slate, and it does not
 	// accumulate garbage (and run out of disk space) if it's restarted.
 	tabletDir := mysqlctl.TabletDir(tabletAlias.Uid)
-	defer func() {
-		log.Infof("Removing temporary tablet directory: %v", tabletDir)
-		if err := os.RemoveAll(tabletDir); err != nil {
-			log.Warningf("Failed to remove temporary tablet directory: %v", err)
-		}
-	}()
+	defer func() { removeTabletDir(tabletDir, err) }()
 
 	// Start up mysqld as if we are mysqlctld provisioning a fresh tablet.
 	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletAlias.Uid, mysqlSocket, mysqlPort, collationEnv)
@@ -343,15 +458,9 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	}
 	deprecatedDurationByPhase.Set("InitMySQLd", int64(time.Since(initMysqldAt).Seconds()))
 	// Shut down mysqld when we're done.
-	defer func() {
-		// Be careful use the background context, not the init one, because we don't want to
-		// skip shutdown just because we timed out waiting for init.
-		mysqlShutdownCtx, mysqlShutdownCancel := context.WithTimeout(backgroundCtx, mysqlShutdownTimeout+10*time.Second)
-		defer mysqlShutdownCancel()
-		if err := mysqld.Shutdown(mysqlShutdownCtx, mycnf, false, mysqlShutdownTimeout); err != nil {
-			log.Errorf("failed to shutdown mysqld: %v", err)
-		}
-	}()
+	// Be careful use the background context, not the init one, because we don't want to
+	// skip shutdown just because we timed out waiting for init.
+	defer func() { shutdownMysqld(backgroundCtx, mysqld, mycnf, err) }()
 
 	extraEnv := map[string]string{
 		"TABLET_ALIAS": topoproto.TabletAliasString(tabletAlias),
@@ -375,6 +484,7 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 		Stats:                backupstats.BackupStats(),
 		UpgradeSafe:          upgradeSafe,
 		MysqlShutdownTimeout: mysqlShutdownTimeout,
+		Label:                backupLabel,
 	}
 	// In initial_backup mode, just take a backup of this empty database.
 	if initialBackup {
@@ -405,6 +515,10 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 		}
 
 		backupParams.BackupTime = time.Now()
+		// The initial backup is of an empty database, so it may use different
+		// compression settings than the regular backups of the shard.
+		backupParams.CompressionEngine = initialBackupCompressionEngine
+		backupParams.SkipCompress = initialBackupSkipCompress
 		// Now we're ready to take the backup.
 		phase.Set(phaseNameInitialBackup, int64(1))
 		defer phase.Set(phaseNameInitialBackup, int64(0))
@@ -435,14 +549,24 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 		Stats:                backupstats.RestoreStats(),
 		MysqlShutdownTimeout: mysqlShutdownTimeout,
 	}
+	if err := setRestoreTarget(ctx, backupStorage, backupDir, &params); err != nil {
+		return err
+	}
+	setMinBackupAge(&params, restoreAt)
 	backupManifest, err := mysqlctl.Restore(ctx, params)
 	var restorePos replication.Position
 	switch err {
 	case nil:
 		// if err is nil, we expect backupManifest to be non-nil
+		if err := checkRestoredBackup(backupManifest); err != nil {
+			return err
+		}
 		restorePos = backupManifest.Position
 		log.Infof("Successfully restored from backup at replication position %v", restorePos)
 	case mysqlctl.ErrNoBackup:
+		if restoreOnlyMode() {
+			return fmt.Errorf("no backup found to restore from: %v", err)
+		}
 		// There is no backup found, but we may be taking the initial backup of a shard
 		if !allowFirstBackup {
 			return fmt.Errorf("no backup found; not starting up empty since --initial_backup flag was not enabled")
@@ -454,6 +578,13 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	deprecatedDurationByPhase.Set("RestoreLastBackup", int64(time.Since(restoreAt).Seconds()))
 	phase.Set(phaseNameRestoreLastBackup, int64(0))
 
+	if restoreOnlyMode() {
+		// We were only asked to restore, so we neither catch up on replication
+		// nor take a new backup.
+		log.Infof("Restore-only mode: restored to replication position %v, skipping replication catch up and backup.", restorePos)
+		return nil
+	}
+
 	// As of MySQL 8.0.21, you can disable redo logging using the ALTER INSTANCE
 	// DISABLE INNODB REDO_LOG statement. This functionality is intended for
 	// loading data into a new MySQL instance. Disabling redo logging speeds up
@@ -570,8 +701,8 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 		return fmt.Errorf("can't get replication status: %v", err)
 	}
 	log.Infof("Replication caught up to %v", status.Position)
-	if !status.Position.AtLeast(primaryPos) && status.Position.Equal(restorePos) {
-		return fmt.Errorf("not taking backup: replication did not make any progress from restore point: %v", restorePos)
+	if err := checkCaughtUp(status.Position, restorePos, primaryPos); err != nil {
+		return err
 	}
 	phaseStatus.Set([]string{phaseNameCatchupReplication, phaseStatusCatchupReplicationStalled}, 0)
 	phaseStatus.Set([]string{phaseNameCatchupReplication, phaseStatusCatchupReplicationStopped}, 0)
@@ -611,6 +742,14 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	deprecatedDurationByPhase.Set("TakeNewBackup", int64(time.Since(backupAt).Seconds()))
 	phase.Set(phaseNameTakeNewBackup, int64(0))
 
+	if verifyAfterBackup {
+		backupName := mysqlctl.BackupName(backupParams.BackupTime, backupParams.TabletAlias, backupParams.Label)
+		if err := verifyBackup(ctx, backupStorage, mysqlctl.GetBackupDir(initKeyspace, initShard), backupName, status.Position); err != nil {
+			return fmt.Errorf("backup %v failed verification: %w", backupName, err)
+		}
+		log.Infof("Verified backup %v", backupName)
+	}
+
 	// Return a non-zero exit code if we didn't meet the replication position
 	// goal, even though we took a backup that pushes the high-water mark up.
 	if !status.Position.AtLeast(primaryPos) {
@@ -620,6 +759,77 @@ func takeBackup(ctx, backgroundCtx context.Context, topoServer *topo.Server, bac
 	return nil
 }
 
+// restoreOnlyMode returns true if vtbackup was asked to restore a specific
+// backup or position rather than perform its usual backup maintenance.
+func restoreOnlyMode() bool {
+	return restoreToBackup != "" || restoreToPos != ""
+}
+
+// setRestoreTarget updates the given restore params to select the backup named
+// by --restore_to_backup or the point in time given by --restore_to_pos. It's
+// a no-op if neither of them is set.
+func setRestoreTarget(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string, params *mysqlctl.RestoreParams) error {
+	switch {
+	case restoreToBackup != "":
+		backups, err := backupStorage.ListBackups(ctx, backupDir)
+		if err != nil {
+			return fmt.Errorf("can't list backups: %v", err)
+		}
+		var found backupstorage.BackupHandle
+		for _, backup := range backups {
+			if backup.Name() == restoreToBackup {
+				found = backup
+				break
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("backup %v not found in %v", restoreToBackup, backupDir)
+		}
+		if err := checkBackupComplete(ctx, found); err != nil {
+			return fmt.Errorf("can't restore backup %v: %v", restoreToBackup, err)
+		}
+		backupTime, err := parseBackupTime(restoreToBackup)
+		if err != nil {
+			return err
+		}
+		// The restore picks the most recent backup taken at or before this time,
+		// which should be the one we just found. checkRestoredBackup verifies
+		// that once the restore is done.
+		params.StartTime = backupTime
+		log.Infof("Restoring backup %v from directory %v", restoreToBackup, backupDir)
+	case restoreToPos != "":
+		pos, _, err := replication.DecodePositionMySQL56(restoreToPos)
+		if err != nil {
+			return fmt.Errorf("can't decode --restore_to_pos %q: %v", restoreToPos, err)
+		}
+		params.RestoreToPos = pos
+		log.Infof("Restoring to position %v from directory %v", restoreToPos, backupDir)
+	}
+	return nil
+}
+
+// setMinBackupAge updates the given restore params so that only backups taken
+// at least --min_backup_age_before_restore before now are restore candidates.
+// It's a no-op in restore-only mode, where the backup to restore is given.
+func setMinBackupAge(params *mysqlctl.RestoreParams, now time.Time) {
+	if minBackupAgeBeforeRestore <= 0 || restoreOnlyMode() {
+		return
+	}
+	params.StartTime = now.Add(-minBackupAgeBeforeRestore)
+	log.Infof("Only restoring from backups taken at or before %v due to --min_backup_age_before_restore", params.StartTime)
+}
+
+// checkRestoredBackup returns an error if --restore_to_backup is set and the
+// given manifest is for a different backup. That can happen if the requested
+// backup can't be restored (e.g. it's an incremental backup) and the restore
+// fell back to an older one, or if several backups share the same start time.
+func checkRestoredBackup(manifest *mysqlctl.BackupManifest) error {
+	if restoreToBackup == "" || manifest.BackupName == restoreToBackup {
+		return nil
+	}
+	return fmt.Errorf("restored backup %v instead of the requested --restore_to_backup %v", manifest.BackupName, restoreToBackup)
+}
+
 func resetReplication(ctx context.Context, pos replication.Position, mysqld mysqlctl.MysqlDaemon) error {
 	if err := mysqld.StopReplication(ctx, nil); err != nil {
 		return vterrors.Wrap(err, "failed to stop replication")
@@ -654,6 +864,9 @@ func startReplication(ctx context.Context, mysqld mysqlctl.MysqlDaemon, topoServ
 	}
 	// TODO(enisoc): Support replicating from another replica, preferably in the
 	//   same cell, preferably rdonly, to reduce load on the primary.
+	if err := checkReplicationSourceAllowed(si.PrimaryAlias); err != nil {
+		return err
+	}
 	ti, err := topoServer.GetTablet(ctx, si.PrimaryAlias)
 	if err != nil {
 		return vterrors.Wrapf(err, "Cannot read primary tablet %v", si.PrimaryAlias)
@@ -666,6 +879,36 @@ func startReplication(ctx context.Context, mysqld mysqlctl.MysqlDaemon, topoServ
 	return nil
 }
 
+// validateForbiddenSourceCells makes sure that each of the cells given with
+// --forbidden_source_cells exists in the topology, so that a typo does not
+// silently leave a cell allowed.
+func validateForbiddenSourceCells(ctx context.Context, topoServer *topo.Server) error {
+	if len(forbiddenSourceCells) == 0 {
+		return nil
+	}
+	cells, err := topoServer.GetCellInfoNames(ctx)
+	if err != nil {
+		return fmt.Errorf("can't get cells to validate --forbidden_source_cells: %w", err)
+	}
+	for _, cell := range forbiddenSourceCells {
+		if !slices.Contains(cells, cell) {
+			return fmt.Errorf("invalid --forbidden_source_cells: cell %q does not exist (known cells: %v)", cell, cells)
+		}
+	}
+	return nil
+}
+
+// checkReplicationSourceAllowed returns an error if the given tablet, which
+// we want to replicate from, is in one of the cells given with
+// --forbidden_source_cells.
+func checkReplicationSourceAllowed(alias *topodatapb.TabletAlias) error {
+	if slices.Contains(forbiddenSourceCells, alias.GetCell()) {
+		return fmt.Errorf("can't replicate from tablet %v: cell %v is in --forbidden_source_cells %v",
+			topoproto.TabletAliasString(alias), alias.GetCell(), forbiddenSourceCells)
+	}
+	return nil
+}
+
 func getPrimaryPosition(ctx context.Context, tmc tmclient.TabletManagerClient, ts *topo.Server) (replication.Position, error) {
 	si, err := ts.GetShard(ctx, initKeyspace, initShard)
 	if err != nil {
@@ -715,19 +958,144 @@ func retryOnError(ctx context.Context, fn func() error) error {
 	}
 }
 
-func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string) error {
+// waitForKeepAlive waits for the --keep-alive-timeout, if one is set, or until
+// the context expires. operation is what was successful, for logging.
+func waitForKeepAlive(ctx context.Context, operation string) {
+	if keepAliveTimeout <= 0 {
+		return
+	}
+	log.Infof("%s was successful, waiting %s before exiting (or until context expires).", operation, keepAliveTimeout)
+	select {
+	case <-time.After(keepAliveTimeout):
+	case <-ctx.Done():
+	}
+}
+
+// keepDataAfter returns whether mysqld and its data should be left in place
+// after takeBackup returned the given error. That is after a successful
+// restore in restore-only mode, so that the restored data can be used, or
+// after a failure with --keep_data_on_failure, for debugging.
+func keepDataAfter(err error) bool {
+	if err != nil {
+		return keepDataOnFailure
+	}
+	return restoreOnlyMode()
+}
+
+// removeTabletDir removes the temporary tablet directory once takeBackup
+// returned the given error, unless it is being kept.
+func removeTabletDir(tabletDir string, err error) {
+	if keepDataAfter(err) {
+		log.Infof("Keeping temporary tablet directory: %v", tabletDir)
+		return
+	}
+	log.Infof("Removing temporary tablet directory: %v", tabletDir)
+	if err := os.RemoveAll(tabletDir); err != nil {
+		log.Warningf("Failed to remove temporary tablet directory: %v", err)
+	}
+}
+
+// shutdownMysqld shuts down mysqld once takeBackup returned the given error,
+// unless it is being left running.
+func shutdownMysqld(ctx context.Context, mysqld mysqlctl.MysqlDaemon, mycnf *mysqlctl.Mycnf, err error) {
+	if keepDataAfter(err) {
+		log.Infof("Leaving mysqld running, with its data directory in %v", mycnf.DataDir)
+		return
+	}
+	mysqlShutdownCtx, mysqlShutdownCancel := context.WithTimeout(ctx, mysqlShutdownTimeout+10*time.Second)
+	defer mysqlShutdownCancel()
+	if err := mysqld.Shutdown(mysqlShutdownCtx, mycnf, false, mysqlShutdownTimeout); err != nil {
+		log.Errorf("failed to shutdown mysqld: %v", err)
+	}
+}
+
+// printBackups writes a table describing each backup in backupDir, as read
+// from its MANIFEST, to out. Backups whose MANIFEST can't be read are
+// still listed, along with the error.
+func printBackups(ctx context.Context, out io.Writer, backupStorage backupstorage.BackupStorage, backupDir string) error {
+	backups, err := backupStorage.ListBackups(ctx, backupDir)
+	if err != nil {
+		return fmt.Errorf("can't list backups: %v", err)
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTIME\tENGINE\tINCREMENTAL\tPOSITION")
+	for _, backup := range backups {
+		manifest, err := mysqlctl.GetBackupManifest(ctx, backup)
+		if err != nil {
+			// Use Error() as vterrors may format the error over several lines.
+			fmt.Fprintf(w, "%s\t\t\t\terror: %s\n", backup.Name(), err.Error())
+			continue
+		}
+		engine := manifest.BackupMethod
+		if engine == "" {
+			// Only the builtin engine ever left the backup method empty.
+			engine = "builtin"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", backup.Name(), manifest.BackupTime, engine, manifest.Incremental,
+			replication.EncodePosition(manifest.Position))
+	}
+	return w.Flush()
+}
+
+// verifyBackup checks that the backup with the given name can be found in the
+// backup storage, that its MANIFEST can be read, and that it was taken at the
+// given replication position. It does not restore the backup.
+func verifyBackup(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir, backupName string, pos replication.Position) error {
+	backups, err := backupStorage.ListBackups(ctx, backupDir)
+	if err != nil {
+		return fmt.Errorf("can't list backups: %v", err)
+	}
+	var backup backupstorage.BackupHandle
+	for _, bh := range backups {
+		if bh.Name() == backupName {
+			backup = bh
+			break
+		}
+	}
+	if backup == nil {
+		return fmt.Errorf("backup not found in %v", backupDir)
+	}
+	manifest, err := mysqlctl.GetBackupManifest(ctx, backup)
+	if err != nil {
+		// The error already says that the MANIFEST can't be read. Use %s as
+		// vterrors may format the error over several lines with %v.
+		return fmt.Errorf("%s", err)
+	}
+	if !manifest.Position.Equal(pos) {
+		return fmt.Errorf("MANIFEST has position %v, expected %v", replication.EncodePosition(manifest.Position), replication.EncodePosition(pos))
+	}
+	return nil
+}
+
+// pruneResult summarizes what a pruneBackups run did, so that partial
+// progress is reported even when it returns an error.
+type pruneResult struct {
+	// removed are the names of the backups that were removed.
+	removed []string
+	// skipped are the names of the backups that could not be removed within
+	// the remove_backup_timeout, and will be retried on the next run.
+	skipped []string
+}
+
+func (pr pruneResult) String() string {
+	return fmt.Sprintf("removed %d backup(s) [%s], skipped %d backup(s) that timed out [%s]",
+		len(pr.removed), strings.Join(pr.removed, ","), len(pr.skipped), strings.Join(pr.skipped, ","))
+}
+
+func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string) (pruneResult, error) {
+	var result pruneResult
 	if minRetentionTime == 0 {
 		log.Info("Pruning of old backups is disabled.")
-		return nil
+		return result, nil
 	}
 	backups, err := backupStorage.ListBackups(ctx, backupDir)
 	if err != nil {
-		return fmt.Errorf("can't list backups: %v", err)
+		return result, fmt.Errorf("can't list backups: %v", err)
 	}
 	numBackups := len(backups)
 	if numBackups <= minRetentionCount {
 		log.Infof("Found %v backups. Not pruning any since this is within the min_retention_count of %v.", numBackups, minRetentionCount)
-		return nil
+		return result, nil
 	}
 	// We have more than the minimum retention count, so we could afford to
 	// prune some. See if any are beyond the minimum retention time.
@@ -735,7 +1103,7 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 	for _, backup := range backups {
 		backupTime, err := parseBackupTime(backup.Name())
 		if err != nil {
-			return err
+			return result, err
 		}
 		if time.Since(backupTime) < minRetentionTime {
 			// The oldest remaining backup is not old enough to prune.
@@ -744,9 +1112,17 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 		}
 		// Remove the backup.
 		log.Infof("Removing old backup %v from %v, since it's older than min_retention_time of %v", backup.Name(), backupDir, minRetentionTime)
-		if err := backupStorage.RemoveBackup(ctx, backupDir, backup.Name()); err != nil {
-			return fmt.Errorf("couldn't remove backup %v from %v: %v", backup.Name(), backupDir, err)
+		if err := removeBackup(ctx, backupStorage, backupDir, backup.Name()); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				// Only this backup timed out, so move on to the others rather
+				// than letting it block all pruning.
+				log.Warningf("Timed out removing backup %v from %v after %v, skipping it: %v", backup.Name(), backupDir, removeBackupTimeout, err)
+				result.skipped = append(result.skipped, backup.Name())
+				continue
+			}
+			return result, fmt.Errorf("couldn't remove backup %v from %v: %v", backup.Name(), backupDir, err)
 		}
+		result.removed = append(result.removed, backup.Name())
 		// We successfully removed one backup. Can we afford to prune any more?
 		numBackups--
 		if numBackups == minRetentionCount {
@@ -754,14 +1130,32 @@ func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage
 			break
 		}
 	}
-	return nil
+	return result, nil
+}
+
+// removeBackup removes a single backup, limited to the remove_backup_timeout
+// if one is set. Storage implementations do not consistently return the
+// context's error when it expires, so a context.DeadlineExceeded is returned
+// whenever the timeout was reached.
+func removeBackup(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir, name string) error {
+	if removeBackupTimeout <= 0 {
+		return backupStorage.RemoveBackup(ctx, backupDir, name)
+	}
+	removeCtx, cancel := context.WithTimeout(ctx, removeBackupTimeout)
+	defer cancel()
+	err := backupStorage.RemoveBackup(removeCtx, backupDir, name)
+	if err != nil && removeCtx.Err() == context.DeadlineExceeded && !errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return err
 }
 
 func parseBackupTime(name string) (time.Time, error) {
-	// Backup names are formatted as "date.time.tablet-alias".
+	// Backup names are formatted as "date.time.tablet-alias", with an
+	// optional fourth "label" component.
 	parts := strings.Split(name, ".")
-	if len(parts) != 3 {
-		return time.Time{}, fmt.Errorf("backup name not in expected format (date.time.tablet-alias): %v", name)
+	if len(parts) != 3 && len(parts) != 4 {
+		return time.Time{}, fmt.Errorf("backup name not in expected format (date.time.tablet-alias[.label]): %v", name)
 	}
 	backupTime, err := time.Parse(mysqlctl.BackupTimestampFormat, fmt.Sprintf("%s.%s", parts[0], parts[1]))
 	if err != nil {
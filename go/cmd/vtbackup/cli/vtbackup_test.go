@@ -0,0 +1,620 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// newFakeBackup returns a backup handle with the given name. If manifest is
+// nil the backup is incomplete, i.e. its MANIFEST can't be read.
+func newFakeBackup(t *testing.T, name string, manifest *mysqlctl.BackupManifest) *mysqlctl.FakeBackupHandle {
+	t.Helper()
+	bh := &mysqlctl.FakeBackupHandle{NameV: name}
+	bh.ReadFileReturnF = func(ctx context.Context, filename string) (io.ReadCloser, error) {
+		if manifest == nil {
+			return nil, fmt.Errorf("no MANIFEST in %v", name)
+		}
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return bh
+}
+
+func newFakeBackupStorage(backups ...backupstorage.BackupHandle) *mysqlctl.FakeBackupStorage {
+	return &mysqlctl.FakeBackupStorage{
+		ListBackupsReturn: mysqlctl.FakeBackupStorageListBackupsReturn{BackupHandles: backups},
+	}
+}
+
+func TestSetRestoreTarget(t *testing.T) {
+	ctx := context.Background()
+	const name = "2024-01-02.030405.zone1-0000000100"
+	complete := newFakeBackup(t, name, &mysqlctl.BackupManifest{BackupName: name})
+	incomplete := newFakeBackup(t, name, nil)
+
+	defer func(old string) { restoreToBackup = old }(restoreToBackup)
+
+	tests := []struct {
+		name          string
+		backup        string
+		storage       *mysqlctl.FakeBackupStorage
+		wantStartTime string
+		wantErr       string
+	}{
+		{
+			name:    "not in restore-only mode",
+			storage: newFakeBackupStorage(complete),
+		},
+		{
+			name:          "complete backup",
+			backup:        name,
+			storage:       newFakeBackupStorage(complete),
+			wantStartTime: "2024-01-02T03:04:05Z",
+		},
+		{
+			name:    "backup not found",
+			backup:  "2024-01-02.030406.zone1-0000000100",
+			storage: newFakeBackupStorage(complete),
+			wantErr: "backup 2024-01-02.030406.zone1-0000000100 not found in ks/0",
+		},
+		{
+			name:    "incomplete backup",
+			backup:  name,
+			storage: newFakeBackupStorage(incomplete),
+			wantErr: "can't restore backup " + name,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreToBackup = tt.backup
+			params := &mysqlctl.RestoreParams{}
+			err := setRestoreTarget(ctx, tt.storage, "ks/0", params)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantStartTime == "" {
+				assert.True(t, params.StartTime.IsZero())
+				return
+			}
+			assert.Equal(t, tt.wantStartTime, mysqlctl.FormatRFC3339(params.StartTime))
+		})
+	}
+}
+
+func TestCheckRestoredBackup(t *testing.T) {
+	const name = "2024-01-02.030405.zone1-0000000100"
+	defer func(old string) { restoreToBackup = old }(restoreToBackup)
+
+	restoreToBackup = ""
+	assert.NoError(t, checkRestoredBackup(&mysqlctl.BackupManifest{BackupName: "2024-01-01.030405.zone1-0000000100"}))
+
+	restoreToBackup = name
+	assert.NoError(t, checkRestoredBackup(&mysqlctl.BackupManifest{BackupName: name}))
+	err := checkRestoredBackup(&mysqlctl.BackupManifest{BackupName: "2024-01-01.030405.zone1-0000000100"})
+	assert.EqualError(t, err, "restored backup 2024-01-01.030405.zone1-0000000100 instead of the requested --restore_to_backup "+name)
+}
+
+func TestForbiddenSourceCells(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	defer func(old []string) { forbiddenSourceCells = old }(forbiddenSourceCells)
+
+	forbiddenSourceCells = nil
+	require.NoError(t, validateForbiddenSourceCells(ctx, ts))
+	require.NoError(t, checkReplicationSourceAllowed(&topodatapb.TabletAlias{Cell: "zone1", Uid: 100}))
+
+	forbiddenSourceCells = []string{"zone2"}
+	require.NoError(t, validateForbiddenSourceCells(ctx, ts))
+	require.NoError(t, checkReplicationSourceAllowed(&topodatapb.TabletAlias{Cell: "zone1", Uid: 100}))
+	err := checkReplicationSourceAllowed(&topodatapb.TabletAlias{Cell: "zone2", Uid: 200})
+	require.EqualError(t, err, "can't replicate from tablet zone2-0000000200: cell zone2 is in --forbidden_source_cells [zone2]")
+
+	forbiddenSourceCells = []string{"zone2", "zone3"}
+	err = validateForbiddenSourceCells(ctx, ts)
+	require.ErrorContains(t, err, `invalid --forbidden_source_cells: cell "zone3" does not exist`)
+}
+
+// backupName returns the name of a backup taken the given duration ago.
+func backupName(ago time.Duration) string {
+	return time.Now().Add(-ago).UTC().Format(mysqlctl.BackupTimestampFormat) + ".zone1-0000000100"
+}
+
+func TestShouldBackupIgnoresMinBackupAge(t *testing.T) {
+	ctx := context.Background()
+	defer func(interval, minAge time.Duration) {
+		minBackupInterval, minBackupAgeBeforeRestore = interval, minAge
+	}(minBackupInterval, minBackupAgeBeforeRestore)
+
+	// The most recent backup is too recent to restore from, but it must still
+	// count as the last backup when deciding whether a new one is needed.
+	name := backupName(time.Minute)
+	storage := newFakeBackupStorage(newFakeBackup(t, name, &mysqlctl.BackupManifest{BackupName: name}))
+	minBackupAgeBeforeRestore = time.Hour
+
+	minBackupInterval = 10 * time.Minute
+	ok, err := shouldBackup(ctx, nil, storage, "ks/0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	minBackupInterval = 30 * time.Second
+	ok, err = shouldBackup(ctx, nil, storage, "ks/0")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSetMinBackupAge(t *testing.T) {
+	defer func(minAge time.Duration, backup string) {
+		minBackupAgeBeforeRestore, restoreToBackup = minAge, backup
+	}(minBackupAgeBeforeRestore, restoreToBackup)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	minBackupAgeBeforeRestore = 0
+	params := &mysqlctl.RestoreParams{}
+	setMinBackupAge(params, now)
+	assert.True(t, params.StartTime.IsZero())
+
+	minBackupAgeBeforeRestore = time.Hour
+	setMinBackupAge(params, now)
+	assert.Equal(t, now.Add(-time.Hour), params.StartTime)
+
+	// The backup to restore is given in restore-only mode.
+	restoreToBackup = "2024-01-02.030405.zone1-0000000100"
+	params = &mysqlctl.RestoreParams{}
+	setMinBackupAge(params, now)
+	assert.True(t, params.StartTime.IsZero())
+}
+
+// slowBackupStorage is a backup storage where removing any of the slow
+// backups blocks until the context is done.
+type slowBackupStorage struct {
+	*mysqlctl.FakeBackupStorage
+	slow []string
+}
+
+func (sbs *slowBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
+	if err := sbs.FakeBackupStorage.RemoveBackup(ctx, dir, name); err != nil {
+		return err
+	}
+	if slices.Contains(sbs.slow, name) {
+		<-ctx.Done()
+		// Like some storage implementations, don't return the context's error.
+		return errors.New("request canceled")
+	}
+	return nil
+}
+
+func TestPruneBackups(t *testing.T) {
+	defer func(retentionTime time.Duration, retentionCount int, timeout time.Duration) {
+		minRetentionTime, minRetentionCount, removeBackupTimeout = retentionTime, retentionCount, timeout
+	}(minRetentionTime, minRetentionCount, removeBackupTimeout)
+	minRetentionTime = time.Hour
+	minRetentionCount = 1
+	removeBackupTimeout = 10 * time.Millisecond
+
+	oldest, older, recent := backupName(3*time.Hour), backupName(2*time.Hour), backupName(time.Minute)
+	newStorage := func() *slowBackupStorage {
+		return &slowBackupStorage{
+			FakeBackupStorage: newFakeBackupStorage(
+				newFakeBackup(t, oldest, nil),
+				newFakeBackup(t, older, nil),
+				newFakeBackup(t, recent, nil),
+			),
+			slow: []string{oldest},
+		}
+	}
+
+	// The backup that times out is skipped and the others are still pruned.
+	storage := newStorage()
+	pruned, err := pruneBackups(context.Background(), storage, "ks/0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{older}, pruned.removed)
+	assert.Equal(t, []string{oldest}, pruned.skipped)
+	assert.Len(t, storage.RemoveBackupCalls, 2)
+	assert.Equal(t, fmt.Sprintf("removed 1 backup(s) [%s], skipped 1 backup(s) that timed out [%s]", older, oldest), pruned.String())
+
+	// Without a timeout, an error removing a backup stops the pruning, and
+	// the progress made so far is still returned.
+	removeBackupTimeout = 0
+	storage = newStorage()
+	storage.slow = nil
+	storage.RemoveBackupReturn = errors.New("permission denied")
+	pruned, err = pruneBackups(context.Background(), storage, "ks/0")
+	require.EqualError(t, err, fmt.Sprintf("couldn't remove backup %s from ks/0: permission denied", oldest))
+	assert.Empty(t, pruned.removed)
+	assert.Empty(t, pruned.skipped)
+
+	// When the whole run is out of time, the pruning stops rather than
+	// skipping each of the remaining backups.
+	removeBackupTimeout = time.Hour
+	storage = newStorage()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pruneBackups(ctx, storage, "ks/0")
+	require.ErrorContains(t, err, fmt.Sprintf("couldn't remove backup %s from ks/0", oldest))
+	assert.Len(t, storage.RemoveBackupCalls, 1)
+}
+
+func TestRemoveBackup(t *testing.T) {
+	defer func(timeout time.Duration) { removeBackupTimeout = timeout }(removeBackupTimeout)
+	const name = "2024-01-02.030405.zone1-0000000100"
+	storage := &slowBackupStorage{FakeBackupStorage: newFakeBackupStorage(), slow: []string{name}}
+
+	removeBackupTimeout = 10 * time.Millisecond
+	err := removeBackup(context.Background(), storage, "ks/0", name)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "context deadline exceeded: request canceled")
+
+	removeBackupTimeout = 0
+	storage.RemoveBackupReturn = errors.New("permission denied")
+	err = removeBackup(context.Background(), storage, "ks/0", name)
+	assert.EqualError(t, err, "permission denied")
+	assert.NotErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPrintBackups(t *testing.T) {
+	ctx := context.Background()
+	pos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615")
+	require.NoError(t, err)
+	const (
+		full        = "2024-01-02.030405.zone1-0000000100"
+		incremental = "2024-01-02.040405.zone1-0000000100"
+		broken      = "2024-01-02.050405.zone1-0000000100"
+	)
+	storage := newFakeBackupStorage(
+		newFakeBackup(t, full, &mysqlctl.BackupManifest{
+			BackupName: full,
+			BackupTime: "2024-01-02T03:04:05Z",
+			Position:   pos,
+		}),
+		newFakeBackup(t, incremental, &mysqlctl.BackupManifest{
+			BackupName:   incremental,
+			BackupMethod: "xtrabackup",
+			BackupTime:   "2024-01-02T04:04:05Z",
+			Incremental:  true,
+			Position:     pos,
+		}),
+		newFakeBackup(t, broken, nil),
+	)
+
+	var out strings.Builder
+	require.NoError(t, printBackups(ctx, &out, storage, "ks/0"))
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"NAME", "TIME", "ENGINE", "INCREMENTAL", "POSITION"}, strings.Fields(lines[0]))
+	// A backup without a backup method was taken by the builtin engine.
+	assert.Equal(t, []string{full, "2024-01-02T03:04:05Z", "builtin", "false", replication.EncodePosition(pos)}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{incremental, "2024-01-02T04:04:05Z", "xtrabackup", "true", replication.EncodePosition(pos)}, strings.Fields(lines[2]))
+	assert.True(t, strings.HasPrefix(lines[3], broken))
+	assert.Contains(t, lines[3], "error: can't read MANIFEST: no MANIFEST in "+broken)
+
+	storage.ListBackupsReturn.Err = errors.New("access denied")
+	err = printBackups(ctx, &out, storage, "ks/0")
+	assert.EqualError(t, err, "can't list backups: access denied")
+}
+
+func TestKeepData(t *testing.T) {
+	defer func(keep bool, backup string) {
+		keepDataOnFailure, restoreToBackup = keep, backup
+	}(keepDataOnFailure, restoreToBackup)
+	ctx := context.Background()
+	backupErr := errors.New("backup failed")
+
+	tests := []struct {
+		name        string
+		keep        bool
+		restoreOnly bool
+		err         error
+		wantKept    bool
+	}{
+		{
+			name: "success",
+			keep: true,
+		},
+		{
+			name: "failure",
+			err:  backupErr,
+		},
+		{
+			name:     "failure keeping data",
+			keep:     true,
+			err:      backupErr,
+			wantKept: true,
+		},
+		{
+			// The restored data is left behind to be used.
+			name:        "restore-only success",
+			restoreOnly: true,
+			wantKept:    true,
+		},
+		{
+			name:        "restore-only failure",
+			restoreOnly: true,
+			err:         backupErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepDataOnFailure = tt.keep
+			restoreToBackup = ""
+			if tt.restoreOnly {
+				restoreToBackup = "2024-01-02.030405.zone1-0000000100"
+			}
+			assert.Equal(t, tt.wantKept, keepDataAfter(tt.err))
+
+			tabletDir := filepath.Join(t.TempDir(), "vt_0000000100")
+			dataDir := filepath.Join(tabletDir, "data")
+			require.NoError(t, os.MkdirAll(dataDir, 0o755))
+			removeTabletDir(tabletDir, tt.err)
+			_, err := os.Stat(dataDir)
+			if tt.wantKept {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			}
+
+			mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+			shutdownMysqld(ctx, mysqld, &mysqlctl.Mycnf{DataDir: dataDir}, tt.err)
+			assert.Equal(t, tt.wantKept, mysqld.Running)
+		})
+	}
+}
+
+func TestWaitForKeepAlive(t *testing.T) {
+	defer func(timeout time.Duration) { keepAliveTimeout = timeout }(keepAliveTimeout)
+
+	keepAliveTimeout = 50 * time.Millisecond
+	start := time.Now()
+	waitForKeepAlive(context.Background(), "Restore")
+	assert.GreaterOrEqual(t, time.Since(start), keepAliveTimeout)
+
+	// The wait ends early when the context expires.
+	keepAliveTimeout = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	waitForKeepAlive(ctx, "Restore")
+	assert.Less(t, time.Since(start), keepAliveTimeout)
+}
+
+func TestVerifyBackup(t *testing.T) {
+	ctx := context.Background()
+	pos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-615")
+	require.NoError(t, err)
+	otherPos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:1-614")
+	require.NoError(t, err)
+	const (
+		name       = "2024-01-02.030405.zone1-0000000100"
+		incomplete = "2024-01-02.040405.zone1-0000000100"
+	)
+	storage := newFakeBackupStorage(
+		newFakeBackup(t, name, &mysqlctl.BackupManifest{BackupName: name, Position: pos}),
+		newFakeBackup(t, incomplete, nil),
+	)
+
+	tests := []struct {
+		name    string
+		backup  string
+		pos     replication.Position
+		listErr error
+		wantErr string
+	}{
+		{
+			name:   "verified",
+			backup: name,
+			pos:    pos,
+		},
+		{
+			name:    "wrong position",
+			backup:  name,
+			pos:     otherPos,
+			wantErr: fmt.Sprintf("MANIFEST has position %s, expected %s", replication.EncodePosition(pos), replication.EncodePosition(otherPos)),
+		},
+		{
+			name:    "no MANIFEST",
+			backup:  incomplete,
+			pos:     pos,
+			wantErr: "can't read MANIFEST: no MANIFEST in " + incomplete,
+		},
+		{
+			name:    "not found",
+			backup:  "2024-01-02.050405.zone1-0000000100",
+			pos:     pos,
+			wantErr: "backup not found in ks/0",
+		},
+		{
+			name:    "can't list backups",
+			backup:  name,
+			pos:     pos,
+			listErr: errors.New("access denied"),
+			wantErr: "can't list backups: access denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage.ListBackupsReturn.Err = tt.listErr
+			err := verifyBackup(ctx, storage, "ks/0", tt.backup, tt.pos)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseBackupTime(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		backup  string
+		wantErr string
+	}{
+		{
+			name:   "no label",
+			backup: "2024-01-02.030405.zone1-0000000100",
+		},
+		{
+			name:   "label",
+			backup: "2024-01-02.030405.zone1-0000000100.pre-upgrade",
+		},
+		{
+			name:   "sanitized label",
+			backup: mysqlctl.BackupName(want, "zone1-0000000100", "v20.0.1 hot/fix"),
+		},
+		{
+			name:    "too few parts",
+			backup:  "2024-01-02.030405",
+			wantErr: "backup name not in expected format (date.time.tablet-alias[.label]): 2024-01-02.030405",
+		},
+		{
+			name:    "too many parts",
+			backup:  "2024-01-02.030405.zone1-0000000100.label.extra",
+			wantErr: "backup name not in expected format (date.time.tablet-alias[.label]): 2024-01-02.030405.zone1-0000000100.label.extra",
+		},
+		{
+			name:    "bad timestamp",
+			backup:  "2024-01-02.0304.zone1-0000000100.label",
+			wantErr: `can't parse timestamp from backup "2024-01-02.0304.zone1-0000000100.label"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backupTime, err := parseBackupTime(tt.backup)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, want, backupTime)
+		})
+	}
+}
+
+func TestExitCodeOnNoop(t *testing.T) {
+	defer func(code int) { exitCodeOnNoop = code }(exitCodeOnNoop)
+
+	for _, code := range []int{0, 2, 255} {
+		exitCodeOnNoop = code
+		assert.NoError(t, validateExitCodeOnNoop(), "exit code %d", code)
+		assert.Equal(t, 0, noopExitCode(true), "exit code %d", code)
+		assert.Equal(t, code, noopExitCode(false), "exit code %d", code)
+	}
+	for _, code := range []int{-1, 1, 256} {
+		exitCodeOnNoop = code
+		assert.EqualError(t, validateExitCodeOnNoop(), "--exit_code_on_noop must be between 0 and 255, and not 1", "exit code %d", code)
+	}
+}
+
+func TestValidateInitialBackupCompressionEngine(t *testing.T) {
+	defer func(initial bool, engine string) {
+		initialBackup, initialBackupCompressionEngine = initial, engine
+	}(initialBackup, initialBackupCompressionEngine)
+
+	// The engine is only used, and so only checked, for an initial backup.
+	initialBackup = false
+	initialBackupCompressionEngine = "gzip"
+	assert.NoError(t, validateInitialBackupCompressionEngine())
+
+	initialBackup = true
+	assert.ErrorContains(t, validateInitialBackupCompressionEngine(), `invalid --initial_backup_compression_engine: unsupported compression engine "gzip"`)
+
+	for _, engine := range []string{"", mysqlctl.ZstdCompressor} {
+		initialBackupCompressionEngine = engine
+		assert.NoError(t, validateInitialBackupCompressionEngine(), "engine %q", engine)
+	}
+}
+
+func TestCheckCaughtUp(t *testing.T) {
+	defer func(require bool) { requireCaughtUp = require }(requireCaughtUp)
+	position := func(gtids string) replication.Position {
+		pos, err := replication.DecodePosition("MySQL56/16b1039f-22b6-11ed-b765-0a43f95f28a3:" + gtids)
+		require.NoError(t, err)
+		return pos
+	}
+	restorePos, partialPos, primaryPos := position("1-100"), position("1-150"), position("1-200")
+
+	tests := []struct {
+		name            string
+		pos             replication.Position
+		requireCaughtUp bool
+		wantErr         string
+	}{
+		{
+			name: "caught up",
+			pos:  primaryPos,
+		},
+		{
+			name:            "caught up when required",
+			pos:             primaryPos,
+			requireCaughtUp: true,
+		},
+		{
+			name:    "no progress",
+			pos:     restorePos,
+			wantErr: fmt.Sprintf("not taking backup: replication did not make any progress from restore point: %v", restorePos),
+		},
+		{
+			name: "partial progress",
+			pos:  partialPos,
+		},
+		{
+			name:            "partial progress when required",
+			pos:             partialPos,
+			requireCaughtUp: true,
+			wantErr:         fmt.Sprintf("not taking backup: replication caught up to %v but didn't make it to the goal of %v, and --require_caught_up is set", partialPos, primaryPos),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requireCaughtUp = tt.requireCaughtUp
+			err := checkCaughtUp(tt.pos, restorePos, primaryPos)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -12,11 +12,14 @@ import (
 )
 
 var CompleteOptions = struct {
-	KeepData         bool
-	KeepRoutingRules bool
-	RenameTables     bool
-	DryRun           bool
-	Shards           []string
+	KeepData            bool
+	KeepRoutingRules    bool
+	KeepSourceVSchema   bool
+	RenameTables        bool
+	DryRun              bool
+	Shards              []string
+	PreDropSourceSQL    []string
+	IgnorePreDropErrors bool
 }{}
 
 func GetCompleteCommand(opts *SubCommandsOpts) *cobra.Command {
@@ -41,12 +44,15 @@ func commandComplete(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.MoveTablesCompleteRequest{
-		Workflow:         BaseOptions.Workflow,
-		TargetKeyspace:   BaseOptions.TargetKeyspace,
-		KeepData:         CompleteOptions.KeepData,
-		KeepRoutingRules: CompleteOptions.KeepRoutingRules,
-		RenameTables:     CompleteOptions.RenameTables,
-		DryRun:           CompleteOptions.DryRun,
+		Workflow:            BaseOptions.Workflow,
+		TargetKeyspace:      BaseOptions.TargetKeyspace,
+		KeepData:            CompleteOptions.KeepData,
+		KeepRoutingRules:    CompleteOptions.KeepRoutingRules,
+		KeepSourceVschema:   CompleteOptions.KeepSourceVSchema,
+		RenameTables:        CompleteOptions.RenameTables,
+		DryRun:              CompleteOptions.DryRun,
+		PreDropSourceSql:    CompleteOptions.PreDropSourceSQL,
+		IgnorePreDropErrors: CompleteOptions.IgnorePreDropErrors,
 	}
 	resp, err := GetClient().MoveTablesComplete(GetCommandCtx(), req)
 	if err != nil {
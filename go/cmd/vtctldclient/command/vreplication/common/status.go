@@ -27,7 +27,8 @@ import (
 )
 
 var StatusOptions = struct {
-	Shards []string
+	Shards         []string
+	ExactRowCounts bool
 }{}
 
 func GetStatusCommand(opts *SubCommandsOpts) *cobra.Command {
@@ -40,6 +41,7 @@ func GetStatusCommand(opts *SubCommandsOpts) *cobra.Command {
 		Args:                  cobra.NoArgs,
 		RunE:                  commandStatus,
 	}
+	cmd.Flags().BoolVar(&StatusOptions.ExactRowCounts, "exact-row-counts", false, "Count the rows in each table being copied, rather than using the table statistics estimates. This is accurate but can be slow for large tables.")
 	return cmd
 }
 
@@ -51,9 +53,10 @@ func commandStatus(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.WorkflowStatusRequest{
-		Keyspace: BaseOptions.TargetKeyspace,
-		Workflow: BaseOptions.Workflow,
-		Shards:   StatusOptions.Shards,
+		Keyspace:       BaseOptions.TargetKeyspace,
+		Workflow:       BaseOptions.Workflow,
+		Shards:         StatusOptions.Shards,
+		ExactRowCounts: StatusOptions.ExactRowCounts,
 	}
 	resp, err := GetClient().WorkflowStatus(GetCommandCtx(), req)
 	if err != nil {
@@ -86,6 +86,13 @@ func commandSwitchTraffic(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if SwitchTrafficOptions.CreateReverseWorkflowOnly {
+		if cmd.Flags().Lookup("enable-reverse-replication").Changed && SwitchTrafficOptions.EnableReverseReplication {
+			return fmt.Errorf("--create-reverse-workflow-only cannot be used with --enable-reverse-replication")
+		}
+		SwitchTrafficOptions.EnableReverseReplication = false
+	}
+
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.WorkflowSwitchTrafficRequest{
@@ -97,6 +104,9 @@ func commandSwitchTraffic(cmd *cobra.Command, args []string) error {
 		Timeout:                   protoutil.DurationToProto(SwitchTrafficOptions.Timeout),
 		DryRun:                    SwitchTrafficOptions.DryRun,
 		EnableReverseReplication:  SwitchTrafficOptions.EnableReverseReplication,
+		CreateReverseWorkflowOnly: SwitchTrafficOptions.CreateReverseWorkflowOnly,
+		DrainTimeout:              protoutil.DurationToProto(SwitchTrafficOptions.DrainTimeout),
+		IdempotencyKey:            SwitchTrafficOptions.IdempotencyKey,
 		InitializeTargetSequences: SwitchTrafficOptions.InitializeTargetSequences,
 		Direction:                 int32(SwitchTrafficOptions.Direction),
 	}
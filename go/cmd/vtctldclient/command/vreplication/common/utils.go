@@ -199,6 +199,14 @@ func OutputStatusResponse(resp *vtctldatapb.WorkflowStatusResponse, format strin
 		}
 		tout.WriteString("\nTraffic State: ")
 		tout.WriteString(resp.TrafficState)
+		if resp.OnDdl != "" {
+			tout.WriteString("\nOn DDL: ")
+			tout.WriteString(resp.OnDdl)
+		}
+		for _, warning := range resp.Warnings {
+			tout.WriteString("\nWarning: ")
+			tout.WriteString(warning)
+		}
 		output = tout.Bytes()
 	}
 	fmt.Println(string(output))
@@ -230,6 +238,9 @@ var SwitchTrafficOptions = struct {
 	Timeout                   time.Duration
 	MaxReplicationLagAllowed  time.Duration
 	EnableReverseReplication  bool
+	CreateReverseWorkflowOnly bool
+	DrainTimeout              time.Duration
+	IdempotencyKey            string
 	DryRun                    bool
 	Direction                 workflow.TrafficSwitchDirection
 	InitializeTargetSequences bool
@@ -242,6 +253,9 @@ func AddCommonSwitchTrafficFlags(cmd *cobra.Command, initializeTargetSequences b
 	cmd.Flags().DurationVar(&SwitchTrafficOptions.Timeout, "timeout", TimeoutDefault, "Specifies the maximum time to wait, in seconds, for VReplication to catch up on primary tablets. The traffic switch will be cancelled on timeout.")
 	cmd.Flags().DurationVar(&SwitchTrafficOptions.MaxReplicationLagAllowed, "max-replication-lag-allowed", MaxReplicationLagDefault, "Allow traffic to be switched only if VReplication lag is below this.")
 	cmd.Flags().BoolVar(&SwitchTrafficOptions.EnableReverseReplication, "enable-reverse-replication", true, "Setup replication going back to the original source keyspace to support rolling back the traffic cutover.")
+	cmd.Flags().BoolVar(&SwitchTrafficOptions.CreateReverseWorkflowOnly, "create-reverse-workflow-only", false, "Create the reverse workflow when switching writes but leave it stopped, so that it can be started later to support rolling back the traffic cutover. Implies --enable-reverse-replication=false.")
+	cmd.Flags().DurationVar(&SwitchTrafficOptions.DrainTimeout, "drain-timeout", 0, "When switching writes, wait up to this long after stopping writes for the transactions already in flight in the source database to finish. The traffic switch continues if they have not finished by then.")
+	cmd.Flags().StringVar(&SwitchTrafficOptions.IdempotencyKey, "idempotency-key", "", "Record this key on the workflow once traffic has been switched. A retried command with the same key will then return without switching traffic again.")
 	cmd.Flags().BoolVar(&SwitchTrafficOptions.DryRun, "dry-run", false, "Print the actions that would be taken and report any known errors that would have occurred.")
 	if initializeTargetSequences {
 		cmd.Flags().BoolVar(&SwitchTrafficOptions.InitializeTargetSequences, "initialize-target-sequences", false, "When moving tables from an unsharded keyspace to a sharded keyspace, initialize any sequences that are being used on the target when switching writes.")
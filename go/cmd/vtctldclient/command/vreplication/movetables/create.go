@@ -39,6 +39,12 @@ var (
 		SourceTimeZone      string
 		NoRoutingRules      bool
 		AtomicCopy          bool
+		SkipTablesNoPK      bool
+		FailOnTablesNoPK    bool
+		TableCreateDDL      map[string]string
+		IgnorePrevJournal   bool
+		AutoStartAfterCheck bool
+		PinSourcePositions  bool
 		WorkflowOptions     vtctldatapb.WorkflowOptions
 	}{}
 
@@ -78,6 +84,9 @@ var (
 			if err := checkAtomicCopyOptions(); err != nil {
 				return err
 			}
+			if createOptions.SkipTablesNoPK && createOptions.FailOnTablesNoPK {
+				return fmt.Errorf("cannot specify both --skip-tables-without-primary-key and --fail-on-tables-without-primary-key")
+			}
 
 			tenantId := createOptions.WorkflowOptions.GetTenantId()
 			if len(createOptions.WorkflowOptions.GetShards()) > 0 && tenantId == "" {
@@ -102,24 +111,30 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.MoveTablesCreateRequest{
-		Workflow:                  common.BaseOptions.Workflow,
-		TargetKeyspace:            common.BaseOptions.TargetKeyspace,
-		SourceKeyspace:            createOptions.SourceKeyspace,
-		SourceShards:              createOptions.SourceShards,
-		SourceTimeZone:            createOptions.SourceTimeZone,
-		Cells:                     common.CreateOptions.Cells,
-		TabletTypes:               common.CreateOptions.TabletTypes,
-		TabletSelectionPreference: tsp,
-		AllTables:                 createOptions.AllTables,
-		IncludeTables:             createOptions.IncludeTables,
-		ExcludeTables:             createOptions.ExcludeTables,
-		OnDdl:                     common.CreateOptions.OnDDL,
-		DeferSecondaryKeys:        common.CreateOptions.DeferSecondaryKeys,
-		AutoStart:                 common.CreateOptions.AutoStart,
-		StopAfterCopy:             common.CreateOptions.StopAfterCopy,
-		NoRoutingRules:            createOptions.NoRoutingRules,
-		AtomicCopy:                createOptions.AtomicCopy,
-		WorkflowOptions:           &createOptions.WorkflowOptions,
+		Workflow:                      common.BaseOptions.Workflow,
+		TargetKeyspace:                common.BaseOptions.TargetKeyspace,
+		SourceKeyspace:                createOptions.SourceKeyspace,
+		SourceShards:                  createOptions.SourceShards,
+		SourceTimeZone:                createOptions.SourceTimeZone,
+		Cells:                         common.CreateOptions.Cells,
+		TabletTypes:                   common.CreateOptions.TabletTypes,
+		TabletSelectionPreference:     tsp,
+		AllTables:                     createOptions.AllTables,
+		IncludeTables:                 createOptions.IncludeTables,
+		ExcludeTables:                 createOptions.ExcludeTables,
+		OnDdl:                         common.CreateOptions.OnDDL,
+		DeferSecondaryKeys:            common.CreateOptions.DeferSecondaryKeys,
+		AutoStart:                     common.CreateOptions.AutoStart,
+		StopAfterCopy:                 common.CreateOptions.StopAfterCopy,
+		NoRoutingRules:                createOptions.NoRoutingRules,
+		AtomicCopy:                    createOptions.AtomicCopy,
+		SkipTablesWithoutPrimaryKey:   createOptions.SkipTablesNoPK,
+		FailOnTablesWithoutPrimaryKey: createOptions.FailOnTablesNoPK,
+		TableCreateDdl:                createOptions.TableCreateDDL,
+		ForceIgnorePreviousJournal:    createOptions.IgnorePrevJournal,
+		AutoStartAfterSchemaCheck:     createOptions.AutoStartAfterCheck,
+		PinSourcePositions:            createOptions.PinSourcePositions,
+		WorkflowOptions:               &createOptions.WorkflowOptions,
 	}
 
 	resp, err := common.GetClient().MoveTablesCreate(common.GetCommandCtx(), req)
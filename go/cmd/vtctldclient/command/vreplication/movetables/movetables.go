@@ -47,9 +47,16 @@ func registerCommands(root *cobra.Command) {
 	create.Flags().StringSliceVar(&createOptions.ExcludeTables, "exclude-tables", nil, "Source tables to exclude from copying.")
 	create.Flags().BoolVar(&createOptions.NoRoutingRules, "no-routing-rules", false, "(Advanced) Do not create routing rules while creating the workflow. See the reference documentation for limitations if you use this flag.")
 	create.Flags().BoolVar(&createOptions.AtomicCopy, "atomic-copy", false, "(EXPERIMENTAL) A single copy phase is run for all tables from the source. Use this, for example, if your source keyspace has tables which use foreign key constraints.")
+	create.Flags().BoolVar(&createOptions.SkipTablesNoPK, "skip-tables-without-primary-key", false, "Exclude any of the selected tables that do not have a primary key on the source from the workflow.")
+	create.Flags().BoolVar(&createOptions.FailOnTablesNoPK, "fail-on-tables-without-primary-key", false, "Fail if any of the selected tables do not have a primary key on the source.")
+	create.Flags().StringToStringVar(&createOptions.TableCreateDDL, "table-create-ddl", nil, "Override how specific tables are created on the target, as a comma-separated list of table=mode pairs where mode is one of copy, copy:drop_constraint, or copy:drop_foreign_keys.")
+	create.Flags().BoolVar(&createOptions.IgnorePrevJournal, "force-ignore-previous-journal", false, "(Advanced) Create the workflow even if an entry from a previous run exists in the resharding journal on the source shards. Only use this if you know that the entry is stale.")
+	create.Flags().BoolVar(&createOptions.AutoStartAfterCheck, "auto-start-after-schema-check", false, "Compare the table schemas on the source and target after creating the workflow and only start it if they match, otherwise leave it stopped and report the differences. This takes precedence over --auto-start.")
+	create.Flags().BoolVar(&createOptions.PinSourcePositions, "pin-source-positions", false, "Capture the current position of every source shard once the workflow has been created and start each stream from its source shard's captured position, giving the workflow a well-defined and recorded baseline.")
 	create.Flags().StringVar(&createOptions.WorkflowOptions.TenantId, "tenant-id", "", "(EXPERIMENTAL: Multi-tenant migrations only) The tenant ID to use for the MoveTables workflow into a multi-tenant keyspace.")
 	create.Flags().BoolVar(&createOptions.WorkflowOptions.StripShardedAutoIncrement, "remove-sharded-auto-increment", true, "If moving the table(s) to a sharded keyspace, remove any auto_increment clauses when copying the schema to the target as sharded keyspaces should rely on either user/application generated values or Vitess sequences to ensure uniqueness.")
 	create.Flags().StringSliceVar(&createOptions.WorkflowOptions.Shards, "shards", nil, "(EXPERIMENTAL: Multi-tenant migrations only) Specify that vreplication streams should only be created on this subset of target shards. Warning: you should first ensure that all rows on the source route to the specified subset of target shards using your VIndex of choice or you could lose data during the migration.")
+	create.Flags().Int32Var(&createOptions.WorkflowOptions.CopyParallelInsertWorkers, "copy-parallel-insert-workers", 0, "The number of parallel workers to use for inserting rows on the target tablets during the copy phase. Lower values reduce the load on the source and target tablets. Defaults to the value of the target tablets' vreplication-parallel-insert-workers flag.")
 	base.AddCommand(create)
 
 	opts := &common.SubCommandsOpts{
@@ -80,8 +87,11 @@ func registerCommands(root *cobra.Command) {
 	complete := common.GetCompleteCommand(opts)
 	complete.Flags().BoolVar(&common.CompleteOptions.KeepData, "keep-data", false, "Keep the original source table data that was copied by the MoveTables workflow.")
 	complete.Flags().BoolVar(&common.CompleteOptions.KeepRoutingRules, "keep-routing-rules", false, "Keep the routing rules in place that direct table traffic from the source keyspace to the target keyspace of the MoveTables workflow.")
+	complete.Flags().BoolVar(&common.CompleteOptions.KeepSourceVSchema, "keep-source-vschema", false, "Keep the table definitions in the source keyspace's vschema. Cannot be used with --keep-routing-rules.")
 	complete.Flags().BoolVar(&common.CompleteOptions.RenameTables, "rename-tables", false, "Keep the original source table data that was copied by the MoveTables workflow, but rename each table to '_<tablename>_old'.")
 	complete.Flags().BoolVar(&common.CompleteOptions.DryRun, "dry-run", false, "Print the actions that would be taken and report any known errors that would have occurred.")
+	complete.Flags().StringArrayVar(&common.CompleteOptions.PreDropSourceSQL, "pre-drop-source-sql", nil, "SQL statement to execute on each source primary tablet right before the source tables are removed. May be specified multiple times.")
+	complete.Flags().BoolVar(&common.CompleteOptions.IgnorePreDropErrors, "ignore-pre-drop-errors", false, "Continue with the complete if any of the --pre-drop-source-sql statements fail.")
 	common.AddShardSubsetFlag(complete, &common.CompleteOptions.Shards)
 	base.AddCommand(complete)
 
@@ -27,9 +27,11 @@ import (
 
 var (
 	reshardCreateOptions = struct {
-		sourceShards   []string
-		targetShards   []string
-		skipSchemaCopy bool
+		sourceShards         []string
+		targetShards         []string
+		skipSchemaCopy       bool
+		validateSchemaOnSkip bool
+		allowSchemaDiff      bool
 	}{}
 
 	// reshardCreate makes a ReshardCreate gRPC call to a vtctld.
@@ -72,6 +74,8 @@ func commandReshardCreate(cmd *cobra.Command, args []string) error {
 		SourceShards:              reshardCreateOptions.sourceShards,
 		TargetShards:              reshardCreateOptions.targetShards,
 		SkipSchemaCopy:            reshardCreateOptions.skipSchemaCopy,
+		ValidateSchemaOnSkip:      reshardCreateOptions.validateSchemaOnSkip,
+		AllowSchemaDiff:           reshardCreateOptions.allowSchemaDiff,
 	}
 	resp, err := common.GetClient().ReshardCreate(common.GetCommandCtx(), req)
 	if err != nil {
@@ -88,5 +92,7 @@ func registerCreateCommand(root *cobra.Command) {
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.sourceShards, "source-shards", nil, "Source shards.")
 	reshardCreate.Flags().StringSliceVar(&reshardCreateOptions.targetShards, "target-shards", nil, "Target shards.")
 	reshardCreate.Flags().BoolVar(&reshardCreateOptions.skipSchemaCopy, "skip-schema-copy", false, "Skip copying the schema from the source shards to the target shards.")
+	reshardCreate.Flags().BoolVar(&reshardCreateOptions.validateSchemaOnSkip, "validate-schema-on-skip", false, "When --skip-schema-copy is used, check that the schema on the target shards matches the source shards and fail if it does not.")
+	reshardCreate.Flags().BoolVar(&reshardCreateOptions.allowSchemaDiff, "allow-schema-diff", false, "When the schema is copied to the target shards, log any differences that remain between the source and target schemas as warnings instead of failing.")
 	root.AddCommand(reshardCreate)
 }
@@ -32,6 +32,8 @@ var (
 	deleteOptions = struct {
 		KeepData         bool
 		KeepRoutingRules bool
+		KeepVDiffData    bool
+		KeepVDiffTables  []string
 	}{}
 
 	// delete makes a WorkflowDelete gRPC call to a vtctld.
@@ -54,6 +56,8 @@ func commandDelete(cmd *cobra.Command, args []string) error {
 		Workflow:         baseOptions.Workflow,
 		KeepData:         deleteOptions.KeepData,
 		KeepRoutingRules: deleteOptions.KeepRoutingRules,
+		KeepVdiffData:    deleteOptions.KeepVDiffData,
+		KeepVdiffTables:  deleteOptions.KeepVDiffTables,
 		Shards:           baseOptions.Shards,
 	}
 	resp, err := common.GetClient().WorkflowDelete(common.GetCommandCtx(), req)
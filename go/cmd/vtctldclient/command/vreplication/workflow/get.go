@@ -29,7 +29,9 @@ import (
 
 var (
 	getWorkflowsOptions = struct {
-		ShowAll bool
+		ShowAll           bool
+		SummaryOnly       bool
+		IncludeReversible bool
 	}{}
 	// GetWorkflows makes a GetWorkflows gRPC call to a vtctld.
 	getWorkflows = &cobra.Command{
@@ -47,9 +49,11 @@ func commandGetWorkflows(cmd *cobra.Command, args []string) error {
 	ks := cmd.Flags().Arg(0)
 
 	resp, err := common.GetClient().GetWorkflows(common.GetCommandCtx(), &vtctldatapb.GetWorkflowsRequest{
-		Keyspace:    ks,
-		ActiveOnly:  !getWorkflowsOptions.ShowAll,
-		IncludeLogs: workflowShowOptions.IncludeLogs,
+		Keyspace:          ks,
+		ActiveOnly:        !getWorkflowsOptions.ShowAll,
+		IncludeLogs:       workflowShowOptions.IncludeLogs,
+		SummaryOnly:       getWorkflowsOptions.SummaryOnly,
+		IncludeReversible: getWorkflowsOptions.IncludeReversible,
 	})
 
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -53,13 +54,18 @@ var (
 )
 
 func commandShow(cmd *cobra.Command, args []string) error {
+	streamStates, err := parseStreamStates(workflowShowOptions.StreamStates)
+	if err != nil {
+		return err
+	}
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.GetWorkflowsRequest{
-		Keyspace:    baseOptions.Keyspace,
-		Workflow:    baseOptions.Workflow,
-		IncludeLogs: workflowShowOptions.IncludeLogs,
-		Shards:      baseOptions.Shards,
+		Keyspace:     baseOptions.Keyspace,
+		Workflow:     baseOptions.Workflow,
+		IncludeLogs:  workflowShowOptions.IncludeLogs,
+		Shards:       baseOptions.Shards,
+		StreamStates: streamStates,
 	}
 	resp, err := common.GetClient().GetWorkflows(common.GetCommandCtx(), req)
 	if err != nil {
@@ -84,3 +90,23 @@ func commandShow(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseStreamStates converts the given state names, ignoring case, into
+// VReplicationWorkflowStates.
+func parseStreamStates(names []string) ([]binlogdatapb.VReplicationWorkflowState, error) {
+	states := make([]binlogdatapb.VReplicationWorkflowState, 0, len(names))
+	for _, name := range names {
+		found := false
+		for value, stateName := range binlogdatapb.VReplicationWorkflowState_name {
+			if strings.EqualFold(name, stateName) {
+				states = append(states, binlogdatapb.VReplicationWorkflowState(value))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid stream state: %s", name)
+		}
+	}
+	return states, nil
+}
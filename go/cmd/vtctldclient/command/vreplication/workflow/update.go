@@ -39,6 +39,7 @@ var (
 		TabletTypes                  []topodatapb.TabletType
 		TabletTypesInPreferenceOrder bool
 		OnDDL                        string
+		StreamStates                 []string
 	}{}
 
 	// update makes a WorkflowUpdate gRPC call to a vtctld.
@@ -103,6 +104,11 @@ func commandUpdate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	includeStates, err := parseStreamStates(updateOptions.StreamStates)
+	if err != nil {
+		return err
+	}
+
 	req := &vtctldatapb.WorkflowUpdateRequest{
 		Keyspace: baseOptions.Keyspace,
 		TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
@@ -112,6 +118,7 @@ func commandUpdate(cmd *cobra.Command, args []string) error {
 			TabletSelectionPreference: tsp,
 			OnDdl:                     binlogdatapb.OnDDLAction(onddl),
 			State:                     binlogdatapb.VReplicationWorkflowState(textutil.SimulatedNullInt), // We don't allow changing this in the client command
+			IncludeStates:             includeStates,
 		},
 	}
 
@@ -43,7 +43,8 @@ var (
 	}{}
 
 	workflowShowOptions = struct {
-		IncludeLogs bool
+		IncludeLogs  bool
+		StreamStates []string
 	}{}
 )
 
@@ -54,12 +55,16 @@ func registerCommands(root *cobra.Command) {
 
 	getWorkflows.Flags().BoolVar(&workflowShowOptions.IncludeLogs, "include-logs", true, "Include recent logs for the workflows.")
 	getWorkflows.Flags().BoolVarP(&getWorkflowsOptions.ShowAll, "show-all", "a", false, "Show all workflows instead of just active workflows.")
+	getWorkflows.Flags().BoolVar(&getWorkflowsOptions.SummaryOnly, "summary-only", false, "Only show a summary of each workflow (type, state, max lag, and copy percentage) instead of the details for all of its streams.")
+	getWorkflows.Flags().BoolVar(&getWorkflowsOptions.IncludeReversible, "include-reversible", false, "Report whether traffic can be reversed for each workflow that has had writes switched. This reads the reverse workflow from each source shard's primary tablet.")
 	root.AddCommand(getWorkflows) // Yes this is supposed to be root as GetWorkflows is a top-level command.
 
 	delete.Flags().StringVarP(&baseOptions.Workflow, "workflow", "w", "", "The workflow you want to delete.")
 	delete.MarkFlagRequired("workflow")
 	delete.Flags().BoolVar(&deleteOptions.KeepData, "keep-data", false, "Keep the partially copied table data from the workflow in the target keyspace.")
 	delete.Flags().BoolVar(&deleteOptions.KeepRoutingRules, "keep-routing-rules", false, "Keep the routing rules created for the workflow.")
+	delete.Flags().BoolVar(&deleteOptions.KeepVDiffData, "keep-vdiff-data", false, "Keep any VDiff data, such as the results of the final VDiff, associated with the workflow.")
+	delete.Flags().StringSliceVar(&deleteOptions.KeepVDiffTables, "keep-vdiff-tables", nil, "Keep the VDiff data for these tables while deleting the rest of the workflow's VDiff data.")
 	common.AddShardSubsetFlag(delete, &baseOptions.Shards)
 	base.AddCommand(delete)
 
@@ -69,6 +74,7 @@ func registerCommands(root *cobra.Command) {
 	show.Flags().StringVarP(&baseOptions.Workflow, "workflow", "w", "", "The workflow you want the details for.")
 	show.MarkFlagRequired("workflow")
 	show.Flags().BoolVar(&workflowShowOptions.IncludeLogs, "include-logs", true, "Include recent logs for the workflow.")
+	show.Flags().StringSliceVar(&workflowShowOptions.StreamStates, "stream-states", nil, "Only show the streams that are in one of these states (e.g. Error,Copying).")
 	common.AddShardSubsetFlag(show, &baseOptions.Shards)
 	base.AddCommand(show)
 
@@ -88,6 +94,7 @@ func registerCommands(root *cobra.Command) {
 	update.Flags().VarP((*topoproto.TabletTypeListFlag)(&updateOptions.TabletTypes), "tablet-types", "t", "New source tablet types to replicate from (e.g. PRIMARY,REPLICA,RDONLY).")
 	update.Flags().BoolVar(&updateOptions.TabletTypesInPreferenceOrder, "tablet-types-in-order", true, "When performing source tablet selection, look for candidates in the type order as they are listed in the tablet-types flag.")
 	update.Flags().StringVar(&updateOptions.OnDDL, "on-ddl", "", "New instruction on what to do when DDL is encountered in the VReplication stream. Possible values are IGNORE, STOP, EXEC, and EXEC_IGNORE.")
+	update.Flags().StringSliceVar(&updateOptions.StreamStates, "stream-states", nil, "Only update the streams that are currently in one of these states (e.g. Running,Copying).")
 	common.AddShardSubsetFlag(update, &baseOptions.Shards)
 	base.AddCommand(update)
 }
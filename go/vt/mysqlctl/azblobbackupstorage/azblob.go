@@ -32,9 +32,11 @@ import (
 	"github.com/Azure/azure-storage-blob-go/azblob"
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/ioutil"
 	"vitess.io/vitess/go/viperutil"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/log"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/servenv"
 )
@@ -262,12 +264,14 @@ func (bh *AZBlobBackupHandle) AddFile(ctx context.Context, filename string, file
 			MaxBuffers: azBlobParallelism.Get(),
 		})
 		if err != nil {
+			bh.bs.params.Logger.Errorf("AddFile: [azblob] container: %s, object: %s, error: %v", bh.bs.container(), obj, err)
 			reader.CloseWithError(err)
 			bh.RecordError(err)
 		}
 	}()
 
-	return writer, nil
+	stat := bh.bs.params.Stats.Scope(stats.Operation("AZBlob:Write"))
+	return ioutil.NewMeteredWriteCloser(writer, stat.TimedIncrementBytes), nil
 }
 
 // EndBackup implements BackupHandle.
@@ -308,17 +312,44 @@ func (bh *AZBlobBackupHandle) ReadFile(ctx context.Context, filename string) (io
 	if err != nil {
 		return nil, err
 	}
-	return resp.Body(azblob.RetryReaderOptions{
+	body := resp.Body(azblob.RetryReaderOptions{
 		MaxRetryRequests: defaultRetryCount,
 		NotifyFailedRead: func(failureCount int, lastError error, offset int64, count int64, willRetry bool) {
-			log.Warningf("ReadFile: [azblob] container: %s, directory: %s, filename: %s, error: %v", containerName, objName(bh.dir, ""), filename, lastError)
+			bh.bs.params.Logger.Warningf("ReadFile: [azblob] container: %s, directory: %s, filename: %s, error: %v", bh.bs.container(), objName(bh.dir, ""), filename, lastError)
 		},
 		TreatEarlyCloseAsError: true,
-	}), nil
+	})
+	stat := bh.bs.params.Stats.Scope(stats.Operation("AZBlob:Read"))
+	return ioutil.NewMeteredReadCloser(body, stat.TimedIncrementBytes), nil
 }
 
 // AZBlobBackupStorage structs implements the BackupStorage interface for AZBlob
 type AZBlobBackupStorage struct {
+	// params are used to log and record stats for operations on the backups.
+	params backupstorage.Params
+	// containerOverride, when set, is used instead of the value of the
+	// --azblob_backup_container_name flag.
+	containerOverride string
+}
+
+// NewAZBlobBackupStorage returns a new AZBlobBackupStorage which stores its
+// backups in the given container, or in the one specified by the
+// --azblob_backup_container_name flag if the container is empty. The storage
+// registered as "azblob" always uses the flag, so this is how a process that
+// backs up to more than one destination gets a storage for each of them.
+func NewAZBlobBackupStorage(container string) *AZBlobBackupStorage {
+	return &AZBlobBackupStorage{
+		params:            backupstorage.NoParams(),
+		containerOverride: container,
+	}
+}
+
+// container returns the name of the container the backups are stored in.
+func (bs *AZBlobBackupStorage) container() string {
+	if bs.containerOverride != "" {
+		return bs.containerOverride
+	}
+	return containerName.Get()
 }
 
 func (bs *AZBlobBackupStorage) containerURL() (*azblob.ContainerURL, error) {
@@ -326,7 +357,7 @@ func (bs *AZBlobBackupStorage) containerURL() (*azblob.ContainerURL, error) {
 	if err != nil {
 		return nil, err
 	}
-	u := azServiceURL(credentials).NewContainerURL(containerName.Get())
+	u := azServiceURL(credentials).NewContainerURL(bs.container())
 	return &u, nil
 }
 
@@ -339,7 +370,7 @@ func (bs *AZBlobBackupStorage) ListBackups(ctx context.Context, dir string) ([]b
 		searchPrefix = objName(dir, "")
 	}
 
-	log.Infof("ListBackups: [azblob] container: %s, directory: %v", containerName, searchPrefix)
+	bs.params.Logger.Infof("ListBackups: [azblob] container: %s, directory: %v", bs.container(), searchPrefix)
 
 	containerURL, err := bs.containerURL()
 	if err != nil {
@@ -399,7 +430,7 @@ func (bs *AZBlobBackupStorage) StartBackup(ctx context.Context, dir, name string
 
 // RemoveBackup implements BackupStorage.
 func (bs *AZBlobBackupStorage) RemoveBackup(ctx context.Context, dir, name string) error {
-	log.Infof("ListBackups: [azblob] container: %s, directory: %s", containerName, objName(dir, ""))
+	bs.params.Logger.Infof("RemoveBackup: [azblob] container: %s, directory: %s", bs.container(), objName(dir, ""))
 
 	containerURL, err := bs.containerURL()
 	if err != nil {
@@ -441,7 +472,7 @@ func (bs *AZBlobBackupStorage) RemoveBackup(ctx context.Context, dir, name strin
 			return err
 		}
 
-		log.Infof("Removing backup directory: %v", strings.TrimSuffix(searchPrefix, "/"))
+		bs.params.Logger.Infof("Removing backup directory: %v", strings.TrimSuffix(searchPrefix, "/"))
 		_, err = containerURL.NewBlobURL(strings.TrimSuffix(searchPrefix, "/")).Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
 		if err == nil {
 			break
@@ -456,9 +487,11 @@ func (bs *AZBlobBackupStorage) Close() error {
 	return nil
 }
 
+// WithParams implements BackupStorage. The returned storage is a distinct
+// instance which shares no state with the original, other than the container
+// it stores its backups in.
 func (bs *AZBlobBackupStorage) WithParams(params backupstorage.Params) backupstorage.BackupStorage {
-	// TODO(maxeng): return a new AZBlobBackupStorage that uses params.
-	return bs
+	return &AZBlobBackupStorage{params: params, containerOverride: bs.containerOverride}
 }
 
 // objName joins path parts into an object name.
@@ -472,5 +505,5 @@ func objName(parts ...string) string {
 }
 
 func init() {
-	backupstorage.BackupStorageMap["azblob"] = &AZBlobBackupStorage{}
+	backupstorage.BackupStorageMap["azblob"] = NewAZBlobBackupStorage("")
 }
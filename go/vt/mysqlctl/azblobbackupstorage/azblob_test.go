@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azblobbackupstorage
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/logutil"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+func TestWithParams(t *testing.T) {
+	params := backupstorage.Params{
+		Logger: logutil.NewMemoryLogger(),
+		Stats:  stats.NewFakeStats(),
+	}
+
+	bs := NewAZBlobBackupStorage("container1")
+	withParams := bs.WithParams(params).(*AZBlobBackupStorage)
+	assert.NotSame(t, bs, withParams)
+	assert.Equal(t, params, withParams.params)
+	assert.Equal(t, "container1", withParams.container())
+	// The original is not changed.
+	assert.Equal(t, backupstorage.NoParams().Stats, bs.params.Stats)
+
+	// The registered storage uses the container given by the flag.
+	registered := backupstorage.BackupStorageMap["azblob"].WithParams(params).(*AZBlobBackupStorage)
+	assert.Equal(t, containerName.Get(), registered.container())
+}
+
+// TestLogsToParamsLogger confirms that listing and removing backups log to
+// the storage's Logger rather than to the process log.
+func TestLogsToParamsLogger(t *testing.T) {
+	// Without credentials both calls fail once they have logged what they
+	// are about to do.
+	t.Setenv("VT_AZBLOB_ACCOUNT_NAME", "")
+	t.Setenv("VT_AZBLOB_ACCOUNT_KEY", "")
+	logger := logutil.NewMemoryLogger()
+	bs := NewAZBlobBackupStorage("container1").WithParams(backupstorage.Params{
+		Logger: logger,
+		Stats:  stats.NewFakeStats(),
+	})
+
+	_, err := bs.ListBackups(context.Background(), "ks/0")
+	assert.Error(t, err)
+	err = bs.RemoveBackup(context.Background(), "ks/0", "backup1")
+	assert.Error(t, err)
+	assert.Contains(t, logger.String(), "ListBackups: [azblob] container: container1, directory: ks/0/")
+	assert.Contains(t, logger.String(), "RemoveBackup: [azblob] container: container1, directory: ks/0/")
+}
+
+func TestSeparateContainers(t *testing.T) {
+	t.Setenv("VT_AZBLOB_ACCOUNT_NAME", "account")
+	t.Setenv("VT_AZBLOB_ACCOUNT_KEY", "a2V5")
+
+	storages := map[string]*AZBlobBackupStorage{
+		"container1": NewAZBlobBackupStorage("container1").WithParams(backupstorage.NoParams()).(*AZBlobBackupStorage),
+		"container2": NewAZBlobBackupStorage("container2").WithParams(backupstorage.NoParams()).(*AZBlobBackupStorage),
+	}
+
+	var wg sync.WaitGroup
+	for container, bs := range storages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				u, err := bs.containerURL()
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, "https://account.blob.core.windows.net/"+container, u.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
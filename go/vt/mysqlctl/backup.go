@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -68,6 +69,10 @@ const (
 )
 
 var (
+	// backupLabelSanitizer matches the characters which are not allowed in
+	// a backup label.
+	backupLabelSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
 	// ErrNoBackup is returned when there is no backup.
 	ErrNoBackup = errors.New("no available backup")
 
@@ -119,13 +124,16 @@ func registerBackupFlags(fs *pflag.FlagSet) {
 // - shuts down Mysqld during the backup
 // - remember if we were replicating, restore the exact same state
 func Backup(ctx context.Context, params BackupParams) error {
+	if err := ValidateCompressionEngine(params.CompressionEngine); err != nil {
+		return vterrors.Wrap(err, "invalid compression engine for the backup")
+	}
 	if params.Stats == nil {
 		params.Stats = backupstats.NoStats()
 	}
 
 	startTs := time.Now()
 	backupDir := GetBackupDir(params.Keyspace, params.Shard)
-	name := fmt.Sprintf("%v.%v", params.BackupTime.UTC().Format(BackupTimestampFormat), params.TabletAlias)
+	name := BackupName(params.BackupTime, params.TabletAlias, params.Label)
 	// Start the backup with the BackupStorage.
 	bs, err := backupstorage.GetBackupStorage()
 	if err != nil {
@@ -201,6 +209,24 @@ func Backup(ctx context.Context, params BackupParams) error {
 	return finishErr
 }
 
+// BackupName returns the name of a backup taken at backupTime by the given
+// tablet, in the format "date.time.tablet-alias". If label is not empty, it
+// is sanitized and appended as a fourth "label" component.
+func BackupName(backupTime time.Time, tabletAlias string, label string) string {
+	name := fmt.Sprintf("%v.%v", backupTime.UTC().Format(BackupTimestampFormat), tabletAlias)
+	if label = SanitizeBackupLabel(label); label != "" {
+		name = fmt.Sprintf("%v.%v", name, label)
+	}
+	return name
+}
+
+// SanitizeBackupLabel replaces any characters in label which are not letters,
+// digits, dashes or underscores with underscores, so that the label can be
+// safely used as a component of a backup name.
+func SanitizeBackupLabel(label string) string {
+	return backupLabelSanitizer.ReplaceAllString(label, "_")
+}
+
 // ParseBackupName parses the backup name for a given dir/name, according to
 // the format generated by mysqlctl.Backup. An error is returned only if the
 // backup name does not have the expected number of parts; errors parsing the
@@ -208,13 +234,14 @@ func Backup(ctx context.Context, params BackupParams) error {
 // fields in case of error.
 func ParseBackupName(dir string, name string) (backupTime *time.Time, alias *topodatapb.TabletAlias, err error) {
 	parts := strings.Split(name, ".")
-	if len(parts) != 3 {
-		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot backup name %s, expected <date>.<time>.<tablet_alias>", name)
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "cannot backup name %s, expected <date>.<time>.<tablet_alias>[.<label>]", name)
 	}
 
 	// parts[0]: date part of BackupTimestampFormat
 	// parts[1]: time part of BackupTimestampFormat
 	// parts[2]: tablet alias
+	// parts[3]: optional label
 	timestamp := strings.Join(parts[:2], ".")
 	aliasStr := parts[2]
 
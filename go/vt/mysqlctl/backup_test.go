@@ -685,6 +685,25 @@ func TestParseBackupName(t *testing.T) {
 	assert.Equal(t, "cell1", al.Cell)
 	assert.Equal(t, uint32(42), al.Uid)
 	assert.NoError(t, err)
+
+	// Valid case with a label
+	bt, al, err = ParseBackupName("dir", "2024-03-18.180911.cell1-42.pre-upgrade")
+	assert.Equal(t, time.Date(2024, 03, 18, 18, 9, 11, 0, time.UTC), *bt)
+	assert.Equal(t, "cell1", al.Cell)
+	assert.Equal(t, uint32(42), al.Uid)
+	assert.NoError(t, err)
+
+	// Too many parts
+	_, _, err = ParseBackupName("dir", "2024-03-18.180911.cell1-42.label.extra")
+	assert.ErrorContains(t, err, "cannot backup name")
+}
+
+func TestBackupName(t *testing.T) {
+	backupTime := time.Date(2024, 03, 18, 18, 9, 11, 0, time.UTC)
+	assert.Equal(t, "2024-03-18.180911.cell1-0000000042", BackupName(backupTime, "cell1-0000000042", ""))
+	assert.Equal(t, "2024-03-18.180911.cell1-0000000042.pre-upgrade_v20", BackupName(backupTime, "cell1-0000000042", "pre-upgrade_v20"))
+	// Characters that could break parsing of the name are replaced.
+	assert.Equal(t, "2024-03-18.180911.cell1-0000000042.v20_0_1_hot_fix", BackupName(backupTime, "cell1-0000000042", "v20.0.1 hot/fix"))
 }
 
 func TestShouldRestore(t *testing.T) {
@@ -87,6 +87,14 @@ type BackupParams struct {
 	UpgradeSafe bool
 	// MysqlShutdownTimeout defines how long we wait during MySQL shutdown if that is part of the backup process.
 	MysqlShutdownTimeout time.Duration
+	// CompressionEngine, when set, overrides the --compression-engine-name flag for this backup.
+	// It is currently only honored by the builtin backup engine.
+	CompressionEngine string
+	// SkipCompress, when set, disables compression for this backup regardless of --backup_storage_compress.
+	// It is currently only honored by the builtin backup engine.
+	SkipCompress bool
+	// Label, when set, is sanitized and appended to the backup name as a fourth component.
+	Label string
 }
 
 func (b *BackupParams) Copy() BackupParams {
@@ -105,9 +113,67 @@ func (b *BackupParams) Copy() BackupParams {
 		Stats:                b.Stats,
 		UpgradeSafe:          b.UpgradeSafe,
 		MysqlShutdownTimeout: b.MysqlShutdownTimeout,
+		CompressionEngine:    b.CompressionEngine,
+		SkipCompress:         b.SkipCompress,
+		Label:                b.Label,
 	}
 }
 
+// compress returns whether or not the backup files should be compressed.
+func (b *BackupParams) compress() bool {
+	return backupStorageCompress && !b.SkipCompress
+}
+
+// compressionEngine returns the name of the compression engine to use for
+// the backup.
+func (b *BackupParams) compressionEngine() string {
+	if b.CompressionEngine != "" {
+		return b.CompressionEngine
+	}
+	return CompressionEngineName
+}
+
+// ValidateCompressionEngine returns an error if the given engine cannot be
+// used as the CompressionEngine of a backup's BackupParams. An empty engine
+// is valid and means that --compression-engine-name is used. The external
+// engine can only be used when an --external-compressor is configured.
+func ValidateCompressionEngine(engine string) error {
+	switch engine {
+	case "", PgzipCompressor, PargzipCompressor, Lz4Compressor, ZstdCompressor:
+		return nil
+	case ExternalCompressor:
+		if ExternalCompressorCmd == "" {
+			return fmt.Errorf("the %q compression engine requires --external-compressor to be set", ExternalCompressor)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression engine %q, supported values are '%s', '%s', '%s', '%s' and '%s'", engine,
+			ExternalCompressor, PgzipCompressor, PargzipCompressor, ZstdCompressor, Lz4Compressor)
+	}
+}
+
+// overridesExternalCompressor returns true when an explicit builtin
+// compression engine was requested for the backup, which takes precedence
+// over any configured external compressor.
+func (b *BackupParams) overridesExternalCompressor() bool {
+	return b.CompressionEngine != "" && b.CompressionEngine != ExternalCompressor
+}
+
+// useExternalCompressor returns whether or not the configured external
+// compressor should be used for the backup.
+func (b *BackupParams) useExternalCompressor() bool {
+	return ExternalCompressorCmd != "" && !b.overridesExternalCompressor()
+}
+
+// manifestExternalDecompressor returns the external decompressor command to
+// record in the backup manifest.
+func (b *BackupParams) manifestExternalDecompressor() string {
+	if b.overridesExternalCompressor() {
+		return ""
+	}
+	return ManifestExternalDecompressorCmd
+}
+
 // RestoreParams is the struct that holds all params passed to ExecuteRestore
 type RestoreParams struct {
 	Cnf    *Mycnf
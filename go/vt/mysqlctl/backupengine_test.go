@@ -213,3 +213,42 @@ func TestValidateMySQLVersionUpgradeCompatible(t *testing.T) {
 	}
 
 }
+
+func TestBackupParamsCompressionOverrides(t *testing.T) {
+	origCompress, origEngine, origExternal := backupStorageCompress, CompressionEngineName, ExternalCompressorCmd
+	defer func() {
+		backupStorageCompress, CompressionEngineName, ExternalCompressorCmd = origCompress, origEngine, origExternal
+	}()
+	backupStorageCompress = true
+	CompressionEngineName = PargzipCompressor
+	ExternalCompressorCmd = "zstd -c"
+
+	params := BackupParams{}
+	assert.True(t, params.compress())
+	assert.Equal(t, PargzipCompressor, params.compressionEngine())
+	assert.True(t, params.useExternalCompressor())
+
+	params = BackupParams{CompressionEngine: Lz4Compressor, SkipCompress: true}
+	assert.False(t, params.compress())
+	assert.Equal(t, Lz4Compressor, params.compressionEngine())
+	assert.False(t, params.useExternalCompressor())
+	assert.Empty(t, params.manifestExternalDecompressor())
+
+	copied := params.Copy()
+	assert.Equal(t, Lz4Compressor, copied.CompressionEngine)
+	assert.True(t, copied.SkipCompress)
+}
+
+func TestValidateCompressionEngine(t *testing.T) {
+	defer func(cmd string) { ExternalCompressorCmd = cmd }(ExternalCompressorCmd)
+
+	ExternalCompressorCmd = ""
+	for _, engine := range []string{"", PgzipCompressor, PargzipCompressor, Lz4Compressor, ZstdCompressor} {
+		assert.NoError(t, ValidateCompressionEngine(engine), "engine %q", engine)
+	}
+	assert.EqualError(t, ValidateCompressionEngine(ExternalCompressor), `the "external" compression engine requires --external-compressor to be set`)
+	assert.ErrorContains(t, ValidateCompressionEngine("gzip"), `unsupported compression engine "gzip"`)
+
+	ExternalCompressorCmd = "zstd -c"
+	assert.NoError(t, ValidateCompressionEngine(ExternalCompressor))
+}
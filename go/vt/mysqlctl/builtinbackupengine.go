@@ -213,7 +213,7 @@ func (fe *FileEntry) open(cnf *Mycnf, readOnly bool) (*os.File, error) {
 // The function returns a BackupResult that indicates the usability of the backup, and an overall error.
 func (be *BuiltinBackupEngine) ExecuteBackup(ctx context.Context, params BackupParams, bh backupstorage.BackupHandle) (BackupResult, error) {
 	params.Logger.Infof("Executing Backup at %v for keyspace/shard %v/%v on tablet %v, concurrency: %v, compress: %v, incrementalFromPos: %v",
-		params.BackupTime, params.Keyspace, params.Shard, params.TabletAlias, params.Concurrency, backupStorageCompress, params.IncrementalFromPos)
+		params.BackupTime, params.Keyspace, params.Shard, params.TabletAlias, params.Concurrency, params.compress(), params.IncrementalFromPos)
 
 	if isIncrementalBackup(params) {
 		return be.executeIncrementalBackup(ctx, params, bh)
@@ -690,9 +690,9 @@ func (be *BuiltinBackupEngine) backupFiles(
 
 		// Builtin-specific fields
 		FileEntries:          fes,
-		SkipCompress:         !backupStorageCompress,
-		CompressionEngine:    CompressionEngineName,
-		ExternalDecompressor: ManifestExternalDecompressorCmd,
+		SkipCompress:         !params.compress(),
+		CompressionEngine:    params.compressionEngine(),
+		ExternalDecompressor: params.manifestExternalDecompressor(),
 	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
@@ -859,12 +859,12 @@ func (be *BuiltinBackupEngine) backupFile(ctx context.Context, params BackupPara
 			}
 		}()
 		// Create the gzip compression pipe, if necessary.
-		if backupStorageCompress {
+		if params.compress() {
 			var compressor io.WriteCloser
-			if ExternalCompressorCmd != "" {
+			if params.useExternalCompressor() {
 				compressor, err = newExternalCompressor(ctx, ExternalCompressorCmd, writer, params.Logger)
 			} else {
-				compressor, err = newBuiltinCompressor(CompressionEngineName, writer, params.Logger)
+				compressor, err = newBuiltinCompressor(params.compressionEngine(), writer, params.Logger)
 			}
 			if err != nil {
 				return vterrors.Wrap(err, "can't create compressor")
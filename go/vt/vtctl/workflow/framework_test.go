@@ -66,6 +66,7 @@ type testKeyspace struct {
 type queryResult struct {
 	query  string
 	result *querypb.QueryResult
+	err    error
 }
 
 func TestMain(m *testing.M) {
@@ -236,6 +237,21 @@ type testTMClient struct {
 	vrQueries                          map[int][]*queryResult
 	createVReplicationWorkflowRequests map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest
 	readVReplicationWorkflowRequests   map[uint32]*tabletmanagerdatapb.ReadVReplicationWorkflowRequest
+	updateVReplicationWorkflowRequests map[uint32]*tabletmanagerdatapb.UpdateVReplicationWorkflowRequest
+	// If set for a tablet, this is returned by GetSchema for the tablet
+	// instead of the schema above.
+	tabletSchemas map[uint32]*tabletmanagerdatapb.SchemaDefinition
+	// If set for a tablet, this is returned by ReadVReplicationWorkflows
+	// for the tablet when no specific workflows are requested.
+	readVReplicationWorkflowsResponses map[uint32]*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse
+	// If set for a tablet, this is returned by ReadVReplicationWorkflow for
+	// the tablet instead of a response built from the test env.
+	readVReplicationWorkflowResponses map[uint32]*tabletmanagerdatapb.ReadVReplicationWorkflowResponse
+	// If set for a tablet, these are the workflow tags returned by
+	// ReadVReplicationWorkflow for the tablet.
+	workflowTags map[uint32]string
+	// The VDiff requests that were sent to each tablet.
+	vdiffRequests map[uint32][]*tabletmanagerdatapb.VDiffRequest
 
 	env     *testEnv    // For access to the env config from tmc methods.
 	reverse atomic.Bool // Are we reversing traffic?
@@ -247,6 +263,12 @@ func newTestTMClient(env *testEnv) *testTMClient {
 		vrQueries:                          make(map[int][]*queryResult),
 		createVReplicationWorkflowRequests: make(map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest),
 		readVReplicationWorkflowRequests:   make(map[uint32]*tabletmanagerdatapb.ReadVReplicationWorkflowRequest),
+		updateVReplicationWorkflowRequests: make(map[uint32]*tabletmanagerdatapb.UpdateVReplicationWorkflowRequest),
+		tabletSchemas:                      make(map[uint32]*tabletmanagerdatapb.SchemaDefinition),
+		readVReplicationWorkflowsResponses: make(map[uint32]*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse),
+		readVReplicationWorkflowResponses:  make(map[uint32]*tabletmanagerdatapb.ReadVReplicationWorkflowResponse),
+		workflowTags:                       make(map[uint32]string),
+		vdiffRequests:                      make(map[uint32][]*tabletmanagerdatapb.VDiffRequest),
 		env:                                env,
 	}
 }
@@ -273,6 +295,9 @@ func (tmc *testTMClient) ReadVReplicationWorkflow(ctx context.Context, tablet *t
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected ReadVReplicationWorkflow request: got %+v, want %+v", req, expect)
 		}
 	}
+	if resp, ok := tmc.readVReplicationWorkflowResponses[tablet.Alias.Uid]; ok {
+		return resp, nil
+	}
 	workflowType := binlogdatapb.VReplicationWorkflowType_MoveTables
 	if strings.Contains(req.Workflow, "lookup") {
 		workflowType = binlogdatapb.VReplicationWorkflowType_CreateLookupIndex
@@ -280,6 +305,7 @@ func (tmc *testTMClient) ReadVReplicationWorkflow(ctx context.Context, tablet *t
 	res := &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
 		Workflow:     req.Workflow,
 		WorkflowType: workflowType,
+		Tags:         tmc.workflowTags[tablet.Alias.Uid],
 		Streams:      make([]*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream, 0, 2),
 	}
 	rules := make([]*binlogdatapb.Rule, len(tmc.schema))
@@ -323,6 +349,10 @@ func (tmc *testTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Table
 	tmc.mu.Lock()
 	defer tmc.mu.Unlock()
 
+	if schemaDefn, ok := tmc.tabletSchemas[tablet.Alias.Uid]; ok {
+		return schemaDefn.CloneVT(), nil
+	}
+
 	schemaDefn := &tabletmanagerdatapb.SchemaDefinition{}
 	for _, table := range req.Tables {
 		if table == "/.*/" {
@@ -389,7 +419,7 @@ func (tmc *testTMClient) VReplicationExec(ctx context.Context, tablet *topodatap
 		return nil, fmt.Errorf("tablet %v:\nunexpected query\n%s\nwant:\n%s", tablet, query, qrs[0].query)
 	}
 	tmc.vrQueries[int(tablet.Alias.Uid)] = qrs[1:]
-	return qrs[0].result, nil
+	return qrs[0].result, qrs[0].err
 }
 
 func (tmc *testTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
@@ -397,6 +427,11 @@ func (tmc *testTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodata
 	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
 }
 
+func (tmc *testTMClient) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error) {
+	// Reuse VReplicationExec.
+	return tmc.VReplicationExec(ctx, tablet, string(req.Query))
+}
+
 func (tmc *testTMClient) ExecuteFetchAsAllPrivs(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest) (*querypb.QueryResult, error) {
 	return nil, nil
 }
@@ -419,7 +454,19 @@ func (tmc *testTMClient) ApplySchema(ctx context.Context, tablet *topodatapb.Tab
 	return nil, nil
 }
 
+func (tmc *testTMClient) ReloadSchema(ctx context.Context, tablet *topodatapb.Tablet, waitPosition string) error {
+	return nil
+}
+
+func (tmc *testTMClient) RefreshState(ctx context.Context, tablet *topodatapb.Tablet) error {
+	return nil
+}
+
 func (tmc *testTMClient) VDiff(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.VDiffRequest) (*tabletmanagerdatapb.VDiffResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+
+	tmc.vdiffRequests[tablet.Alias.Uid] = append(tmc.vdiffRequests[tablet.Alias.Uid], req)
 	return &tabletmanagerdatapb.VDiffResponse{
 		Id:        1,
 		VdiffUuid: req.VdiffUuid,
@@ -479,11 +526,22 @@ func (tmc *testTMClient) ReadVReplicationWorkflows(ctx context.Context, tablet *
 			},
 		}, nil
 	} else {
+		if resp, ok := tmc.readVReplicationWorkflowsResponses[tablet.Alias.Uid]; ok {
+			return resp, nil
+		}
 		return &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{}, nil
 	}
 }
 
 func (tmc *testTMClient) UpdateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateVReplicationWorkflowRequest) (*tabletmanagerdatapb.UpdateVReplicationWorkflowResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+
+	if expect := tmc.updateVReplicationWorkflowRequests[tablet.Alias.Uid]; expect != nil {
+		if !proto.Equal(expect, req) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unexpected UpdateVReplicationWorkflow request: got %+v, want %+v", req, expect)
+		}
+	}
 	return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{
 		Result: &querypb.QueryResult{
 			RowsAffected: 1,
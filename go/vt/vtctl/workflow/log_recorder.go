@@ -19,12 +19,16 @@ package workflow
 import (
 	"fmt"
 	"sort"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // LogRecorder is used to collect logs for a specific purpose.
 // Not thread-safe since it is expected to be generated in repeatable sequence
 type LogRecorder struct {
 	logs []string
+	// steps holds the structured form of each log, in the same sequence.
+	steps []*vtctldatapb.DryRunStep
 }
 
 // NewLogRecorder creates a new instance of LogRecorder
@@ -35,12 +39,29 @@ func NewLogRecorder() *LogRecorder {
 
 // Log records a new log message
 func (lr *LogRecorder) Log(log string) {
-	lr.logs = append(lr.logs, log)
+	lr.LogStep("", "", log)
 }
 
 // Logf records a new log message with interpolation parameters using fmt.Sprintf.
 func (lr *LogRecorder) Logf(log string, args ...any) {
-	lr.logs = append(lr.logs, fmt.Sprintf(log, args...))
+	lr.LogStep("", "", fmt.Sprintf(log, args...))
+}
+
+// LogStep records a new log message along with the action it describes and
+// the target of that action, e.g. a keyspace, shard, or tablet.
+func (lr *LogRecorder) LogStep(action, target, log string) {
+	lr.logs = append(lr.logs, log)
+	lr.steps = append(lr.steps, &vtctldatapb.DryRunStep{
+		Action:  action,
+		Target:  target,
+		Details: log,
+	})
+}
+
+// LogStepf records a new log message, with interpolation parameters using
+// fmt.Sprintf, along with the action it describes and the target of that action.
+func (lr *LogRecorder) LogStepf(action, target, log string, args ...any) {
+	lr.LogStep(action, target, fmt.Sprintf(log, args...))
 }
 
 // LogSlice sorts a given slice using natural sort, so that the result is predictable.
@@ -54,5 +75,16 @@ func (lr *LogRecorder) LogSlice(logs []string) {
 
 // GetLogs returns all recorded logs in sequence
 func (lr *LogRecorder) GetLogs() []string {
+	if lr == nil {
+		return nil
+	}
 	return lr.logs
 }
+
+// GetSteps returns the structured form of all recorded logs in sequence
+func (lr *LogRecorder) GetSteps() []*vtctldatapb.DryRunStep {
+	if lr == nil {
+		return nil
+	}
+	return lr.steps
+}
@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 func TestLogRecorder(t *testing.T) {
@@ -33,3 +35,23 @@ func TestLogRecorder(t *testing.T) {
 	want := []string{"log 1", "log 2", "log 3 with params: param1, August, 3", "log 4", "log 5"}
 	assert.Equal(t, lr.GetLogs(), want)
 }
+
+func TestLogRecorderSteps(t *testing.T) {
+	lr := NewLogRecorder()
+	lr.LogStepf("lock_keyspace", "ks1", "Lock keyspace %s", "ks1")
+	lr.Log("Create journal entries on source databases")
+	lr.LogStep("unlock_keyspace", "ks1", "Unlock keyspace ks1")
+	wantLogs := []string{"Lock keyspace ks1", "Create journal entries on source databases", "Unlock keyspace ks1"}
+	wantSteps := []*vtctldatapb.DryRunStep{
+		{Action: "lock_keyspace", Target: "ks1", Details: "Lock keyspace ks1"},
+		{Details: "Create journal entries on source databases"},
+		{Action: "unlock_keyspace", Target: "ks1", Details: "Unlock keyspace ks1"},
+	}
+	assert.Equal(t, wantLogs, lr.GetLogs())
+	assert.Equal(t, wantSteps, lr.GetSteps())
+
+	// A nil recorder, as returned by the non dry run switcher, has no logs.
+	var nilRecorder *LogRecorder
+	assert.Nil(t, nilRecorder.GetLogs())
+	assert.Nil(t, nilRecorder.GetSteps())
+}
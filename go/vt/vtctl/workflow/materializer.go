@@ -109,10 +109,10 @@ func (mz *materializer) getOptionsJSON() (string, error) {
 	return string(optionsJSON), nil
 }
 
+// createWorkflowStreams creates the workflow's streams on the target shards.
+// Callers are expected to have already checked that the workflow does not
+// exist, using validateNewWorkflow, before making any other changes.
 func (mz *materializer) createWorkflowStreams(req *tabletmanagerdatapb.CreateVReplicationWorkflowRequest) error {
-	if err := validateNewWorkflow(mz.ctx, mz.ts, mz.tmc, mz.ms.TargetKeyspace, mz.ms.Workflow); err != nil {
-		return err
-	}
 	err := mz.buildMaterializer()
 	if err != nil {
 		return err
@@ -158,6 +158,11 @@ type testMaterializerTMClient struct {
 	mu                                 sync.Mutex
 	vrQueries                          map[int][]*queryResult
 	createVReplicationWorkflowRequests map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest
+	// If set for a tablet, this is returned by GetSchema for the tablet
+	// instead of the schema above.
+	tabletSchemas map[uint32]*tabletmanagerdatapb.SchemaDefinition
+	// The position returned by PrimaryPosition for each tablet.
+	primaryPositions map[uint32]string
 
 	// Used to confirm the number of times WorkflowDelete was called.
 	workflowDeleteCalls int
@@ -168,9 +173,17 @@ func newTestMaterializerTMClient() *testMaterializerTMClient {
 		schema:                             make(map[string]*tabletmanagerdatapb.SchemaDefinition),
 		vrQueries:                          make(map[int][]*queryResult),
 		createVReplicationWorkflowRequests: make(map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest),
+		tabletSchemas:                      make(map[uint32]*tabletmanagerdatapb.SchemaDefinition),
+		primaryPositions:                   make(map[uint32]string),
 	}
 }
 
+func (tmc *testMaterializerTMClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	return tmc.primaryPositions[tablet.Alias.Uid], nil
+}
+
 func (tmc *testMaterializerTMClient) CreateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CreateVReplicationWorkflowRequest) (*tabletmanagerdatapb.CreateVReplicationWorkflowResponse, error) {
 	if expect := tmc.createVReplicationWorkflowRequests[tablet.Alias.Uid]; expect != nil {
 		if !proto.Equal(expect, request) {
@@ -220,6 +233,9 @@ func (tmc *testMaterializerTMClient) DeleteVReplicationWorkflow(ctx context.Cont
 }
 
 func (tmc *testMaterializerTMClient) GetSchema(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.GetSchemaRequest) (*tabletmanagerdatapb.SchemaDefinition, error) {
+	if schemaDefn, ok := tmc.tabletSchemas[tablet.Alias.Uid]; ok {
+		return schemaDefn.CloneVT(), nil
+	}
 	schemaDefn := &tabletmanagerdatapb.SchemaDefinition{}
 	for _, table := range request.Tables {
 		if table == "/.*/" {
@@ -30,6 +30,7 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vtenv"
@@ -576,7 +577,7 @@ func TestMoveTablesDDLFlag(t *testing.T) {
 			require.NoError(t, err)
 			sourceShard, err := env.topoServ.GetShardNames(ctx, ms.SourceKeyspace)
 			require.NoError(t, err)
-			want := fmt.Sprintf("shard_streams:{key:\"%s/%s\" value:{streams:{id:1 tablet:{cell:\"%s\" uid:200} source_shard:\"%s/%s\" position:\"%s\" status:\"Running\" info:\"VStream Lag: 0s\"}}} traffic_state:\"Reads Not Switched. Writes Not Switched\"",
+			want := fmt.Sprintf("shard_streams:{key:\"%s/%s\" value:{streams:{id:1 tablet:{cell:\"%s\" uid:200} source_shard:\"%s/%s\" position:\"%s\" status:\"Running\" info:\"VStream Lag: 0s\"}}} traffic_state:\"Reads Not Switched. Writes Not Switched\" on_ddl:\"IGNORE\"",
 				ms.TargetKeyspace, targetShard[0], env.cell, ms.SourceKeyspace, sourceShard[0], position)
 
 			res, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
@@ -592,6 +593,115 @@ func TestMoveTablesDDLFlag(t *testing.T) {
 	}
 }
 
+// TestMoveTablesAutoStartAfterSchemaCheck confirms that the workflow is only
+// started when the source and target schemas match.
+func TestMoveTablesAutoStartAfterSchemaCheck(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "t1",
+			SourceExpression: "select * from t1",
+		}},
+	}
+
+	testcases := []struct {
+		name         string
+		targetSchema *tabletmanagerdatapb.SchemaDefinition
+		wantWarnings []string
+	}{
+		{
+			name: "schemas match",
+		},
+		{
+			name: "schemas differ",
+			targetSchema: &tabletmanagerdatapb.SchemaDefinition{
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+					Name:   "t1",
+					Schema: "t1_schema_changed",
+					Type:   tmutils.TableBaseTable,
+				}},
+			},
+			wantWarnings: []string{
+				"the schema for table .* differs between the source and target: dest has an extra table named t1",
+				"the workflow was not started, fix the schema differences and then start it using: MoveTables --workflow workflow --target-keyspace targetks start",
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+			defer env.close()
+			if tc.targetSchema != nil {
+				env.tmc.tabletSchemas[200] = tc.targetSchema
+			}
+
+			env.tmc.expectVRQuery(100, mzCheckJournal, &sqltypes.Result{})
+			env.tmc.expectVRQuery(200, mzGetCopyState, &sqltypes.Result{})
+			env.tmc.expectVRQuery(200, mzGetLatestCopyState, &sqltypes.Result{})
+
+			res, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+				Workflow:                  ms.Workflow,
+				SourceKeyspace:            ms.SourceKeyspace,
+				TargetKeyspace:            ms.TargetKeyspace,
+				IncludeTables:             []string{"t1"},
+				AutoStartAfterSchemaCheck: true,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.wantWarnings, res.Warnings)
+		})
+	}
+}
+
+// TestMoveTablesPinSourcePositions confirms that each stream's starting
+// position is set to its source shard's position before it is started.
+func TestMoveTablesPinSourcePositions(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      "t1",
+			SourceExpression: "select * from t1",
+		}},
+	}
+	sourcePos := "MySQL56/e5fb8ef4-6f4a-11ee-a6d9-0242ac120002:1-42"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+	env.tmc.primaryPositions[100] = sourcePos
+
+	env.tmc.expectVRQuery(100, mzCheckJournal, &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, fmt.Sprintf("update _vt.vreplication set pos = '%s' where id = 1", sourcePos), &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, mzGetCopyState, &sqltypes.Result{})
+	env.tmc.expectVRQuery(200, mzGetLatestCopyState, &sqltypes.Result{})
+
+	_, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+		Workflow:           ms.Workflow,
+		SourceKeyspace:     ms.SourceKeyspace,
+		TargetKeyspace:     ms.TargetKeyspace,
+		IncludeTables:      []string{"t1"},
+		PinSourcePositions: true,
+	})
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+
+	_, err = env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+		Workflow:            ms.Workflow,
+		SourceKeyspace:      ms.SourceKeyspace,
+		TargetKeyspace:      ms.TargetKeyspace,
+		IncludeTables:       []string{"t1"},
+		ExternalClusterName: "ext1",
+		PinSourcePositions:  true,
+	})
+	require.ErrorContains(t, err, "cannot pin the source positions when the source is an external cluster")
+}
+
 // TestMoveTablesNoRoutingRules confirms that MoveTables does not create routing rules if --no-routing-rules is specified.
 func TestMoveTablesNoRoutingRules(t *testing.T) {
 	ms := &vtctldatapb.MaterializeSettings{
@@ -644,6 +754,7 @@ func TestMoveTablesNoRoutingRules(t *testing.T) {
 			},
 		},
 		TrafficState: "Reads Not Switched. Writes Not Switched",
+		OnDdl:        "IGNORE",
 	}
 
 	res, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
@@ -18,8 +18,14 @@ package workflow
 
 import (
 	"context"
+	"sort"
+	"sync"
 
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vterrors"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -86,3 +92,67 @@ func (s *Server) MountShow(ctx context.Context, req *vtctldatapb.MountShowReques
 		Name:       req.Name,
 	}, nil
 }
+
+// MountStatus reports whether a mounted external Vitess cluster could be
+// reached, as determined by ListMounts.
+type MountStatus struct {
+	Name      string
+	Reachable bool
+	// Error describes why the cluster's topo server could not be reached.
+	Error error
+}
+
+// ListMounts returns the names of all of the mounted external Vitess
+// clusters, sorted by name, along with whether or not each one's topo server
+// can be opened and read from. This can be used to confirm that a mount is
+// usable before creating a Migrate workflow that uses it.
+func (s *Server) ListMounts(ctx context.Context) ([]*MountStatus, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ListMounts")
+	defer span.Finish()
+
+	names, err := s.ts.GetExternalVitessClusters(ctx)
+	if err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get external vitess clusters in ListMounts: %v", err)
+	}
+	sort.Strings(names)
+
+	var (
+		mu       sync.Mutex
+		statuses = make([]*MountStatus, 0, len(names))
+		eg, ectx = errgroup.WithContext(ctx)
+	)
+	eg.SetLimit(topo.DefaultConcurrency)
+	for _, name := range names {
+		eg.Go(func() error {
+			status := &MountStatus{Name: name}
+			status.Error = s.pingExternalVitessCluster(ectx, name)
+			status.Reachable = status.Error == nil
+			mu.Lock()
+			defer mu.Unlock()
+			statuses = append(statuses, status)
+			// We want a status for every mount, so we don't fail the group.
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses, nil
+}
+
+// pingExternalVitessCluster opens the topo server of the given external
+// Vitess cluster and reads its keyspaces, returning an error if either fails.
+func (s *Server) pingExternalVitessCluster(ctx context.Context, name string) error {
+	externalTopo, err := s.ts.OpenExternalVitessClusterServer(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer externalTopo.Close()
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+	_, err = externalTopo.GetKeyspaces(ctx)
+	return err
+}
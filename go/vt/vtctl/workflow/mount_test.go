@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestListMounts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "cell1")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	mounts, err := ws.ListMounts(ctx)
+	require.NoError(t, err)
+	require.Empty(t, mounts)
+
+	// Neither mount uses a topo implementation that exists, so neither
+	// can be reached.
+	for _, name := range []string{"ext2", "ext1"} {
+		err = ts.CreateExternalVitessCluster(ctx, name, &topodatapb.ExternalVitessCluster{
+			TopoConfig: &topodatapb.TopoConfig{
+				TopoType: "nonexistent",
+				Server:   "localhost:2379",
+				Root:     "/vitess/" + name,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	mounts, err = ws.ListMounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+	for i, name := range []string{"ext1", "ext2"} {
+		require.Equal(t, name, mounts[i].Name)
+		require.False(t, mounts[i].Reachable)
+		require.Error(t, mounts[i].Error)
+	}
+}
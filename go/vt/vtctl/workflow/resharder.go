@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
@@ -58,6 +60,7 @@ type resharder struct {
 	stopAfterCopy      bool
 	onDDL              string
 	deferSecondaryKeys bool
+	allowSchemaDiff    bool
 }
 
 type refStream struct {
@@ -137,7 +140,7 @@ func (s *Server) buildResharder(ctx context.Context, keyspace, workflow string,
 func (rs *resharder) validateTargets(ctx context.Context) error {
 	err := rs.forAll(rs.targetShards, func(target *topo.ShardInfo) error {
 		targetPrimary := rs.targetPrimaries[target.ShardName()]
-		res, err := rs.s.tmc.HasVReplicationWorkflows(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.HasVReplicationWorkflowsRequest{})
+		res, err := rs.s.tmClient().HasVReplicationWorkflows(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.HasVReplicationWorkflowsRequest{})
 		if err != nil {
 			return vterrors.Wrapf(err, "HasVReplicationWorkflows(%v)", targetPrimary.Tablet)
 		}
@@ -157,7 +160,7 @@ func (rs *resharder) readRefStreams(ctx context.Context) error {
 		req := &tabletmanagerdatapb.ReadVReplicationWorkflowsRequest{
 			ExcludeFrozen: true,
 		}
-		res, err := rs.s.tmc.ReadVReplicationWorkflows(ctx, sourcePrimary.Tablet, req)
+		res, err := rs.s.tmClient().ReadVReplicationWorkflows(ctx, sourcePrimary.Tablet, req)
 		if err != nil {
 			return vterrors.Wrapf(err, "ReadVReplicationWorkflows(%v, %+v)", sourcePrimary.Tablet, req)
 		}
@@ -258,11 +261,29 @@ func (rs *resharder) identifyRuleType(rule *binlogdatapb.Rule) (StreamType, erro
 func (rs *resharder) copySchema(ctx context.Context) error {
 	oneSource := rs.sourceShards[0].PrimaryAlias
 	err := rs.forAll(rs.targetShards, func(target *topo.ShardInfo) error {
-		return rs.s.CopySchemaShard(ctx, oneSource, []string{"/.*"}, nil, false, rs.keyspace, target.ShardName(), 1*time.Second, false)
+		return rs.s.CopySchemaShard(ctx, oneSource, []string{"/.*"}, nil, false, rs.keyspace, target.ShardName(), 1*time.Second, false, rs.allowSchemaDiff)
 	})
 	return err
 }
 
+// validateSchema compares the schema on each target shard with that of the
+// source. It is used when the schema copy is skipped because the schema was
+// created on the target shards beforehand.
+func (rs *resharder) validateSchema(ctx context.Context) error {
+	oneSource := rs.sourceShards[0].PrimaryAlias
+	return rs.forAll(rs.targetShards, func(target *topo.ShardInfo) error {
+		diffs, err := schematools.CompareSchemas(ctx, rs.s.ts, rs.s.tmClient(), oneSource, target.PrimaryAlias, []string{"/.*"}, nil, false)
+		if err != nil {
+			return err
+		}
+		if len(diffs) > 0 {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "schema on target shard %s does not match the source: %s",
+				target.ShardName(), strings.Join(diffs, "; "))
+		}
+		return nil
+	})
+}
+
 // createStreams creates all of the VReplication streams that
 // need to now exist on the new shards.
 func (rs *resharder) createStreams(ctx context.Context) error {
@@ -313,7 +334,7 @@ func (rs *resharder) createStreams(ctx context.Context) error {
 				rs.deferSecondaryKeys)
 		}
 		query := ig.String()
-		if _, err := rs.s.tmc.VReplicationExec(ctx, targetPrimary.Tablet, query); err != nil {
+		if _, err := rs.s.tmClient().VReplicationExec(ctx, targetPrimary.Tablet, query); err != nil {
 			return vterrors.Wrapf(err, "VReplicationExec(%v, %s)", targetPrimary.Tablet, query)
 		}
 		return nil
@@ -336,7 +357,7 @@ func (rs *resharder) startStreams(ctx context.Context) error {
 			Message:      textutil.SimulatedNullString,
 			StopPosition: textutil.SimulatedNullString,
 		}
-		if _, err := rs.s.tmc.UpdateVReplicationWorkflows(ctx, targetPrimary.Tablet, req); err != nil {
+		if _, err := rs.s.tmClient().UpdateVReplicationWorkflows(ctx, targetPrimary.Tablet, req); err != nil {
 			return vterrors.Wrapf(err, "UpdateVReplicationWorkflows(%v, 'state='%s')",
 				targetPrimary.Tablet, binlogdatapb.VReplicationWorkflowState_Running.String())
 		}
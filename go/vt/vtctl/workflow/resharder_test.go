@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
@@ -63,9 +64,12 @@ func TestReshardCreate(t *testing.T) {
 	testcases := []struct {
 		name                           string
 		sourceKeyspace, targetKeyspace *testKeyspace
+		skipSchemaCopy                 bool
+		validateSchemaOnSkip           bool
 		preFunc                        func(env *testEnv)
 		want                           *vtctldatapb.WorkflowStatusResponse
 		wantErr                        string
+		wantErrIs                      error
 	}{
 		{
 			name: "basic",
@@ -99,6 +103,7 @@ func TestReshardCreate(t *testing.T) {
 					},
 				},
 				TrafficState: "Reads Not Switched. Writes Not Switched",
+				OnDdl:        "IGNORE",
 			},
 		},
 		{
@@ -118,7 +123,71 @@ func TestReshardCreate(t *testing.T) {
 				})
 				require.NoError(t, err)
 			},
-			wantErr: "buildResharder: target shard -80 has no primary tablet",
+			wantErr: "shard has no primary: -80",
+		},
+		{
+			name: "can't read a target primary tablet",
+			sourceKeyspace: &testKeyspace{
+				KeyspaceName: sourceKeyspaceName,
+				ShardNames:   []string{"0"},
+			},
+			targetKeyspace: &testKeyspace{
+				KeyspaceName: targetKeyspaceName,
+				ShardNames:   []string{"-80", "80-"},
+			},
+			preFunc: func(env *testEnv) {
+				// The duplicate workflow check can't be done on the shard, so
+				// the workflow must not be created.
+				err := env.ts.DeleteTablet(ctx, &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID + tabletUIDStep})
+				require.NoError(t, err)
+			},
+			wantErr: fmt.Sprintf("validateWorkflowName.GetTablet: node doesn't exist: tablets/%s-0000000210/Tablet", defaultCellName),
+		},
+		{
+			name: "workflow already exists",
+			sourceKeyspace: &testKeyspace{
+				KeyspaceName: sourceKeyspaceName,
+				ShardNames:   []string{"0"},
+			},
+			targetKeyspace: &testKeyspace{
+				KeyspaceName: targetKeyspaceName,
+				ShardNames:   []string{"-80", "80-"},
+			},
+			preFunc: func(env *testEnv) {
+				env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID] = &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+					Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+						{Workflow: workflowName},
+					},
+				}
+			},
+			wantErr:   fmt.Sprintf("workflow already exists: workflow %s already exists in keyspace %s on tablet %s-0000000200", workflowName, targetKeyspaceName, defaultCellName),
+			wantErrIs: ErrWorkflowAlreadyExists,
+		},
+		{
+			name: "skip schema copy with a schema mismatch",
+			sourceKeyspace: &testKeyspace{
+				KeyspaceName: sourceKeyspaceName,
+				ShardNames:   []string{"0"},
+			},
+			targetKeyspace: &testKeyspace{
+				KeyspaceName: targetKeyspaceName,
+				ShardNames:   []string{"-80", "80-"},
+			},
+			skipSchemaCopy:       true,
+			validateSchemaOnSkip: true,
+			preFunc: func(env *testEnv) {
+				// The target has a table that the source does not.
+				env.tmc.tabletSchemas[startingTargetTabletUID] = &tabletmanagerdatapb.SchemaDefinition{
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, PRIMARY KEY (id))", tableName),
+							Type:   tmutils.TableBaseTable,
+						},
+					},
+				}
+			},
+			wantErr: "validateSchema: schema on target shard -80 does not match the source: dest has an extra table named t1",
 		},
 	}
 	for _, tc := range testcases {
@@ -137,6 +206,9 @@ func TestReshardCreate(t *testing.T) {
 				SourceShards: tc.sourceKeyspace.ShardNames,
 				TargetShards: tc.targetKeyspace.ShardNames,
 				Cells:        []string{env.cell},
+
+				SkipSchemaCopy:       tc.skipSchemaCopy,
+				ValidateSchemaOnSkip: tc.validateSchemaOnSkip,
 			}
 
 			for i := range tc.sourceKeyspace.ShardNames {
@@ -181,6 +253,9 @@ func TestReshardCreate(t *testing.T) {
 			res, err := env.ws.ReshardCreate(ctx, req)
 			if tc.wantErr != "" {
 				require.EqualError(t, err, tc.wantErr)
+				if tc.wantErrIs != nil {
+					require.ErrorIs(t, err, tc.wantErrIs)
+				}
 				return
 			}
 			require.NoError(t, err)
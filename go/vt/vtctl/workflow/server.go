@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
@@ -40,6 +41,7 @@ import (
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/textutil"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/concurrency"
@@ -93,6 +95,47 @@ type tableCopyProgress struct {
 // copyProgress stores the tableCopyProgress for all tables still being copied
 type copyProgress map[string]*tableCopyProgress
 
+const (
+	// copyStateTablesMaxRows is the minimum row limit used when reading the
+	// tables that a stream is still copying.
+	copyStateTablesMaxRows = 1000
+	// copyStateTablesMaxRowsLimit is the largest row limit we will retry
+	// with when a stream is copying more tables than the current limit.
+	copyStateTablesMaxRowsLimit = 1000000
+)
+
+// vreplicationTables are the sidecar database tables used by VReplication
+// and VDiff which grow over time.
+var vreplicationTables = []string{"copy_state", "vreplication", "vreplication_log", "vdiff", "vdiff_log", "vdiff_table"}
+
+// defaultCopyThroughputSampleInterval is how long we wait, by default, between
+// the two samples of the rows copied by a workflow that are used to calculate
+// its throughput.
+const defaultCopyThroughputSampleInterval = 10 * time.Second
+
+// TableCompletionEstimate is the estimated time remaining to finish copying a
+// single table in a workflow.
+type TableCompletionEstimate struct {
+	RowsRemaining  int64
+	BytesRemaining int64
+	// ETA is how long it will take to copy the remaining rows at the
+	// workflow's current throughput.
+	ETA time.Duration
+}
+
+// WorkflowCompletionEstimate is the estimated time remaining for a workflow
+// to finish its copy phase.
+type WorkflowCompletionEstimate struct {
+	// Unknown is set when no estimate could be made, with the reason why in
+	// UnknownReason.
+	Unknown       bool
+	UnknownReason string
+	RowsPerSecond float64
+	ETA           time.Duration
+	// Tables contains the estimate for each table still being copied.
+	Tables map[string]*TableCompletionEstimate
+}
+
 // sequenceMetadata contains all of the relevant metadata for a sequence that
 // is being used by a table involved in a vreplication workflow.
 type sequenceMetadata struct {
@@ -110,6 +153,19 @@ type sequenceMetadata struct {
 	usingTableDefinition *vschemapb.Table
 }
 
+// WorkflowSequence describes a sequence used by one of a workflow's tables
+// for its auto-increment column.
+type WorkflowSequence struct {
+	// The name of the table using the sequence.
+	Table string
+	// The auto-increment column in the table.
+	Column string
+	// The name of the backing sequence table.
+	BackingTable string
+	// The keyspace where the backing sequence table lives.
+	BackingKeyspace string
+}
+
 // vdiffOutput holds the data from all shards that is needed to generate
 // the full summary results of the vdiff in the vdiff show command output.
 type vdiffOutput struct {
@@ -124,14 +180,24 @@ const (
 	cannotSwitchHighLag             = "replication lag %ds is higher than allowed lag %ds"
 	cannotSwitchFailedTabletRefresh = "could not refresh all of the tablets involved in the operation:\n%s"
 	cannotSwitchFrozen              = "workflow is frozen"
+	cannotSwitchMissingShardStreams = "serving target shards %s do not have any streams for the workflow"
 
 	// Number of LOCK TABLES cycles to perform on the sources during SwitchWrites.
 	lockTablesCycles = 2
 	// Time to wait between LOCK TABLES cycles on the sources during SwitchWrites.
 	lockTablesCycleDelay = time.Duration(100 * time.Millisecond)
+	// Default number of times to try each LOCK TABLES on a source when it
+	// fails with a transient error, such as a deadlock or lock wait timeout.
+	defaultLockTablesMaxAttempts = 3
+	// Default time to wait before retrying a LOCK TABLES on a source.
+	defaultLockTablesRetryDelay = time.Duration(500 * time.Millisecond)
 
 	// Default duration used for lag, timeout, etc.
 	defaultDuration = 30 * time.Second
+
+	// Default number of statements that CopySchemaShard applies between each
+	// progress message.
+	defaultCopySchemaProgressInterval = 100
 )
 
 var (
@@ -148,6 +214,9 @@ var (
 	ErrMultipleTargetKeyspaces   = errors.New("multiple target keyspaces for a single workflow")
 	ErrWorkflowNotFullySwitched  = errors.New("cannot complete workflow because you have not yet switched all read and write traffic")
 	ErrWorkflowPartiallySwitched = errors.New("cannot cancel workflow because you have already switched some or all read and write traffic")
+	// ErrWorkflowAlreadyExists occurs when trying to create a workflow that
+	// already exists on one or more of the target shards.
+	ErrWorkflowAlreadyExists = errors.New("workflow already exists")
 )
 
 // Server provides an API to work with Vitess workflows, like vreplication
@@ -155,19 +224,225 @@ var (
 type Server struct {
 	ts  *topo.Server
 	tmc tmclient.TabletManagerClient
+	// tmcFactory, if set, is used to get the TabletManagerClient for each
+	// use instead of tmc.
+	tmcFactory func() tmclient.TabletManagerClient
 	// Limit the number of concurrent background goroutines if needed.
 	sem *semaphore.Weighted
 	env *vtenv.Environment
+	// logger is used by workflow operations that were not given their
+	// own logger with WithLogger.
+	logger logutil.Logger
+	// keyspaceDefaultTimeouts, if set, holds the default timeout to use for
+	// requests on a keyspace when the request does not specify one.
+	keyspaceDefaultTimeouts map[string]time.Duration
+	// copySchemaProgressInterval is the number of statements CopySchemaShard
+	// applies between each progress message it logs. If it is not positive
+	// then no progress is logged.
+	copySchemaProgressInterval int
+	// tabletRefreshTimeout, if set, is used instead of
+	// shardTabletRefreshTimeout when refreshing the tablets in a shard.
+	tabletRefreshTimeout time.Duration
+	// copySchemaReloadConcurrency, if positive, limits how many of the
+	// destination shard's tablets CopySchemaShard reloads the schema on at
+	// the same time.
+	copySchemaReloadConcurrency int
+	// copyThroughputSampleInterval, if set, is used instead of
+	// defaultCopyThroughputSampleInterval by EstimateWorkflowCompletion.
+	copyThroughputSampleInterval time.Duration
+	// lockTablesMaxAttempts and lockTablesRetryDelay, if set, are used
+	// instead of defaultLockTablesMaxAttempts and defaultLockTablesRetryDelay
+	// when retrying a LOCK TABLES on a source while switching writes.
+	lockTablesMaxAttempts int
+	lockTablesRetryDelay  time.Duration
+}
+
+// ServerOption configures optional behavior of a Server.
+type ServerOption func(s *Server)
+
+// WithTMCFactory returns a ServerOption that makes the Server obtain its
+// TabletManagerClient from the given factory every time it needs one, rather
+// than always using the client passed to NewServer. This allows tests to wrap
+// the client's calls, e.g. to inject latency or errors.
+func WithTMCFactory(factory func() tmclient.TabletManagerClient) ServerOption {
+	return func(s *Server) {
+		s.tmcFactory = factory
+	}
+}
+
+// WithKeyspaceDefaultTimeouts returns a ServerOption that sets the default
+// timeout, by keyspace name, used when a request on that keyspace does not
+// specify its own. Keyspaces that are not in the map use the package default.
+func WithKeyspaceDefaultTimeouts(timeouts map[string]time.Duration) ServerOption {
+	return func(s *Server) {
+		s.keyspaceDefaultTimeouts = maps.Clone(timeouts)
+	}
+}
+
+// WithCopySchemaProgressInterval returns a ServerOption that sets how many
+// statements CopySchemaShard applies between each progress message that it
+// logs to the Server's Logger. Set it to 0 to disable progress logging.
+func WithCopySchemaProgressInterval(statements int) ServerOption {
+	return func(s *Server) {
+		s.copySchemaProgressInterval = statements
+	}
+}
+
+// WithTabletRefreshTimeout returns a ServerOption that sets how long to wait
+// for the tablets in a shard to refresh their state, e.g. after the denied
+// tables for the shard are updated. This may need to be raised for shards
+// with many tablets.
+func WithTabletRefreshTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.tabletRefreshTimeout = timeout
+	}
+}
+
+// WithCopySchemaReloadConcurrency returns a ServerOption that limits how
+// many tablets in the destination shard CopySchemaShard reloads the schema
+// on at the same time once the schema has been copied, so that shards with
+// many replicas are not all reloaded at once. By default there is no limit.
+func WithCopySchemaReloadConcurrency(concurrency int) ServerOption {
+	return func(s *Server) {
+		s.copySchemaReloadConcurrency = concurrency
+	}
+}
+
+// WithCopyThroughputSampleInterval returns a ServerOption that sets how long
+// EstimateWorkflowCompletion waits between the two samples of the rows copied
+// by a workflow that it uses to calculate the workflow's throughput. Longer
+// intervals give more stable estimates but make each call take longer.
+func WithCopyThroughputSampleInterval(interval time.Duration) ServerOption {
+	return func(s *Server) {
+		s.copyThroughputSampleInterval = interval
+	}
+}
+
+// WithLockTablesRetries returns a ServerOption that sets how many times each
+// LOCK TABLES on a source is tried while switching writes when it fails with
+// a transient error, such as a deadlock or lock wait timeout, and how long to
+// wait between the attempts. Use 1 attempt to disable the retries.
+func WithLockTablesRetries(maxAttempts int, retryDelay time.Duration) ServerOption {
+	return func(s *Server) {
+		s.lockTablesMaxAttempts = maxAttempts
+		s.lockTablesRetryDelay = retryDelay
+	}
 }
 
 // NewServer returns a new server instance with the given topo.Server and
 // TabletManagerClient.
-func NewServer(env *vtenv.Environment, ts *topo.Server, tmc tmclient.TabletManagerClient) *Server {
-	return &Server{
-		ts:  ts,
-		tmc: tmc,
-		env: env,
+func NewServer(env *vtenv.Environment, ts *topo.Server, tmc tmclient.TabletManagerClient, opts ...ServerOption) *Server {
+	s := &Server{
+		ts:                         ts,
+		tmc:                        tmc,
+		env:                        env,
+		logger:                     logutil.NewConsoleLogger(),
+		copySchemaProgressInterval: defaultCopySchemaProgressInterval,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// tmClient returns the TabletManagerClient to use for a call.
+func (s *Server) tmClient() tmclient.TabletManagerClient {
+	if s.tmcFactory != nil {
+		return s.tmcFactory()
+	}
+	return s.tmc
+}
+
+// defaultTimeout returns the timeout to use for requests on the given
+// keyspace that do not specify their own.
+func (s *Server) defaultTimeout(keyspace string) time.Duration {
+	if timeout, ok := s.keyspaceDefaultTimeouts[keyspace]; ok {
+		return timeout
+	}
+	return defaultDuration
+}
+
+// refreshTimeout returns how long to wait for the tablets in a shard to
+// refresh their state.
+func (s *Server) refreshTimeout() time.Duration {
+	if s.tabletRefreshTimeout > 0 {
+		return s.tabletRefreshTimeout
+	}
+	return shardTabletRefreshTimeout
+}
+
+// throughputSampleInterval returns how long to wait between the samples used
+// to calculate a workflow's copy throughput.
+func (s *Server) throughputSampleInterval() time.Duration {
+	if s.copyThroughputSampleInterval > 0 {
+		return s.copyThroughputSampleInterval
+	}
+	return defaultCopyThroughputSampleInterval
+}
+
+// lockTablesAttempts returns how many times to try each LOCK TABLES on a
+// source when it fails with a transient error.
+func (s *Server) lockTablesAttempts() int {
+	if s.lockTablesMaxAttempts > 0 {
+		return s.lockTablesMaxAttempts
+	}
+	return defaultLockTablesMaxAttempts
+}
+
+// lockTablesRetryWait returns how long to wait before retrying a LOCK TABLES
+// on a source.
+func (s *Server) lockTablesRetryWait() time.Duration {
+	if s.lockTablesRetryDelay > 0 {
+		return s.lockTablesRetryDelay
+	}
+	return defaultLockTablesRetryDelay
+}
+
+// copySchemaReloadSemaphore returns the semaphore used to limit the
+// concurrent schema reloads done by CopySchemaShard, or nil when they are
+// not limited.
+func (s *Server) copySchemaReloadSemaphore() *semaphore.Weighted {
+	if s.copySchemaReloadConcurrency > 0 {
+		return semaphore.NewWeighted(int64(s.copySchemaReloadConcurrency))
 	}
+	return nil
+}
+
+// Logger returns the Server's default logger.
+func (s *Server) Logger() logutil.Logger {
+	return s.logger
+}
+
+// WorkflowActionOption configures optional behavior of a single workflow
+// action, such as WorkflowSwitchTraffic or MoveTablesComplete.
+type WorkflowActionOption func(opts *workflowActionOptions)
+
+type workflowActionOptions struct {
+	logger logutil.Logger
+}
+
+// WithLogger returns a WorkflowActionOption that makes the action log to
+// the given logger instead of the Server's default logger, e.g. to capture
+// the logs of one request and return them to its caller.
+func WithLogger(logger logutil.Logger) WorkflowActionOption {
+	return func(opts *workflowActionOptions) {
+		opts.logger = logger
+	}
+}
+
+// processWorkflowActionOptions applies the given options on top of the
+// Server's defaults.
+func (s *Server) processWorkflowActionOptions(opts []WorkflowActionOption) *workflowActionOptions {
+	options := &workflowActionOptions{
+		logger: s.Logger(),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.logger == nil {
+		options.logger = s.Logger()
+	}
+	return options
 }
 
 func (s *Server) SQLParser() *sqlparser.Parser {
@@ -187,13 +462,17 @@ func (s *Server) CheckReshardingJournalExistsOnTablet(ctx context.Context, table
 		exists  bool
 	)
 
-	query := fmt.Sprintf("select val from _vt.resharding_journal where id=%v", migrationID)
-	p3qr, err := s.tmc.VReplicationExec(ctx, tablet, query)
+	// The id is the table's primary key, so we should never see more than one
+	// row. We read at most two so that we can report it if we do.
+	query := fmt.Sprintf("select val from _vt.resharding_journal where id=%v limit 2", migrationID)
+	p3qr, err := s.tmClient().VReplicationExec(ctx, tablet, query)
 	if err != nil {
 		return nil, false, err
 	}
 
-	if len(p3qr.Rows) != 0 {
+	switch len(p3qr.Rows) {
+	case 0:
+	case 1:
 		qr := sqltypes.Proto3ToResult(p3qr)
 		qrBytes, err := qr.Rows[0][0].ToBytes()
 		if err != nil {
@@ -204,6 +483,9 @@ func (s *Server) CheckReshardingJournalExistsOnTablet(ctx context.Context, table
 		}
 
 		exists = true
+	default:
+		return nil, false, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "found more than one resharding journal with id %d on tablet %s",
+			migrationID, topoproto.TabletAliasString(tablet.Alias))
 	}
 
 	return &journal, exists, nil
@@ -303,6 +585,55 @@ func (s *Server) GetCellsWithTableReadsSwitched(
 		return nil, nil, err
 	}
 
+	for _, cell := range cells {
+		srvVSchema, err := s.ts.GetSrvVSchema(ctx, cell)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, switched, err := tableReadsRoutingRule(srvVSchema.RoutingRules, keyspace, table, tabletType)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if switched {
+			cellsSwitched = append(cellsSwitched, cell)
+		} else {
+			cellsNotSwitched = append(cellsNotSwitched, cell)
+		}
+	}
+
+	return cellsSwitched, cellsNotSwitched, nil
+}
+
+// GetTableReadsSwitchedInCell returns whether table reads have been switched
+// for the given tablet type according to the SrvVSchema in the given cell,
+// using the same definition as GetCellsWithTableReadsSwitched. It also
+// returns the routing rule for the (table, tablet_type) in that SrvVSchema,
+// or nil if there is none, so that the view of different cells can be
+// compared when they do not agree.
+func (s *Server) GetTableReadsSwitchedInCell(
+	ctx context.Context,
+	keyspace string,
+	table string,
+	tabletType topodatapb.TabletType,
+	cell string,
+) (switched bool, rule *vschemapb.RoutingRule, err error) {
+	srvVSchema, err := s.ts.GetSrvVSchema(ctx, cell)
+	if err != nil {
+		return false, nil, vterrors.Wrapf(err, "failed to get the SrvVSchema in cell %s", cell)
+	}
+	rule, switched, err = tableReadsRoutingRule(srvVSchema.RoutingRules, keyspace, table, tabletType)
+	if err != nil {
+		return false, nil, err
+	}
+	return switched, rule, nil
+}
+
+// tableReadsRoutingRule returns the routing rule for the (table, tablet_type)
+// in the given routing rules, if any, along with whether it points to the
+// given keyspace.
+func tableReadsRoutingRule(rules *vschemapb.RoutingRules, keyspace, table string, tabletType topodatapb.TabletType) (*vschemapb.RoutingRule, bool, error) {
 	getKeyspace := func(ruleTarget string) (string, error) {
 		arr := strings.Split(ruleTarget, ".")
 		if len(arr) != 2 {
@@ -312,49 +643,487 @@ func (s *Server) GetCellsWithTableReadsSwitched(
 		return arr[0], nil
 	}
 
-	for _, cell := range cells {
+	ruleName := fmt.Sprintf("%s.%s@%s", keyspace, table, strings.ToLower(tabletType.String()))
+	for _, rule := range rules.GetRules() {
+		if rule.FromTable != ruleName {
+			continue
+		}
+		for _, to := range rule.ToTables {
+			ks, err := getKeyspace(to)
+			if err != nil {
+				log.Errorf(err.Error())
+				return nil, false, err
+			}
+
+			if ks == keyspace {
+				return rule, true, nil // if one table in the workflow switched, we are done.
+			}
+		}
+		return rule, false, nil
+	}
+	return nil, false, nil
+}
+
+// RoutingRulesDiff describes how the global routing rules differ from an
+// earlier snapshot of them, as returned by DiffRoutingRules. The rules are
+// keyed by their from table.
+type RoutingRulesDiff struct {
+	// Added contains the rules that are not in the snapshot.
+	Added map[string][]string
+	// Removed contains the rules that are only in the snapshot.
+	Removed map[string][]string
+	// Changed contains the rules whose to tables differ from the snapshot,
+	// with the current to tables.
+	Changed map[string][]string
+}
+
+// IsEmpty returns true if the routing rules have not changed.
+func (d *RoutingRulesDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffRoutingRules compares the given snapshot of the global routing rules,
+// as returned by topotools.GetRoutingRules, against the current ones. This
+// can be used to confirm that an operation such as SwitchTraffic only made
+// the expected routing changes.
+func (s *Server) DiffRoutingRules(ctx context.Context, before map[string][]string) (*RoutingRulesDiff, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.DiffRoutingRules")
+	defer span.Finish()
+
+	after, err := topotools.GetRoutingRules(ctx, s.ts)
+	if err != nil {
+		return nil, err
+	}
+	return computeRoutingRulesDiff(before, after), nil
+}
+
+// computeRoutingRulesDiff returns the difference between the before and after
+// routing rules.
+func computeRoutingRulesDiff(before, after map[string][]string) *RoutingRulesDiff {
+	diff := &RoutingRulesDiff{
+		Added:   make(map[string][]string),
+		Removed: make(map[string][]string),
+		Changed: make(map[string][]string),
+	}
+	for from, toTables := range after {
+		prevToTables, ok := before[from]
+		switch {
+		case !ok:
+			diff.Added[from] = toTables
+		case !slices.Equal(prevToTables, toTables):
+			diff.Changed[from] = toTables
+		}
+	}
+	for from, toTables := range before {
+		if _, ok := after[from]; !ok {
+			diff.Removed[from] = toTables
+		}
+	}
+	return diff
+}
+
+// RoutingRuleSource identifies the kind of routing rule that determines where
+// the queries for a table are sent.
+type RoutingRuleSource string
+
+const (
+	// RoutingRuleSourceNone means that no routing rule applies and the queries
+	// go to the table's own keyspace.
+	RoutingRuleSourceNone RoutingRuleSource = "none"
+	// RoutingRuleSourceKeyspace means that a keyspace routing rule, as used by
+	// multi-tenant migrations, applies.
+	RoutingRuleSourceKeyspace RoutingRuleSource = "keyspace_routing_rules"
+	// RoutingRuleSourceShard means that shard routing rules, as used by partial
+	// migrations, apply.
+	RoutingRuleSourceShard RoutingRuleSource = "shard_routing_rules"
+	// RoutingRuleSourceTable means that a table routing rule applies.
+	RoutingRuleSourceTable RoutingRuleSource = "routing_rules"
+)
+
+// TableRoutingExplanation describes where the queries for a table, sent to a
+// given tablet type, are routed, as determined by ExplainTableRouting.
+type TableRoutingExplanation struct {
+	Keyspace   string
+	Table      string
+	TabletType topodatapb.TabletType
+	// Cell is the cell whose SrvVSchema was used, or empty if the global
+	// routing rules were used.
+	Cell string
+	// Source is the kind of rule that won and Rule is the rule itself, e.g.
+	// "customer.orders@replica -> commerce.orders".
+	Source RoutingRuleSource
+	Rule   string
+	// TargetKeyspace is the keyspace that the queries are sent to. When shard
+	// routing rules apply it is empty and ShardTargets has the keyspace
+	// for each shard instead.
+	TargetKeyspace string
+	ShardTargets   map[string]string
+}
+
+// ExplainTableRouting reports where the queries for the given table and tablet
+// type are routed by the current routing rules. The rules are checked with the
+// same precedence that getWorkflowState uses: keyspace routing rules first,
+// then shard routing rules, and finally table routing rules. If a cell is
+// given then the rules in that cell's SrvVSchema are used, as that is what the
+// vtgates in the cell route with, otherwise the global rules are used.
+func (s *Server) ExplainTableRouting(ctx context.Context, keyspace, table string, tabletType topodatapb.TabletType, cell string) (*TableRoutingExplanation, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ExplainTableRouting")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("table", table)
+	span.Annotate("tablet_type", tabletType)
+	span.Annotate("cell", cell)
+
+	var (
+		rules         *vschemapb.RoutingRules
+		shardRules    *vschemapb.ShardRoutingRules
+		keyspaceRules *vschemapb.KeyspaceRoutingRules
+		err           error
+	)
+	if cell != "" {
 		srvVSchema, err := s.ts.GetSrvVSchema(ctx, cell)
 		if err != nil {
-			return nil, nil, err
+			return nil, vterrors.Wrapf(err, "failed to get the SrvVSchema in cell %s", cell)
+		}
+		rules, shardRules, keyspaceRules = srvVSchema.GetRoutingRules(), srvVSchema.GetShardRoutingRules(), srvVSchema.GetKeyspaceRoutingRules()
+	} else {
+		if rules, err = s.ts.GetRoutingRules(ctx); err != nil {
+			return nil, err
+		}
+		if shardRules, err = s.ts.GetShardRoutingRules(ctx); err != nil {
+			return nil, err
+		}
+		if keyspaceRules, err = s.ts.GetKeyspaceRoutingRules(ctx); err != nil {
+			return nil, err
 		}
+	}
 
-		var (
-			found    bool
-			switched bool
-		)
+	explanation := &TableRoutingExplanation{
+		Keyspace:   keyspace,
+		Table:      table,
+		TabletType: tabletType,
+		Cell:       cell,
+	}
+	suffix := getTabletTypeSuffix(tabletType)
 
-		for _, rule := range srvVSchema.RoutingRules.Rules {
-			ruleName := fmt.Sprintf("%s.%s@%s", keyspace, table, strings.ToLower(tabletType.String()))
-			if rule.FromTable == ruleName {
-				found = true
+	fromKeyspace := keyspace + suffix
+	for _, rule := range keyspaceRules.GetRules() {
+		if rule.FromKeyspace == fromKeyspace {
+			explanation.Source = RoutingRuleSourceKeyspace
+			explanation.Rule = fmt.Sprintf("%s -> %s", rule.FromKeyspace, rule.ToKeyspace)
+			explanation.TargetKeyspace = rule.ToKeyspace
+			return explanation, nil
+		}
+	}
 
-				for _, to := range rule.ToTables {
-					ks, err := getKeyspace(to)
-					if err != nil {
-						log.Errorf(err.Error())
-						return nil, nil, err
-					}
+	for _, rule := range shardRules.GetRules() {
+		if rule.FromKeyspace != keyspace {
+			continue
+		}
+		if explanation.ShardTargets == nil {
+			explanation.ShardTargets = make(map[string]string)
+		}
+		explanation.ShardTargets[rule.Shard] = rule.ToKeyspace
+	}
+	if len(explanation.ShardTargets) > 0 {
+		shards := maps.Keys(explanation.ShardTargets)
+		sort.Strings(shards)
+		shardRuleStrs := make([]string, 0, len(shards))
+		for _, shard := range shards {
+			shardRuleStrs = append(shardRuleStrs, fmt.Sprintf("%s.%s -> %s", keyspace, shard, explanation.ShardTargets[shard]))
+		}
+		explanation.Source = RoutingRuleSourceShard
+		explanation.Rule = strings.Join(shardRuleStrs, ", ")
+		return explanation, nil
+	}
 
-					if ks == keyspace {
-						switched = true
-						break // if one table in the workflow switched, we are done.
-					}
-				}
-			}
+	// Like vtgate, prefer the rule for the qualified table name and then the
+	// unqualified one, and for each the tablet type specific rule.
+	var fromTables []string
+	for _, name := range []string{fmt.Sprintf("%s.%s", keyspace, table), table} {
+		if suffix != "" {
+			fromTables = append(fromTables, name+suffix)
+		}
+		fromTables = append(fromTables, name)
+	}
+	tableRules := make(map[string][]string, len(rules.GetRules()))
+	for _, rule := range rules.GetRules() {
+		tableRules[rule.FromTable] = rule.ToTables
+	}
+	for _, fromTable := range fromTables {
+		toTables, ok := tableRules[fromTable]
+		if !ok || len(toTables) == 0 {
+			continue
+		}
+		targetKeyspace, _, ok := strings.Cut(toTables[0], ".")
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "rule target is not correctly formatted: %s", toTables[0])
+		}
+		explanation.Source = RoutingRuleSourceTable
+		explanation.Rule = fmt.Sprintf("%s -> %s", fromTable, strings.Join(toTables, ","))
+		explanation.TargetKeyspace = targetKeyspace
+		return explanation, nil
+	}
 
-			if found {
-				break
-			}
+	explanation.Source = RoutingRuleSourceNone
+	explanation.TargetKeyspace = keyspace
+	return explanation, nil
+}
+
+// ShardPrimaryStatus reports whether the primary tablet of a shard could be
+// reached, as determined by ValidateKeyspacePrimaries.
+type ShardPrimaryStatus struct {
+	Shard        string
+	PrimaryAlias *topodatapb.TabletAlias
+	// Position is the current replication position of the primary tablet,
+	// which is only set when the primary was reachable.
+	Position  string
+	Reachable bool
+	// Error describes why the primary tablet could not be reached.
+	Error error
+}
+
+// ValidateKeyspacePrimaries confirms that each serving shard in the given
+// keyspace has a primary tablet and that it responds to a PrimaryPosition
+// request. It returns a reachability report for each shard, keyed by shard
+// name, along with an error if any of the primaries could not be reached.
+// This can be used as a health check before creating a workflow.
+func (s *Server) ValidateKeyspacePrimaries(ctx context.Context, keyspace string) (map[string]*ShardPrimaryStatus, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ValidateKeyspacePrimaries")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+
+	shards, err := s.ts.FindAllShardsInKeyspace(ctx, keyspace, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu       sync.Mutex
+		report   = make(map[string]*ShardPrimaryStatus, len(shards))
+		rec      = &concurrency.AllErrorRecorder{}
+		eg, ectx = errgroup.WithContext(ctx)
+	)
+	eg.SetLimit(topo.DefaultConcurrency)
+	for shardName, si := range shards {
+		if !si.IsPrimaryServing {
+			continue
 		}
+		eg.Go(func() error {
+			status := &ShardPrimaryStatus{
+				Shard:        shardName,
+				PrimaryAlias: si.PrimaryAlias,
+			}
+			status.Position, status.Error = s.pingShardPrimary(ectx, si)
+			status.Reachable = status.Error == nil
+			if status.Error != nil {
+				rec.RecordError(vterrors.Wrapf(status.Error, "%s/%s", keyspace, shardName))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report[shardName] = status
+			// We want a status for every shard, so we don't fail the group.
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	if rec.HasErrors() {
+		return report, vterrors.Wrapf(rec.AggrError(vterrors.Aggregate), "not all primary tablets in the %s keyspace are reachable", keyspace)
+	}
+	return report, nil
+}
 
-		if switched {
-			cellsSwitched = append(cellsSwitched, cell)
+// pingShardPrimary returns the current replication position of the shard's
+// primary tablet, or an error if the shard has no primary or it could not be
+// reached.
+func (s *Server) pingShardPrimary(ctx context.Context, si *topo.ShardInfo) (string, error) {
+	if si.PrimaryAlias == nil {
+		return "", vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard has no primary")
+	}
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+	primary, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return "", vterrors.Wrapf(err, "failed to get primary tablet %s", topoproto.TabletAliasString(si.PrimaryAlias))
+	}
+	pos, err := s.tmClient().PrimaryPosition(ctx, primary.Tablet)
+	if err != nil {
+		return "", vterrors.Wrapf(err, "failed to get the primary position from tablet %s", topoproto.TabletAliasString(si.PrimaryAlias))
+	}
+	return pos, nil
+}
+
+// RebuildShardRoutingRules recomputes the shard routing rules for a partial
+// (shard-by-shard) MoveTables workflow from the current state of the workflow
+// and saves them. This can be used to repair the shard routing rules after
+// they were changed manually or left behind by an aborted traffic switch.
+// If writes have been switched for the workflow -- which is the case when
+// the workflow is frozen -- then the source shards are routed to the target
+// keyspace, otherwise the target shards are routed to the source keyspace.
+// The changes made to the rules are returned and when dryRun is set, they
+// are not saved.
+func (s *Server) RebuildShardRoutingRules(ctx context.Context, keyspace, workflow string, dryRun bool) ([]string, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.RebuildShardRoutingRules")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("dry_run", dryRun)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	if !ts.isPartialMigration {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s.%s is not a partial MoveTables workflow", keyspace, workflow)
+	}
+
+	srr, err := topotools.GetShardRoutingRules(ctx, s.ts)
+	if err != nil {
+		return nil, err
+	}
+	rebuilt := make(map[string]string, len(srr))
+	for from, to := range srr {
+		rebuilt[from] = to
+	}
+	for _, si := range ts.SourceShards() {
+		fromSource := fmt.Sprintf("%s.%s", ts.SourceKeyspaceName(), si.ShardName())
+		fromTarget := fmt.Sprintf("%s.%s", ts.TargetKeyspaceName(), si.ShardName())
+		delete(rebuilt, fromSource)
+		delete(rebuilt, fromTarget)
+		if ts.frozen {
+			rebuilt[fromSource] = ts.TargetKeyspaceName()
 		} else {
-			cellsNotSwitched = append(cellsNotSwitched, cell)
+			rebuilt[fromTarget] = ts.SourceKeyspaceName()
 		}
 	}
 
-	return cellsSwitched, cellsNotSwitched, nil
+	changes := diffShardRoutingRules(srr, rebuilt)
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+	if err := topotools.SaveShardRoutingRules(ctx, s.ts, rebuilt); err != nil {
+		return nil, err
+	}
+	if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ResetWorkflowRoutingRules recreates the table routing rules for a
+// MoveTables workflow as they were when the workflow was created, with all
+// of the workflow's tables routed to the source keyspace. This can be used
+// to restore routing rules that were deleted or changed by mistake before
+// any traffic was switched, or to return the workflow to that state.
+// The changes made to the rules are returned and when dryRun is set, they
+// are not saved.
+func (s *Server) ResetWorkflowRoutingRules(ctx context.Context, keyspace, workflow string, dryRun bool) ([]string, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ResetWorkflowRoutingRules")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("dry_run", dryRun)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ts.MigrationType() != binlogdatapb.MigrationType_TABLES:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s.%s is not a MoveTables workflow", keyspace, workflow)
+	case ts.IsPartialMigration():
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s.%s is a partial MoveTables workflow which uses shard routing rules, use RebuildShardRoutingRules instead", keyspace, workflow)
+	case ts.IsMultiTenantMigration():
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s.%s is a multi-tenant MoveTables workflow which uses keyspace routing rules", keyspace, workflow)
+	case ts.frozen:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "writes have already been switched for workflow %s.%s", keyspace, workflow)
+	}
+
+	rules, err := topotools.GetRoutingRules(ctx, s.ts)
+	if err != nil {
+		return nil, err
+	}
+	reset := maps.Clone(rules)
+	routeTablesToSource(reset, ts.SourceKeyspaceName(), ts.TargetKeyspaceName(), ts.Tables())
+
+	changes := diffRoutingRules(rules, reset)
+	if len(changes) == 0 || dryRun {
+		return changes, nil
+	}
+	if err := topotools.SaveRoutingRules(ctx, s.ts, reset); err != nil {
+		return nil, err
+	}
+	if err := s.ts.RebuildSrvVSchema(ctx, nil); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetEffectiveWorkflowTabletTypes returns the tablet types, and the
+// selection preference for them, that the workflow's streams use when
+// picking a source tablet to stream from. When no tablet types were
+// configured for the workflow, the streams fall back to the vttablet
+// default so that is what we return.
+func (s *Server) GetEffectiveWorkflowTabletTypes(ctx context.Context, keyspace, workflow string) ([]topodatapb.TabletType, tabletmanagerdatapb.TabletSelectionPreference, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetEffectiveWorkflowTabletTypes")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	wf, err := s.GetWorkflow(ctx, keyspace, workflow, false, nil)
+	if err != nil {
+		return nil, tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN, err
+	}
+	tabletTypes, tsp := effectiveTabletTypes(wf.GetTabletTypes(), wf.GetTabletSelectionPreference())
+	return tabletTypes, tsp, nil
+}
+
+// ListFrozenWorkflows returns the workflows in the keyspace that have at
+// least one stream that is frozen, which is the case after writes have been
+// switched. A frozen workflow cannot have its traffic switched again, so
+// this can be used to find workflows that were left frozen, e.g. by a
+// traffic switch that was never completed.
+func (s *Server) ListFrozenWorkflows(ctx context.Context, keyspace string) ([]*vtctldatapb.Workflow, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ListFrozenWorkflows")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+
+	resp, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+		Keyspace: keyspace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var frozen []*vtctldatapb.Workflow
+	for _, wf := range resp.GetWorkflows() {
+		if isWorkflowFrozen(wf) {
+			frozen = append(frozen, wf)
+		}
+	}
+	slices.SortFunc(frozen, func(a, b *vtctldatapb.Workflow) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return frozen, nil
+}
+
+// isWorkflowFrozen returns true if any of the workflow's streams is frozen.
+func isWorkflowFrozen(wf *vtctldatapb.Workflow) bool {
+	for _, shardStreams := range wf.GetShardStreams() {
+		for _, stream := range shardStreams.GetStreams() {
+			if stream.GetMessage() == Frozen {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (s *Server) GetWorkflow(ctx context.Context, keyspace, workflow string, includeLogs bool, shards []string) (*vtctldatapb.Workflow, error) {
@@ -393,6 +1162,9 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 	span.Annotate("active_only", req.ActiveOnly)
 	span.Annotate("include_logs", req.IncludeLogs)
 	span.Annotate("shards", req.Shards)
+	span.Annotate("stream_states", req.StreamStates)
+	span.Annotate("summary_only", req.SummaryOnly)
+	span.Annotate("include_reversible", req.IncludeReversible)
 
 	readReq := &tabletmanagerdatapb.ReadVReplicationWorkflowsRequest{}
 	if req.Workflow != "" {
@@ -429,7 +1201,7 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 			}
 			// Clone the request so that we can set the correct DB name for tablet.
 			req := readReq.CloneVT()
-			wres, err := s.tmc.ReadVReplicationWorkflows(readWorkflowsCtx, primary.Tablet, req)
+			wres, err := s.tmClient().ReadVReplicationWorkflows(readWorkflowsCtx, primary.Tablet, req)
 			if err != nil {
 				return err
 			}
@@ -517,6 +1289,23 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 		// things are running concurrently with this which also access these maps).
 		m.Lock()
 		defer m.Unlock()
+
+		// Record the source tablet selection configuration for the workflow
+		// as a whole.
+		for _, cell := range strings.Split(res.Cells, ",") {
+			cell = strings.TrimSpace(cell)
+			if cell != "" && !slices.Contains(workflow.Cells, cell) {
+				workflow.Cells = append(workflow.Cells, cell)
+			}
+		}
+		sort.Strings(workflow.Cells)
+		for _, tabletType := range res.TabletTypes {
+			if !slices.Contains(workflow.TabletTypes, tabletType) {
+				workflow.TabletTypes = append(workflow.TabletTypes, tabletType)
+			}
+		}
+		workflow.TabletSelectionPreference = res.TabletSelectionPreference
+
 		for _, rstream := range res.Streams {
 			// The value in the pos column can be compressed and thus not
 			// have a valid GTID consisting of valid UTF-8 characters so we
@@ -753,7 +1542,7 @@ ORDER BY
 			return
 		}
 
-		vx := vexec.NewVExec(req.Keyspace, workflow.Name, s.ts, s.tmc, s.SQLParser())
+		vx := vexec.NewVExec(req.Keyspace, workflow.Name, s.ts, s.tmClient(), s.SQLParser())
 		results, err := vx.QueryContext(ctx, query)
 		if err != nil {
 			// Note that we do not return here. If there are any query results
@@ -910,6 +1699,24 @@ ORDER BY
 
 		workflow.MaxVReplicationLag = int64(maxVReplicationLag)
 		workflow.MaxVReplicationTransactionLag = int64(maxVReplicationTransactionLag)
+		if req.IncludeReversible {
+			if workflow.Reversible, err = s.isWorkflowReversible(ctx, workflow); err != nil {
+				return nil, vterrors.Wrapf(err, "failed to check if the %s workflow is reversible", name)
+			}
+		}
+
+		// Now that the aggregate values have been computed using all of the
+		// streams, remove any that are not in the requested states.
+		if len(req.StreamStates) > 0 {
+			filterWorkflowStreamsByState(workflow, req.StreamStates)
+		}
+
+		if req.SummaryOnly {
+			workflow.Summary = summarizeWorkflow(workflow)
+			workflow.ShardStreams = nil
+			workflows = append(workflows, workflow)
+			continue
+		}
 
 		// Sort shard streams by stream_id ASC, to support an optimization
 		// in fetchStreamLogs below.
@@ -939,6 +1746,57 @@ ORDER BY
 	}, nil
 }
 
+// isWorkflowReversible returns true if writes have been switched for the
+// workflow, which leaves its streams frozen, and its reverse workflow exists
+// on every source shard without being frozen itself. The reverse workflow is
+// read from the source shards concurrently, and an error is returned if it
+// cannot be read from any of them.
+func (s *Server) isWorkflowReversible(ctx context.Context, workflow *vtctldatapb.Workflow) (bool, error) {
+	if !isWorkflowFrozen(workflow) {
+		return false, nil
+	}
+	reverseWorkflow := ReverseWorkflowName(workflow.GetName())
+	sourceKeyspace := workflow.GetSource().GetKeyspace()
+	var notReversible atomic.Bool
+	eg, ectx := errgroup.WithContext(ctx)
+	for _, shard := range workflow.GetSource().GetShards() {
+		eg.Go(func() error {
+			si, err := s.ts.GetShard(ectx, sourceKeyspace, shard)
+			if err != nil {
+				return vterrors.Wrapf(err, "failed to get shard %s/%s", sourceKeyspace, shard)
+			}
+			if si.PrimaryAlias == nil {
+				return fmt.Errorf("%w %s/%s", vexec.ErrNoShardPrimary, sourceKeyspace, shard)
+			}
+			primary, err := s.ts.GetTablet(ectx, si.PrimaryAlias)
+			if err != nil {
+				return vterrors.Wrapf(err, "failed to get the primary tablet for shard %s/%s", sourceKeyspace, shard)
+			}
+			res, err := s.tmClient().ReadVReplicationWorkflow(ectx, primary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+				Workflow: reverseWorkflow,
+			})
+			if err != nil {
+				return vterrors.Wrapf(err, "failed to read the reverse workflow %s on tablet %s", reverseWorkflow, primary.AliasString())
+			}
+			if res == nil || len(res.Streams) == 0 {
+				notReversible.Store(true)
+				return nil
+			}
+			for _, stream := range res.Streams {
+				if stream.Message == Frozen {
+					notReversible.Store(true)
+					return nil
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return false, err
+	}
+	return !notReversible.Load(), nil
+}
+
 func (s *Server) getWorkflowState(ctx context.Context, targetKeyspace, workflowName string) (*trafficSwitcher, *State, error) {
 	ts, err := s.buildTrafficSwitcher(ctx, targetKeyspace, workflowName)
 	if err != nil {
@@ -1066,6 +1924,44 @@ func (s *Server) getWorkflowState(ctx context.Context, targetKeyspace, workflowN
 	return ts, state, nil
 }
 
+// GetCopyStateRows returns the latest copy_state entry, with the last primary
+// key that was copied, for each of the tables that the given stream on the
+// given target shard is still copying.
+func (s *Server) GetCopyStateRows(ctx context.Context, keyspace, workflow, shard string, streamID int32) ([]*vtctldatapb.Workflow_Stream_CopyState, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetCopyStateRows")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("shard", shard)
+	span.Annotate("stream_id", streamID)
+
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if si.PrimaryAlias == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s does not have a primary", keyspace, shard)
+	}
+	primary, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.tmClient().ReadVReplicationWorkflow(ctx, primary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+		Workflow: workflow,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(res.GetStreams(), func(stream *tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream) bool {
+		return stream.Id == streamID
+	}) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "stream %d of workflow %s does not exist on shard %s/%s",
+			streamID, workflow, keyspace, shard)
+	}
+	return s.getWorkflowCopyStates(ctx, primary, []int32{streamID})
+}
+
 func (s *Server) getWorkflowCopyStates(ctx context.Context, tablet *topo.TabletInfo, streamIds []int32) ([]*vtctldatapb.Workflow_Stream_CopyState, error) {
 	span, ctx := trace.NewSpan(ctx, "workflow.Server.getWorkflowCopyStates")
 	defer span.Finish()
@@ -1084,7 +1980,7 @@ func (s *Server) getWorkflowCopyStates(ctx context.Context, tablet *topo.TabletI
 	if err != nil {
 		return nil, err
 	}
-	qr, err := s.tmc.VReplicationExec(ctx, tablet.Tablet, query)
+	qr, err := s.tmClient().VReplicationExec(ctx, tablet.Tablet, query)
 	if err != nil {
 		return nil, err
 	}
@@ -1196,7 +2092,7 @@ func (s *Server) LookupVindexExternalize(ctx context.Context, req *vtctldatapb.L
 		if err != nil {
 			return err
 		}
-		res, err := s.tmc.ReadVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+		res, err := s.tmClient().ReadVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
 			Workflow: req.Name,
 		})
 		if err != nil {
@@ -1260,7 +2156,7 @@ func (s *Server) Materialize(ctx context.Context, ms *vtctldatapb.MaterializeSet
 		ctx:      ctx,
 		ts:       s.ts,
 		sourceTs: s.ts,
-		tmc:      s.tmc,
+		tmc:      s.tmClient(),
 		ms:       ms,
 		env:      s.env,
 	}
@@ -1275,6 +2171,9 @@ func (s *Server) Materialize(ctx context.Context, ms *vtctldatapb.MaterializeSet
 		cells[i] = strings.TrimSpace(cells[i])
 	}
 
+	if err := validateNewWorkflow(ctx, s.ts, s.tmClient(), ms.TargetKeyspace, ms.Workflow); err != nil {
+		return err
+	}
 	err = mz.createWorkflowStreams(&tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
 		Workflow:                  ms.Workflow,
 		Cells:                     strings.Split(ms.Cell, ","),
@@ -1298,6 +2197,187 @@ func (s *Server) MoveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	return s.moveTablesCreate(ctx, req, binlogdatapb.VReplicationWorkflowType_MoveTables)
 }
 
+// CancelWorkflowCreate removes the artifacts left behind by a MoveTablesCreate
+// that was interrupted or failed before it completed. It performs the same
+// cleanup as the deferred handler in moveTablesCreate: dropping the target
+// denied tables entries, the vreplication streams, and the routing rules.
+// The original target vschema cannot be restored as it is only known to the
+// create call itself.
+//
+// It must only be called once the create call has returned or its vtctld has
+// gone away: the create writes the streams before it takes the target
+// keyspace lock, so a concurrent cancel can clean up before the create puts
+// the routing rules and denied tables in place. It is idempotent: if the
+// workflow's streams no longer exist then there is nothing to clean up and it
+// returns nil.
+func (s *Server) CancelWorkflowCreate(ctx context.Context, keyspace, workflow string) (err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.CancelWorkflowCreate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, state, err := s.getWorkflowState(ctx, keyspace, workflow)
+	if err != nil {
+		if errors.Is(err, ErrNoStreams) {
+			log.Infof("No streams found for workflow %s.%s, nothing to cancel", keyspace, workflow)
+			return nil
+		}
+		return err
+	}
+	if ts.MigrationType() != binlogdatapb.MigrationType_TABLES {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "workflow %s.%s is not a MoveTables workflow", keyspace, workflow)
+	}
+	if state.WritesSwitched || len(state.ReplicaCellsSwitched) > 0 || len(state.RdonlyCellsSwitched) > 0 {
+		return ErrWorkflowPartiallySwitched
+	}
+
+	sw := &switcher{s: s, ts: ts}
+	lockCtx, targetUnlock, lockErr := sw.lockKeyspace(ctx, ts.TargetKeyspaceName(), "CancelWorkflowCreate")
+	if lockErr != nil {
+		ts.Logger().Errorf("Locking target keyspace %s failed: %v", ts.TargetKeyspaceName(), lockErr)
+		return lockErr
+	}
+	defer targetUnlock(&err)
+	ctx = lockCtx
+
+	if !ts.IsMultiTenantMigration() && !ts.IsPartialMigration() { // Non-standard ones do not use shard scoped mechanisms
+		if err := ts.dropTargetDeniedTables(ctx); err != nil {
+			return vterrors.Wrapf(err, "failed to cleanup denied table entries")
+		}
+	}
+	if err := s.dropArtifacts(ctx, false, sw); err != nil {
+		return vterrors.Wrapf(err, "failed to cleanup workflow artifacts")
+	}
+	return nil
+}
+
+// ClearTargetDeniedTables removes the denied table entries that were added
+// for the workflow's tables on the target shards and refreshes the target
+// tablets. The workflow's streams and data are left untouched. This can be
+// used to unblock queries when a failed MoveTablesCreate left the target
+// denied tables behind.
+func (s *Server) ClearTargetDeniedTables(ctx context.Context, keyspace, workflow string) (err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ClearTargetDeniedTables")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return err
+	}
+	if ts.MigrationType() != binlogdatapb.MigrationType_TABLES {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "workflow %s.%s is not a MoveTables workflow", keyspace, workflow)
+	}
+	if ts.IsMultiTenantMigration() || ts.IsPartialMigration() {
+		// Non-standard ones do not use shard scoped mechanisms.
+		ts.Logger().Infof("Workflow %s.%s does not use target denied tables, nothing to clear", keyspace, workflow)
+		return nil
+	}
+
+	sw := &switcher{s: s, ts: ts}
+	lockCtx, targetUnlock, lockErr := sw.lockKeyspace(ctx, ts.TargetKeyspaceName(), "ClearTargetDeniedTables")
+	if lockErr != nil {
+		ts.Logger().Errorf("Locking target keyspace %s failed: %v", ts.TargetKeyspaceName(), lockErr)
+		return lockErr
+	}
+	defer targetUnlock(&err)
+	ctx = lockCtx
+
+	if err := ts.dropTargetDeniedTables(ctx); err != nil {
+		return vterrors.Wrapf(err, "failed to cleanup denied table entries")
+	}
+	return nil
+}
+
+// GetSourceDeniedTables returns the tables that are currently denied on each
+// of the workflow's source shards, keyed by shard name and then by tablet
+// type. The shard records are read from the topo, so every denied table is
+// returned, including ones that are not part of the workflow. Shards and
+// tablet types without any denied tables are omitted.
+func (s *Server) GetSourceDeniedTables(ctx context.Context, keyspace, workflow string) (map[string]map[topodatapb.TabletType][]string, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetSourceDeniedTables")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		m            sync.Mutex
+		deniedTables = make(map[string]map[topodatapb.TabletType][]string, len(ts.Sources()))
+	)
+	err = ts.ForAllSources(func(source *MigrationSource) error {
+		si, err := s.ts.GetShard(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName())
+		if err != nil {
+			return err
+		}
+		shardDenied := make(map[topodatapb.TabletType][]string)
+		for _, tc := range si.TabletControls {
+			if len(tc.DeniedTables) == 0 {
+				continue
+			}
+			tables := slices.Clone(tc.DeniedTables)
+			sort.Strings(tables)
+			shardDenied[tc.TabletType] = tables
+		}
+		if len(shardDenied) == 0 {
+			return nil
+		}
+		m.Lock()
+		defer m.Unlock()
+		deniedTables[si.ShardName()] = shardDenied
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deniedTables, nil
+}
+
+// ValidateWorkflowVSchema checks that the target keyspace's vschema is
+// consistent with the tables in the given workflow. Every table must have an
+// entry in the vschema and, when the target keyspace is unsharded, the entry
+// must not have any column vindexes, e.g. left over from a sharded source
+// keyspace. It returns a description of each discrepancy that was found.
+func (s *Server) ValidateWorkflowVSchema(ctx context.Context, keyspace, workflow string) ([]string, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ValidateWorkflowVSchema")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	vschema, err := s.ts.GetVSchema(ctx, ts.TargetKeyspaceName())
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the vschema for target keyspace %s", ts.TargetKeyspaceName())
+	}
+	tables := slices.Clone(ts.Tables())
+	sort.Strings(tables)
+	var discrepancies []string
+	for _, table := range tables {
+		vsTable, ok := vschema.Tables[table]
+		if !ok {
+			discrepancies = append(discrepancies, fmt.Sprintf("table %s has no entry in the vschema for keyspace %s",
+				table, ts.TargetKeyspaceName()))
+			continue
+		}
+		if !vschema.Sharded && len(vsTable.ColumnVindexes) > 0 {
+			discrepancies = append(discrepancies, fmt.Sprintf("table %s has column vindexes in the vschema for unsharded keyspace %s",
+				table, ts.TargetKeyspaceName()))
+		}
+	}
+	return discrepancies, nil
+}
+
 func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest,
 	workflowType binlogdatapb.VReplicationWorkflowType,
 ) (res *vtctldatapb.WorkflowStatusResponse, err error) {
@@ -1320,6 +2400,17 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		sourceTopo   = s.ts
 	)
 
+	if req.GetPinSourcePositions() && req.ExternalClusterName != "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot pin the source positions when the source is an external cluster")
+	}
+	if req.GetWorkflowOptions().GetCopyParallelInsertWorkers() < 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "copy_parallel_insert_workers cannot be negative: %d",
+			req.GetWorkflowOptions().GetCopyParallelInsertWorkers())
+	}
+	if req.SkipTablesWithoutPrimaryKey && req.FailOnTablesWithoutPrimaryKey {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot both skip and fail on tables without a primary key")
+	}
+
 	// When the source is an external cluster mounted using the Mount command.
 	if req.ExternalClusterName != "" {
 		externalTopo, err = s.ts.OpenExternalVitessClusterServer(ctx, req.ExternalClusterName)
@@ -1330,6 +2421,14 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		log.Infof("Successfully opened external topo: %+v", externalTopo)
 	}
 
+	// Fail early, before making any changes, if the workflow already exists.
+	if err := validateNewWorkflow(ctx, s.ts, s.tmClient(), targetKeyspace, req.Workflow); err != nil {
+		return nil, err
+	}
+	if err := s.validateRoutingRuleFlags(req); err != nil {
+		return nil, err
+	}
+
 	var vschema *vschemapb.Keyspace
 	var origVSchema *vschemapb.Keyspace // If we need to rollback a failed create
 	vschema, err = s.ts.GetVSchema(ctx, targetKeyspace)
@@ -1350,9 +2449,12 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		if err := validateTenantId(multiTenantSpec.TenantIdColumnType, req.WorkflowOptions.TenantId); err != nil {
 			return nil, err
 		}
+		if err := s.validateTenantIdNotInUse(ctx, targetKeyspace, req.WorkflowOptions.TenantId); err != nil {
+			return nil, err
+		}
 	}
 
-	ksTables, err := getTablesInKeyspace(ctx, sourceTopo, s.tmc, sourceKeyspace)
+	ksTables, err := getTablesInKeyspace(ctx, sourceTopo, s.tmClient(), sourceKeyspace)
 	if err != nil {
 		return nil, err
 	}
@@ -1380,7 +2482,32 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 			tables2 = append(tables2, t)
 		}
 	}
+	if len(tables) > 0 && len(tables2) == 0 {
+		// The exclusions removed every candidate table, which is most likely
+		// a mistake, so be explicit about it rather than reporting that there
+		// are simply no tables to move.
+		excluded := slices.Clone(req.ExcludeTables)
+		slices.Sort(excluded)
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "all %d candidate tables were excluded from the workflow, excluded tables: %s",
+			len(tables), strings.Join(excluded, ","))
+	}
 	tables = tables2
+	if req.SkipTablesWithoutPrimaryKey || req.FailOnTablesWithoutPrimaryKey {
+		noPKTables, err := getTablesWithoutPrimaryKey(ctx, sourceTopo, s.tmClient(), sourceKeyspace, tables)
+		if err != nil {
+			return nil, err
+		}
+		if len(noPKTables) > 0 {
+			if req.FailOnTablesWithoutPrimaryKey {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tables without a primary key found in the %s keyspace: %s",
+					sourceKeyspace, strings.Join(noPKTables, ","))
+			}
+			log.Infof("Skipping tables without a primary key: %s", strings.Join(noPKTables, ","))
+			tables = slices.DeleteFunc(tables, func(t string) bool {
+				return slices.Contains(noPKTables, t)
+			})
+		}
+	}
 	if len(tables) == 0 {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to move")
 	}
@@ -1418,21 +2545,36 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	if req.DropForeignKeys {
 		createDDLMode = createDDLAsCopyDropForeignKeys
 	}
+	for table, createDDL := range req.GetTableCreateDdl() {
+		if !slices.Contains(tables, table) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "create ddl override specified for table %s which is not part of the workflow", table)
+		}
+		switch createDDL {
+		case createDDLAsCopy, createDDLAsCopyDropConstraint, createDDLAsCopyDropForeignKeys:
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid create ddl override %q for table %s, valid values are: %s, %s, %s",
+				createDDL, table, createDDLAsCopy, createDDLAsCopyDropConstraint, createDDLAsCopyDropForeignKeys)
+		}
+	}
 
 	for _, table := range tables {
 		buf := sqlparser.NewTrackedBuffer(nil)
 		buf.Myprintf("select * from %v", sqlparser.NewIdentifierCS(table))
+		tableCreateDDL := createDDLMode
+		if createDDL, ok := req.GetTableCreateDdl()[table]; ok {
+			tableCreateDDL = createDDL
+		}
 		ms.TableSettings = append(ms.TableSettings, &vtctldatapb.TableMaterializeSettings{
 			TargetTable:      table,
 			SourceExpression: buf.String(),
-			CreateDdl:        createDDLMode,
+			CreateDdl:        tableCreateDDL,
 		})
 	}
 	mz := &materializer{
 		ctx:          ctx,
 		ts:           s.ts,
 		sourceTs:     sourceTopo,
-		tmc:          s.tmc,
+		tmc:          s.tmClient(),
 		ms:           ms,
 		workflowType: workflowType,
 		env:          s.env,
@@ -1531,7 +2673,13 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		if err != nil {
 			return nil, err
 		}
-		if exists {
+		switch {
+		case exists && req.GetForceIgnorePreviousJournal():
+			// This only skips the check here, the journal is still used as
+			// normal when switching traffic.
+			log.Warningf("Ignoring the previous journal entry found for migration id %d in _vt.resharding_journal on tablets %s as requested",
+				migrationID, strings.Join(tablets, ","))
+		case exists:
 			log.Errorf("Found a previous journal entry for %d", migrationID)
 			msg := fmt.Sprintf("found an entry from a previous run for migration id %d in _vt.resharding_journal on tablets %s, ",
 				migrationID, strings.Join(tablets, ","))
@@ -1541,7 +2689,36 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		}
 	}
 
-	if req.AutoStart {
+	if req.GetPinSourcePositions() {
+		if err := ts.pinStreamPositions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var warnings []string
+	switch {
+	case req.AutoStartAfterSchemaCheck:
+		diffs, err := s.ValidateWorkflowSchemas(ctx, targetKeyspace, req.Workflow)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffs) == 0 {
+			if err := mz.startStreams(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+		diffTables := maps.Keys(diffs)
+		slices.Sort(diffTables)
+		for _, table := range diffTables {
+			warnings = append(warnings, fmt.Sprintf("the schema for table %s differs between the source and target: %s",
+				table, strings.Join(diffs[table], "; ")))
+		}
+		warnings = append(warnings, fmt.Sprintf("the workflow was not started, fix the schema differences and then start it using: MoveTables --workflow %s --target-keyspace %s start",
+			req.Workflow, req.TargetKeyspace))
+		log.Warningf("Not starting the %s.%s workflow as the schema differs between the source and target for tables: %s",
+			targetKeyspace, req.Workflow, strings.Join(diffTables, ","))
+	case req.AutoStart:
 		if err := mz.startStreams(ctx); err != nil {
 			return nil, err
 		}
@@ -1550,19 +2727,28 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	for _, shard := range mz.targetShards {
 		targetShards = append(targetShards, shard.ShardName())
 	}
-	return s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+	resp, err := s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
 		Keyspace: targetKeyspace,
 		Workflow: req.Workflow,
 		Shards:   targetShards,
 	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Warnings = warnings
+	return resp, nil
 }
 
-func (s *Server) validateRoutingRuleFlags(req *vtctldatapb.MoveTablesCreateRequest, mz *materializer) error {
-	if mz.IsMultiTenantMigration() {
+// validateRoutingRuleFlags rejects combinations of create flags that cannot
+// be used together given how the workflow's routing rules are managed. It
+// only looks at the request so that it can be run for every MoveTables
+// based workflow, including Migrate, before anything has been created.
+func (s *Server) validateRoutingRuleFlags(req *vtctldatapb.MoveTablesCreateRequest) error {
+	if req.GetWorkflowOptions().GetTenantId() != "" {
 		switch {
 		case req.NoRoutingRules:
 			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot use --no-routing-rules in a multi-tenant migration")
-		case mz.isPartial:
+		case len(req.SourceShards) > 0:
 			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot run partial shard migration along with multi-tenant migration")
 		}
 	}
@@ -1579,7 +2765,7 @@ func (s *Server) setupInitialDeniedTables(ctx context.Context, ts *trafficSwitch
 		}); err != nil {
 			return err
 		}
-		strCtx, cancel := context.WithTimeout(ctx, shardTabletRefreshTimeout)
+		strCtx, cancel := context.WithTimeout(ctx, s.refreshTimeout())
 		defer cancel()
 		_, _, err := topotools.RefreshTabletsByShard(strCtx, ts.TopoServer(), ts.TabletManagerClient(), target.GetShard(), nil, ts.Logger())
 		return err
@@ -1587,10 +2773,6 @@ func (s *Server) setupInitialDeniedTables(ctx context.Context, ts *trafficSwitch
 }
 
 func (s *Server) setupInitialRoutingRules(ctx context.Context, req *vtctldatapb.MoveTablesCreateRequest, mz *materializer, tables []string) error {
-	if err := s.validateRoutingRuleFlags(req, mz); err != nil {
-		return err
-	}
-
 	sourceKeyspace := req.SourceKeyspace
 	targetKeyspace := req.TargetKeyspace
 
@@ -1627,21 +2809,7 @@ func (s *Server) setupInitialRoutingRules(ctx context.Context, req *vtctldatapb.
 	if err != nil {
 		return err
 	}
-	routeTableToSource := func(keyspace, table string) {
-		key := table
-		route := fmt.Sprintf("%s.%s", sourceKeyspace, table)
-		if keyspace != "" {
-			key = fmt.Sprintf("%s.%s", keyspace, table)
-		}
-		for _, typ := range tabletTypeSuffixes {
-			rules[key+typ] = []string{route}
-		}
-	}
-	for _, table := range tables {
-		for _, ks := range []string{globalTableQualifier, targetKeyspace, sourceKeyspace} {
-			routeTableToSource(ks, table)
-		}
-	}
+	routeTablesToSource(rules, sourceKeyspace, targetKeyspace, tables)
 	if err := topotools.SaveRoutingRules(ctx, s.ts, rules); err != nil {
 		return err
 	}
@@ -1650,15 +2818,28 @@ func (s *Server) setupInitialRoutingRules(ctx context.Context, req *vtctldatapb.
 
 // MoveTablesComplete is part of the vtctlservicepb.VtctldServer interface.
 // It cleans up a successful MoveTables workflow and its related artifacts.
+// If it fails part way through removing the source tables then it can be
+// run again to finish the cleanup, as tables that were already removed are
+// skipped.
 // Note: this is currently re-used for Reshard as well.
-func (s *Server) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTablesCompleteRequest) (*vtctldatapb.MoveTablesCompleteResponse, error) {
+func (s *Server) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTablesCompleteRequest, opts ...WorkflowActionOption) (*vtctldatapb.MoveTablesCompleteResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "workflow.Server.MoveTablesComplete")
 	defer span.Finish()
 
+	if req.GetKeepSourceVschema() && req.KeepRoutingRules {
+		// The routing rules redirect queries against the source keyspace's tables
+		// to the target keyspace, so keeping them would make the retained source
+		// vschema definitions unreachable.
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot keep the routing rules when keeping the source keyspace vschema for the %s workflow",
+			req.Workflow)
+	}
+
+	options := s.processWorkflowActionOptions(opts)
 	ts, state, err := s.getWorkflowState(ctx, req.GetTargetKeyspace(), req.GetWorkflow())
 	if err != nil {
 		return nil, err
 	}
+	ts.logger = options.logger
 
 	var summary string
 	if req.DryRun {
@@ -1666,7 +2847,7 @@ func (s *Server) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTa
 	} else {
 		summary = fmt.Sprintf("Successfully completed the %s workflow in the %s keyspace", req.Workflow, req.TargetKeyspace)
 	}
-	var dryRunResults *[]string
+	var dryRunResults *LogRecorder
 
 	if state.WorkflowType == TypeMigrate {
 		dryRunResults, err = s.finalizeMigrateWorkflow(ctx, ts, strings.Join(ts.tables, ","), false, req.KeepData, req.KeepRoutingRules, req.DryRun)
@@ -1675,10 +2856,9 @@ func (s *Server) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTa
 				req.Workflow, req.TargetKeyspace)
 		}
 		resp := &vtctldatapb.MoveTablesCompleteResponse{
-			Summary: summary,
-		}
-		if dryRunResults != nil {
-			resp.DryRunResults = *dryRunResults
+			Summary:       summary,
+			DryRunResults: dryRunResults.GetLogs(),
+			DryRunSteps:   dryRunResults.GetSteps(),
 		}
 		return resp, nil
 	}
@@ -1692,20 +2872,56 @@ func (s *Server) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTa
 	} else {
 		renameTable = DropTable
 	}
-	if dryRunResults, err = s.dropSources(ctx, ts, renameTable, req.KeepData, req.KeepRoutingRules, false, req.DryRun); err != nil {
+	if dryRunResults, err = s.dropSources(ctx, ts, renameTable, req.KeepData, req.KeepRoutingRules, req.GetKeepSourceVschema(), false, req.DryRun,
+		req.PreDropSourceSql, req.IgnorePreDropErrors); err != nil {
 		return nil, err
 	}
 
 	resp := &vtctldatapb.MoveTablesCompleteResponse{
-		Summary: summary,
-	}
-	if dryRunResults != nil {
-		resp.DryRunResults = *dryRunResults
+		Summary:       summary,
+		DryRunResults: dryRunResults.GetLogs(),
+		DryRunSteps:   dryRunResults.GetSteps(),
 	}
 
 	return resp, nil
 }
 
+// ValidateReshardKeyranges confirms that the given target shards form a
+// contiguous, non-overlapping cover of the key range of the given source
+// shards in the keyspace, as required to Reshard from the sources to the
+// targets. If they do not, the error describes each gap and overlap. This
+// can be used to catch mistakes in the shard boundaries before calling
+// ReshardCreate.
+func (s *Server) ValidateReshardKeyranges(ctx context.Context, keyspace string, sourceShards, targetShards []string) error {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ValidateReshardKeyranges")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("source_shards", sourceShards)
+	span.Annotate("target_shards", targetShards)
+
+	getShards := func(shards []string) ([]*topo.ShardInfo, error) {
+		sis := make([]*topo.ShardInfo, 0, len(shards))
+		for _, shard := range shards {
+			si, err := s.ts.GetShard(ctx, keyspace, shard)
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "GetShard(%s) failed", shard)
+			}
+			sis = append(sis, si)
+		}
+		return sis, nil
+	}
+	sources, err := getShards(sourceShards)
+	if err != nil {
+		return err
+	}
+	targets, err := getShards(targetShards)
+	if err != nil {
+		return err
+	}
+	return validateKeyRangeCover(sources, targets)
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCreateRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
 	span, ctx := trace.NewSpan(ctx, "workflow.Server.ReshardCreate")
@@ -1721,7 +2937,10 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 
 	keyspace := req.Keyspace
 	cells := req.Cells
-	// TODO: validate workflow does not exist.
+
+	if err := validateNewWorkflow(ctx, s.ts, s.tmClient(), keyspace, req.Workflow); err != nil {
+		return nil, err
+	}
 
 	if err := s.ts.ValidateSrvKeyspace(ctx, keyspace, strings.Join(cells, ",")); err != nil {
 		err2 := vterrors.Wrapf(err, "SrvKeyspace for keyspace %s is corrupt for cell(s) %s", keyspace, cells)
@@ -1736,10 +2955,15 @@ func (s *Server) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCrea
 	rs.onDDL = req.OnDdl
 	rs.stopAfterCopy = req.StopAfterCopy
 	rs.deferSecondaryKeys = req.DeferSecondaryKeys
+	rs.allowSchemaDiff = req.AllowSchemaDiff
 	if !req.SkipSchemaCopy {
 		if err := rs.copySchema(ctx); err != nil {
 			return nil, vterrors.Wrap(err, "copySchema")
 		}
+	} else if req.ValidateSchemaOnSkip {
+		if err := rs.validateSchema(ctx); err != nil {
+			return nil, vterrors.Wrap(err, "validateSchema")
+		}
 	}
 	if err := rs.createStreams(ctx); err != nil {
 		return nil, vterrors.Wrap(err, "createStreams")
@@ -1843,7 +3067,7 @@ func (s *Server) VDiffCreate(ctx context.Context, req *vtctldatapb.VDiffCreateRe
 	}
 
 	err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		_, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
+		_, err := s.tmClient().VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
 		return err
 	})
 	if err != nil {
@@ -1878,7 +3102,7 @@ func (s *Server) VDiffDelete(ctx context.Context, req *vtctldatapb.VDiffDeleteRe
 	}
 
 	err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		_, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
+		_, err := s.tmClient().VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
 		return err
 	})
 	if err != nil {
@@ -1911,7 +3135,7 @@ func (s *Server) VDiffResume(ctx context.Context, req *vtctldatapb.VDiffResumeRe
 	}
 
 	err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		_, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
+		_, err := s.tmClient().VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
 		return err
 	})
 	if err != nil {
@@ -1948,7 +3172,7 @@ func (s *Server) VDiffShow(ctx context.Context, req *vtctldatapb.VDiffShowReques
 		err:       nil,
 	}
 	output.err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		resp, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
+		resp, err := s.tmClient().VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
 		output.mu.Lock()
 		defer output.mu.Unlock()
 		output.responses[target.GetShard().ShardName()] = resp
@@ -1985,7 +3209,7 @@ func (s *Server) VDiffStop(ctx context.Context, req *vtctldatapb.VDiffStopReques
 	}
 
 	err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		_, err := s.tmc.VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
+		_, err := s.tmClient().VDiff(ctx, target.GetPrimary().Tablet, tabletreq)
 		return err
 	})
 	if err != nil {
@@ -2030,15 +3254,17 @@ func (s *Server) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDe
 	deleteReq := &tabletmanagerdatapb.DeleteVReplicationWorkflowRequest{
 		Workflow: req.Workflow,
 	}
-	vx := vexec.NewVExec(req.Keyspace, req.Workflow, s.ts, s.tmc, s.env.Parser())
+	vx := vexec.NewVExec(req.Keyspace, req.Workflow, s.ts, s.tmClient(), s.env.Parser())
 	vx.SetShardSubset(req.Shards)
 	callback := func(ctx context.Context, tablet *topo.TabletInfo) (*querypb.QueryResult, error) {
-		res, err := s.tmc.DeleteVReplicationWorkflow(ctx, tablet.Tablet, deleteReq)
+		res, err := s.tmClient().DeleteVReplicationWorkflow(ctx, tablet.Tablet, deleteReq)
 		if err != nil {
 			return nil, err
 		}
 		// Best effort cleanup and optimization of related data.
-		s.deleteWorkflowVDiffData(ctx, tablet.Tablet, req.Workflow)
+		if !req.GetKeepVdiffData() {
+			s.deleteWorkflowVDiffData(ctx, tablet.Tablet, req.Workflow, req.GetKeepVdiffTables())
+		}
 		s.optimizeCopyStateTable(tablet.Tablet)
 		return res.Result, err
 	}
@@ -2080,12 +3306,43 @@ func (s *Server) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDe
 	return response, nil
 }
 
+// GetReverseWorkflowStatus returns the status of the reverse workflow for the
+// given workflow in the target keyspace. The reverse workflow lives in the
+// forward workflow's source keyspace and is named using ReverseWorkflowName,
+// so callers do not need to know either in order to monitor it, e.g. after
+// writes have been switched.
+func (s *Server) GetReverseWorkflowStatus(ctx context.Context, targetKeyspace, workflow string) (*vtctldatapb.WorkflowStatusResponse, error) {
+	wf, err := s.GetWorkflow(ctx, targetKeyspace, workflow, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	sourceKeyspace := wf.GetSource().GetKeyspace()
+	if sourceKeyspace == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "could not determine the source keyspace for the %s workflow in the %s keyspace",
+			workflow, targetKeyspace)
+	}
+	reverseWorkflow := ReverseWorkflowName(workflow)
+	resp, err := s.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+		Keyspace: sourceKeyspace,
+		Workflow: reverseWorkflow,
+	})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the status of the %s reverse workflow in the %s keyspace", reverseWorkflow, sourceKeyspace)
+	}
+	return resp, nil
+}
+
 func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowStatusRequest) (*vtctldatapb.WorkflowStatusResponse, error) {
+	if len(req.Shards) > 0 {
+		if err := validateKeyspaceShards(ctx, s.ts, req.Keyspace, req.Shards); err != nil {
+			return nil, err
+		}
+	}
 	ts, state, err := s.getWorkflowState(ctx, req.Keyspace, req.Workflow)
 	if err != nil {
 		return nil, err
 	}
-	copyProgress, err := s.GetCopyProgress(ctx, ts, state)
+	copyProgress, err := s.GetCopyProgress(ctx, ts, state, req.GetExactRowCounts())
 	if err != nil {
 		return nil, err
 	}
@@ -2102,21 +3359,18 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 		sort.Strings(tables)
 		var progress tableCopyProgress
 		for _, table := range tables {
-			var rowCountPct, tableSizePct float32
 			resp.TableCopyState[table] = &vtctldatapb.WorkflowStatusResponse_TableCopyState{}
 			progress = *(*copyProgress)[table]
-			if progress.SourceRowCount > 0 {
-				rowCountPct = float32(100.0 * float64(progress.TargetRowCount) / float64(progress.SourceRowCount))
-			}
-			if progress.SourceTableSize > 0 {
-				tableSizePct = float32(100.0 * float64(progress.TargetTableSize) / float64(progress.SourceTableSize))
-			}
+			rowsRemaining, rowCountPct := copyRemaining(progress.TargetRowCount, progress.SourceRowCount)
+			bytesRemaining, tableSizePct := copyRemaining(progress.TargetTableSize, progress.SourceTableSize)
 			resp.TableCopyState[table].RowsCopied = progress.TargetRowCount
 			resp.TableCopyState[table].RowsTotal = progress.SourceRowCount
 			resp.TableCopyState[table].RowsPercentage = rowCountPct
+			resp.TableCopyState[table].RowsRemaining = rowsRemaining
 			resp.TableCopyState[table].BytesCopied = progress.TargetTableSize
 			resp.TableCopyState[table].BytesTotal = progress.SourceTableSize
 			resp.TableCopyState[table].BytesPercentage = tableSizePct
+			resp.TableCopyState[table].BytesRemaining = bytesRemaining
 		}
 	}
 
@@ -2133,6 +3387,7 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 	}
 	sort.Strings(streamKeys)
 	resp.ShardStreams = make(map[string]*vtctldatapb.WorkflowStatusResponse_ShardStreams, len(streamKeys))
+	onDDLs := sets.New[string]()
 	for _, streamKey := range streamKeys {
 		streams := workflow.ShardStreams[streamKey].GetStreams()
 		keyParts := strings.Split(streamKey, "/")
@@ -2173,28 +3428,51 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			ts.Position = st.Position
 			ts.Status = st.State
 			ts.Info = strings.Join(info, "; ")
+			ts.Throttled, ts.ThrottledReason = getStreamThrottledState(st)
 			resp.ShardStreams[ksShard].Streams[i] = ts
+			onDDLs.Insert(st.BinlogSource.GetOnDdl().String())
 		}
 	}
+	resp.OnDdl = strings.Join(sets.List(onDDLs), ",")
 
 	return resp, nil
 }
 
 // GetCopyProgress returns the progress of all tables being copied in the
-// workflow.
-func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state *State) (*copyProgress, error) {
+// workflow. By default the row counts are the estimates from
+// information_schema.tables; if exactRowCounts is set they are instead
+// counted on each table, which is accurate but can be slow for large tables.
+func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state *State, exactRowCounts bool) (*copyProgress, error) {
 	getTablesQuery := "select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = %d"
 	getRowCountQuery := "select table_name, table_rows, data_length from information_schema.tables where table_schema = %s and table_name in (%s)"
 	tables := make(map[string]bool)
-	const MaxRows = 1000
+	// getCopyStateTables returns the tables still being copied by a stream. The
+	// number of tables is not known up front -- a Reshard workflow copies every
+	// table in the keyspace -- so if the result is larger than the row limit we
+	// retry with a larger one rather than failing or silently truncating it.
+	getCopyStateTables := func(tablet *topodatapb.Tablet, id int32) (*querypb.QueryResult, error) {
+		maxRows := uint64(max(copyStateTablesMaxRows, len(ts.Tables())))
+		for {
+			p3qr, err := s.tmClient().ExecuteFetchAsDba(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:   []byte(fmt.Sprintf(getTablesQuery, id)),
+				MaxRows: maxRows,
+			})
+			if err == nil || !isRowCountExceededError(err) {
+				return p3qr, err
+			}
+			if maxRows >= copyStateTablesMaxRowsLimit {
+				return nil, vterrors.Wrapf(err, "stream %d on tablet %s is copying more than %d tables",
+					id, topoproto.TabletAliasString(tablet.Alias), maxRows)
+			}
+			log.Infof("Stream %d on tablet %s is copying more than %d tables, retrying with a larger limit",
+				id, topoproto.TabletAliasString(tablet.Alias), maxRows)
+			maxRows = min(maxRows*2, copyStateTablesMaxRowsLimit)
+		}
+	}
 	sourcePrimaries := make(map[*topodatapb.TabletAlias]bool)
 	for _, target := range ts.targets {
 		for id, bls := range target.Sources {
-			query := fmt.Sprintf(getTablesQuery, id)
-			p3qr, err := s.tmc.ExecuteFetchAsDba(ctx, target.GetPrimary().Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
-				Query:   []byte(query),
-				MaxRows: MaxRows,
-			})
+			p3qr, err := getCopyStateTables(target.GetPrimary().Tablet, id)
 			if err != nil {
 				return nil, err
 			}
@@ -2238,7 +3516,7 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 	}
 
 	getTableMetrics := func(tablet *topodatapb.Tablet, query string, rowCounts *map[string]int64, tableSizes *map[string]int64) error {
-		p3qr, err := s.tmc.ExecuteFetchAsDba(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		p3qr, err := s.tmClient().ExecuteFetchAsDba(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
 			Query:   []byte(query),
 			MaxRows: uint64(len(tables)),
 		})
@@ -2261,6 +3539,32 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 		}
 		return nil
 	}
+	// getExactRowCounts replaces the estimated row counts, which can be stale
+	// or inaccurate for InnoDB tables, with exact ones from a full count of
+	// each table.
+	getExactRowCounts := func(tablet *topodatapb.Tablet, dbName string, rowCounts *map[string]int64) error {
+		for table := range tables {
+			query := fmt.Sprintf("select count(*) from %s.%s", sqlescape.EscapeID(dbName), sqlescape.EscapeID(table))
+			p3qr, err := s.tmClient().ExecuteFetchAsDba(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:   []byte(query),
+				MaxRows: 1,
+			})
+			if err != nil {
+				return err
+			}
+			qr := sqltypes.Proto3ToResult(p3qr)
+			if len(qr.Rows) != 1 {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected number of rows returned when counting the rows in table %s on tablet %s: %d",
+					table, topoproto.TabletAliasString(tablet.Alias), len(qr.Rows))
+			}
+			rowCount, err := qr.Rows[0][0].ToCastInt64()
+			if err != nil {
+				return err
+			}
+			(*rowCounts)[table] += rowCount
+		}
+		return nil
+	}
 	sourceDbName := ""
 	for _, tsSource := range ts.sources {
 		sourceDbName = tsSource.GetPrimary().DbName()
@@ -2280,14 +3584,21 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 	sort.Strings(tableList) // sort list for repeatability for mocking in tests
 	tablesStr := strings.Join(tableList, ",")
 	query := fmt.Sprintf(getRowCountQuery, encodeString(targetDbName), tablesStr)
+	exactTargetRowCounts := make(map[string]int64, len(tables))
 	for _, target := range ts.targets {
 		tablet := target.GetPrimary().Tablet
 		if err := getTableMetrics(tablet, query, &targetRowCounts, &targetTableSizes); err != nil {
 			return nil, err
 		}
+		if exactRowCounts {
+			if err := getExactRowCounts(tablet, targetDbName, &exactTargetRowCounts); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	query = fmt.Sprintf(getRowCountQuery, encodeString(sourceDbName), tablesStr)
+	exactSourceRowCounts := make(map[string]int64, len(tables))
 	for source := range sourcePrimaries {
 		ti, err := s.ts.GetTablet(ctx, source)
 		tablet := ti.Tablet
@@ -2297,6 +3608,14 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 		if err := getTableMetrics(tablet, query, &sourceRowCounts, &sourceTableSizes); err != nil {
 			return nil, err
 		}
+		if exactRowCounts {
+			if err := getExactRowCounts(tablet, sourceDbName, &exactSourceRowCounts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if exactRowCounts {
+		targetRowCounts, sourceRowCounts = exactTargetRowCounts, exactSourceRowCounts
 	}
 
 	copyProgress := copyProgress{}
@@ -2311,54 +3630,369 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 	return &copyProgress, nil
 }
 
-// WorkflowUpdate is part of the vtctlservicepb.VtctldServer interface.
-// It passes the embedded TabletRequest object to the given keyspace's
-// target primary tablets that are participating in the given workflow.
-func (s *Server) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest) (*vtctldatapb.WorkflowUpdateResponse, error) {
-	span, ctx := trace.NewSpan(ctx, "workflow.Server.WorkflowUpdate")
+// EstimateWorkflowCompletion estimates how long it will take for the workflow
+// to finish its copy phase. The remaining rows and bytes for each table come
+// from GetCopyProgress, and the throughput is calculated from the number of
+// rows copied by the workflow's streams over a short sampling interval. The
+// returned estimate is marked as unknown if the workflow is not in the copy
+// phase or if no rows were copied during the sampling interval.
+func (s *Server) EstimateWorkflowCompletion(ctx context.Context, keyspace, workflow string) (*WorkflowCompletionEstimate, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.EstimateWorkflowCompletion")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, state, err := s.getWorkflowState(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.GetCopyProgress(ctx, ts, state, false)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil || len(*progress) == 0 {
+		return &WorkflowCompletionEstimate{
+			Unknown:       true,
+			UnknownReason: "workflow is not in the copy phase",
+		}, nil
+	}
+
+	startRows, err := s.getWorkflowRowsCopied(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(s.throughputSampleInterval()):
+	}
+	endRows, err := s.getWorkflowRowsCopied(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	elapsed := time.Since(start)
+	if endRows <= startRows || elapsed <= 0 {
+		return &WorkflowCompletionEstimate{
+			Unknown:       true,
+			UnknownReason: "no copy throughput sample is available yet",
+		}, nil
+	}
+
+	estimate := &WorkflowCompletionEstimate{
+		RowsPerSecond: float64(endRows-startRows) / elapsed.Seconds(),
+		Tables:        make(map[string]*TableCompletionEstimate, len(*progress)),
+	}
+	etaForRows := func(rows int64) time.Duration {
+		return time.Duration(float64(rows) / estimate.RowsPerSecond * float64(time.Second))
+	}
+	var totalRowsRemaining int64
+	for table, tcp := range *progress {
+		tce := &TableCompletionEstimate{
+			RowsRemaining:  max(tcp.SourceRowCount-tcp.TargetRowCount, 0),
+			BytesRemaining: max(tcp.SourceTableSize-tcp.TargetTableSize, 0),
+		}
+		tce.ETA = etaForRows(tce.RowsRemaining)
+		totalRowsRemaining += tce.RowsRemaining
+		estimate.Tables[table] = tce
+	}
+	estimate.ETA = etaForRows(totalRowsRemaining)
+	return estimate, nil
+}
+
+// getWorkflowRowsCopied returns the total number of rows copied by all of the
+// workflow's streams.
+func (s *Server) getWorkflowRowsCopied(ctx context.Context, keyspace, workflow string) (int64, error) {
+	wf, err := s.GetWorkflow(ctx, keyspace, workflow, false, nil)
+	if err != nil {
+		return 0, err
+	}
+	var rowsCopied int64
+	for _, shardStreams := range wf.GetShardStreams() {
+		for _, stream := range shardStreams.GetStreams() {
+			rowsCopied += stream.GetRowsCopied()
+		}
+	}
+	return rowsCopied, nil
+}
+
+// WorkflowUpdate is part of the vtctlservicepb.VtctldServer interface.
+// It passes the embedded TabletRequest object to the given keyspace's
+// target primary tablets that are participating in the given workflow.
+func (s *Server) WorkflowUpdate(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest) (*vtctldatapb.WorkflowUpdateResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.WorkflowUpdate")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("workflow", req.TabletRequest.Workflow)
+	span.Annotate("cells", req.TabletRequest.Cells)
+	span.Annotate("tablet_types", req.TabletRequest.TabletTypes)
+	span.Annotate("on_ddl", req.TabletRequest.OnDdl)
+	span.Annotate("state", req.TabletRequest.State)
+	span.Annotate("include_states", req.TabletRequest.IncludeStates)
+
+	if err := s.validateWorkflowStateTransition(ctx, req); err != nil {
+		return nil, err
+	}
+
+	vx := vexec.NewVExec(req.Keyspace, req.TabletRequest.Workflow, s.ts, s.tmClient(), s.env.Parser())
+	callback := func(ctx context.Context, tablet *topo.TabletInfo) (*querypb.QueryResult, error) {
+		res, err := s.tmClient().UpdateVReplicationWorkflow(ctx, tablet.Tablet, req.TabletRequest)
+		if err != nil {
+			return nil, err
+		}
+		return res.Result, err
+	}
+	updCtx, updCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout*2)
+	defer updCancel()
+	res, err := vx.CallbackContext(updCtx, callback)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return nil, vterrors.Wrapf(err, "%s keyspace does not exist", req.Keyspace)
+		}
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s workflow does not exist in the %s keyspace", req.TabletRequest.Workflow, req.Keyspace)
+	}
+
+	response := &vtctldatapb.WorkflowUpdateResponse{}
+	response.Summary = fmt.Sprintf("Successfully updated the %s workflow on (%d) target primary tablets in the %s keyspace", req.TabletRequest.Workflow, len(res), req.Keyspace)
+	details := make([]*vtctldatapb.WorkflowUpdateResponse_TabletInfo, 0, len(res))
+	for tinfo, tres := range res {
+		result := &vtctldatapb.WorkflowUpdateResponse_TabletInfo{
+			Tablet:  tinfo.Alias,
+			Changed: tres.RowsAffected > 0, // Can be more than one with shard merges
+		}
+		details = append(details, result)
+	}
+	response.Details = details
+	return response, nil
+}
+
+// validateWorkflowStateTransition reads the current state of the workflow's
+// streams and rejects state changes that make no sense for it, such as
+// starting a frozen workflow or -- when the state is the only thing being
+// updated -- moving the workflow to the state that all of its streams are
+// already in. Requests that do not change the state are always allowed.
+func (s *Server) validateWorkflowStateTransition(ctx context.Context, req *vtctldatapb.WorkflowUpdateRequest) error {
+	treq := req.GetTabletRequest()
+	if textutil.ValueIsSimulatedNull(treq.GetState()) {
+		return nil
+	}
+	res, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+		Keyspace: req.GetKeyspace(),
+		Workflow: treq.GetWorkflow(),
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.GetWorkflows()) == 0 {
+		// Let the update itself report that the workflow does not exist.
+		return nil
+	}
+
+	newState := treq.GetState()
+	sameState := true
+	for _, wf := range res.GetWorkflows() {
+		for _, shardStream := range wf.GetShardStreams() {
+			for _, stream := range shardStream.GetStreams() {
+				if newState == binlogdatapb.VReplicationWorkflowState_Running && stream.GetMessage() == Frozen {
+					return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot start the %s workflow in the %s keyspace as it is frozen",
+						treq.GetWorkflow(), req.GetKeyspace())
+				}
+				currentState := stream.GetState()
+				// A running stream that is still copying is reported as Copying.
+				if newState == binlogdatapb.VReplicationWorkflowState_Running && currentState == binlogdatapb.VReplicationWorkflowState_Copying.String() {
+					currentState = binlogdatapb.VReplicationWorkflowState_Running.String()
+				}
+				if currentState != newState.String() {
+					sameState = false
+				}
+			}
+		}
+	}
+
+	// The workflow start and stop commands leave the tablet selection
+	// preference at its zero value, so only an explicit INORDER counts as
+	// another change here.
+	stateOnly := textutil.ValueIsSimulatedNull(treq.GetCells()) &&
+		textutil.ValueIsSimulatedNull(treq.GetTabletTypes()) &&
+		textutil.ValueIsSimulatedNull(treq.GetOnDdl()) &&
+		treq.GetTabletSelectionPreference() != tabletmanagerdatapb.TabletSelectionPreference_INORDER
+	if sameState && stateOnly {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s workflow in the %s keyspace is already in the %s state",
+			treq.GetWorkflow(), req.GetKeyspace(), newState)
+	}
+	return nil
+}
+
+// WorkflowRename renames the given workflow by updating the workflow name
+// on all of its streams, and on any vdiffs of it. The routing rules are keyed
+// by keyspace and table rather than by workflow, so they do not need to be
+// updated. Workflows that have had their writes switched cannot be renamed,
+// as the reverse workflow is named after the original one.
+func (s *Server) WorkflowRename(ctx context.Context, keyspace, oldName, newName string) (err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.WorkflowRename")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", oldName)
+	span.Annotate("new_workflow", newName)
+
+	if newName == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a new name must be provided for the %s workflow", oldName)
+	}
+	if newName == oldName {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the %s workflow is already named %s", oldName, newName)
+	}
+	if strings.HasSuffix(oldName, reverseSuffix) || strings.HasSuffix(newName, reverseSuffix) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "workflow names ending in %s are reserved for reverse workflows", reverseSuffix)
+	}
+
+	ts, state, err := s.getWorkflowState(ctx, keyspace, oldName)
+	if err != nil {
+		return err
+	}
+
+	// Hold the target keyspace lock from before the new name is checked
+	// until every shard has been renamed, so that no workflow with the
+	// new name can be created in the meantime.
+	sw := &switcher{s: s, ts: ts}
+	lockCtx, targetUnlock, lockErr := sw.lockKeyspace(ctx, ts.TargetKeyspaceName(), "WorkflowRename")
+	if lockErr != nil {
+		ts.Logger().Errorf("Locking target keyspace %s failed: %v", ts.TargetKeyspaceName(), lockErr)
+		return lockErr
+	}
+	defer targetUnlock(&err)
+	ctx = lockCtx
+
+	if state.WritesSwitched {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot rename the %s workflow in the %s keyspace as writes have been switched",
+			oldName, keyspace)
+	}
+	if err := validateNewWorkflow(ctx, s.ts, s.tmClient(), keyspace, newName); err != nil {
+		return err
+	}
+
+	// Each target shard is renamed in a single transaction, so a shard is
+	// either fully renamed or not at all. If any shard fails, the shards
+	// that were renamed are renamed back so that the workflow keeps a
+	// single name.
+	var (
+		mu      sync.Mutex
+		renamed []*MigrationTarget
+	)
+	renameErr := ts.ForAllTargets(func(target *MigrationTarget) error {
+		if err := s.renameWorkflowOnTarget(ctx, target, oldName, newName); err != nil {
+			return vterrors.Wrapf(err, "failed to rename the %s workflow on shard %s", oldName, target.GetShard().ShardName())
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		renamed = append(renamed, target)
+		return nil
+	})
+	if renameErr == nil {
+		return nil
+	}
+	var notReverted []string
+	for _, target := range renamed {
+		if err := s.renameWorkflowOnTarget(ctx, target, newName, oldName); err != nil {
+			ts.Logger().Errorf("Failed to rename the %s workflow back to %s on shard %s: %v", newName, oldName, target.GetShard().ShardName(), err)
+			notReverted = append(notReverted, target.GetShard().ShardName())
+		}
+	}
+	if len(notReverted) > 0 {
+		slices.Sort(notReverted)
+		return vterrors.Wrapf(renameErr, "the workflow is still named %s on shards %s", newName, strings.Join(notReverted, ", "))
+	}
+	return renameErr
+}
+
+// renameWorkflowOnTarget renames the workflow's streams on the given target
+// shard's primary tablet.
+func (s *Server) renameWorkflowOnTarget(ctx context.Context, target *MigrationTarget, oldName, newName string) error {
+	_, err := s.tmClient().UpdateVReplicationWorkflow(ctx, target.GetPrimary().Tablet, &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+		Workflow:                  oldName,
+		Cells:                     textutil.SimulatedNullStringSlice,
+		TabletTypes:               []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+		TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN,
+		OnDdl:                     binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+		State:                     binlogdatapb.VReplicationWorkflowState(textutil.SimulatedNullInt),
+		NewWorkflowName:           newName,
+	})
+	return err
+}
+
+// WorkflowStopStream stops a single stream of the workflow -- the one with
+// the given id on the given target shard -- leaving the workflow's other
+// streams as they are.
+func (s *Server) WorkflowStopStream(ctx context.Context, keyspace, workflow, shard string, streamID int32) error {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.WorkflowStopStream")
 	defer span.Finish()
 
-	span.Annotate("keyspace", req.Keyspace)
-	span.Annotate("workflow", req.TabletRequest.Workflow)
-	span.Annotate("cells", req.TabletRequest.Cells)
-	span.Annotate("tablet_types", req.TabletRequest.TabletTypes)
-	span.Annotate("on_ddl", req.TabletRequest.OnDdl)
-	span.Annotate("state", req.TabletRequest.State)
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("shard", shard)
+	span.Annotate("stream_id", streamID)
 
-	vx := vexec.NewVExec(req.Keyspace, req.TabletRequest.Workflow, s.ts, s.tmc, s.env.Parser())
-	callback := func(ctx context.Context, tablet *topo.TabletInfo) (*querypb.QueryResult, error) {
-		res, err := s.tmc.UpdateVReplicationWorkflow(ctx, tablet.Tablet, req.TabletRequest)
-		if err != nil {
-			return nil, err
-		}
-		return res.Result, err
-	}
-	updCtx, updCancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout*2)
-	defer updCancel()
-	res, err := vx.CallbackContext(updCtx, callback)
+	return s.updateWorkflowStreamState(ctx, keyspace, workflow, shard, streamID, binlogdatapb.VReplicationWorkflowState_Stopped)
+}
+
+// WorkflowStartStream starts a single stream of the workflow -- the one with
+// the given id on the given target shard -- leaving the workflow's other
+// streams as they are.
+func (s *Server) WorkflowStartStream(ctx context.Context, keyspace, workflow, shard string, streamID int32) error {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.WorkflowStartStream")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+	span.Annotate("shard", shard)
+	span.Annotate("stream_id", streamID)
+
+	return s.updateWorkflowStreamState(ctx, keyspace, workflow, shard, streamID, binlogdatapb.VReplicationWorkflowState_Running)
+}
+
+// updateWorkflowStreamState sets the state of the stream with the given id
+// on the given target shard's primary tablet, without changing any of the
+// stream's other settings.
+func (s *Server) updateWorkflowStreamState(ctx context.Context, keyspace, workflow, shard string, streamID int32, state binlogdatapb.VReplicationWorkflowState) error {
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
 	if err != nil {
-		if topo.IsErrType(err, topo.NoNode) {
-			return nil, vterrors.Wrapf(err, "%s keyspace does not exist", req.Keyspace)
-		}
-		return nil, err
+		return err
 	}
-
-	if len(res) == 0 {
-		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the %s workflow does not exist in the %s keyspace", req.TabletRequest.Workflow, req.Keyspace)
+	if si.PrimaryAlias == nil {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", keyspace, shard)
 	}
-
-	response := &vtctldatapb.WorkflowUpdateResponse{}
-	response.Summary = fmt.Sprintf("Successfully updated the %s workflow on (%d) target primary tablets in the %s keyspace", req.TabletRequest.Workflow, len(res), req.Keyspace)
-	details := make([]*vtctldatapb.WorkflowUpdateResponse_TabletInfo, 0, len(res))
-	for tinfo, tres := range res {
-		result := &vtctldatapb.WorkflowUpdateResponse_TabletInfo{
-			Tablet:  tinfo.Alias,
-			Changed: tres.RowsAffected > 0, // Can be more than one with shard merges
-		}
-		details = append(details, result)
+	primary, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return err
 	}
-	response.Details = details
-	return response, nil
+	res, err := s.tmClient().ReadVReplicationWorkflow(ctx, primary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+		Workflow: workflow,
+	})
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(res.GetStreams(), func(stream *tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream) bool {
+		return stream.GetId() == streamID
+	}) {
+		return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "stream %d of the %s workflow does not exist on shard %s/%s",
+			streamID, workflow, keyspace, shard)
+	}
+	_, err = s.tmClient().UpdateVReplicationWorkflow(ctx, primary.Tablet, &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+		Workflow:                  workflow,
+		Cells:                     textutil.SimulatedNullStringSlice,
+		TabletTypes:               []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+		TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN,
+		OnDdl:                     binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+		State:                     state,
+		IncludeIds:                []int32{streamID},
+	})
+	return err
 }
 
 // validateSourceTablesExist validates that tables provided are present
@@ -2431,7 +4065,7 @@ func (s *Server) collectTargetStreams(ctx context.Context, mz *materializer) ([]
 		if err != nil {
 			return vterrors.Wrapf(err, "GetTablet(%v) failed", target.PrimaryAlias)
 		}
-		res, err := s.tmc.ReadVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+		res, err := s.tmClient().ReadVReplicationWorkflow(ctx, targetPrimary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
 			Workflow: mz.ms.Workflow,
 		})
 		if err != nil {
@@ -2497,18 +4131,89 @@ func (s *Server) checkIfPreviousJournalExists(ctx context.Context, mz *materiali
 }
 
 // deleteWorkflowVDiffData cleans up any potential VDiff related data associated
-// with the workflow on the given tablet.
-func (s *Server) deleteWorkflowVDiffData(ctx context.Context, tablet *topodatapb.Tablet, workflow string) {
-	if _, err := s.tmc.VDiff(ctx, tablet, &tabletmanagerdatapb.VDiffRequest{
-		Keyspace:  tablet.Keyspace,
-		Workflow:  workflow,
-		Action:    string(vdiff.DeleteAction),
-		ActionArg: vdiff.AllActionArg,
+// with the workflow on the given tablet. The data for any tables in keepTables
+// is retained.
+func (s *Server) deleteWorkflowVDiffData(ctx context.Context, tablet *topodatapb.Tablet, workflow string, keepTables []string) {
+	if _, err := s.tmClient().VDiff(ctx, tablet, &tabletmanagerdatapb.VDiffRequest{
+		Keyspace:   tablet.Keyspace,
+		Workflow:   workflow,
+		Action:     string(vdiff.DeleteAction),
+		ActionArg:  vdiff.AllActionArg,
+		KeepTables: keepTables,
 	}); err != nil {
 		log.Errorf("Error deleting vdiff data for %s.%s workflow: %v", tablet.Keyspace, workflow, err)
 	}
 }
 
+// GetVReplicationTableSizes returns the on-disk size, in bytes, of each of
+// the sidecar database tables used by VReplication and VDiff on the primary
+// tablet of each of the given shards -- or of all shards when none are given
+// -- in the keyspace. The sizes are keyed by tablet alias and then by table
+// name, and are the estimates from information_schema.tables.
+func (s *Server) GetVReplicationTableSizes(ctx context.Context, keyspace string, shards []string) (map[string]map[string]int64, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetVReplicationTableSizes")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("shards", shards)
+
+	shards, err := common.GetShards(ctx, s.ts, keyspace, shards)
+	if err != nil {
+		return nil, err
+	}
+	tableList := make([]string, 0, len(vreplicationTables))
+	for _, table := range vreplicationTables {
+		tableList = append(tableList, encodeString(table))
+	}
+	query := fmt.Sprintf("select table_name, data_length + index_length from information_schema.tables where table_schema = '_vt' and table_name in (%s)",
+		strings.Join(tableList, ", "))
+
+	var (
+		m     sync.Mutex
+		sizes = make(map[string]map[string]int64, len(shards))
+	)
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(topo.DefaultConcurrency)
+	for _, shard := range shards {
+		eg.Go(func() error {
+			si, err := s.ts.GetShard(ectx, keyspace, shard)
+			if err != nil {
+				return err
+			}
+			if si.PrimaryAlias == nil {
+				return fmt.Errorf("%w %s/%s", vexec.ErrNoShardPrimary, keyspace, shard)
+			}
+			primary, err := s.ts.GetTablet(ectx, si.PrimaryAlias)
+			if err != nil {
+				return err
+			}
+			p3qr, err := s.tmClient().ExecuteFetchAsDba(ectx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:   []byte(query),
+				MaxRows: uint64(len(vreplicationTables)),
+			})
+			if err != nil {
+				return vterrors.Wrapf(err, "failed to get the vreplication table sizes on %s", primary.AliasString())
+			}
+			tabletSizes := make(map[string]int64, len(vreplicationTables))
+			for _, row := range sqltypes.Proto3ToResult(p3qr).Rows {
+				size, err := row[1].ToCastInt64()
+				if err != nil {
+					return err
+				}
+				tabletSizes[row[0].ToString()] = size
+			}
+			m.Lock()
+			defer m.Unlock()
+			sizes[primary.AliasString()] = tabletSizes
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
+
 // optimizeCopyStateTable rebuilds the copy_state table to ensure the on-disk
 // structures are minimal and optimized and resets the auto-inc value for
 // subsequent inserts.
@@ -2539,7 +4244,7 @@ func (s *Server) optimizeCopyStateTable(tablet *topodatapb.Tablet) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 		defer cancel()
 		sqlOptimizeTable := "optimize table _vt.copy_state"
-		if _, err := s.tmc.ExecuteFetchAsAllPrivs(ctx, tablet, &tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest{
+		if _, err := s.tmClient().ExecuteFetchAsAllPrivs(ctx, tablet, &tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest{
 			Query:   []byte(sqlOptimizeTable),
 			MaxRows: uint64(100), // always produces 1+rows with notes and status
 		}); err != nil {
@@ -2551,7 +4256,7 @@ func (s *Server) optimizeCopyStateTable(tablet *topodatapb.Tablet) {
 		// This will automatically set the value to 1 or the current max value in the
 		// table, whichever is greater.
 		sqlResetAutoInc := "alter table _vt.copy_state auto_increment = 1"
-		if _, err := s.tmc.ExecuteFetchAsAllPrivs(ctx, tablet, &tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest{
+		if _, err := s.tmClient().ExecuteFetchAsAllPrivs(ctx, tablet, &tabletmanagerdatapb.ExecuteFetchAsAllPrivsRequest{
 			Query:   []byte(sqlResetAutoInc),
 			MaxRows: uint64(0),
 		}); err != nil {
@@ -2563,7 +4268,7 @@ func (s *Server) optimizeCopyStateTable(tablet *topodatapb.Tablet) {
 
 // DropTargets cleans up target tables, shards and denied tables if a MoveTables/Reshard
 // is cancelled.
-func (s *Server) DropTargets(ctx context.Context, ts *trafficSwitcher, keepData, keepRoutingRules, dryRun bool) (*[]string, error) {
+func (s *Server) DropTargets(ctx context.Context, ts *trafficSwitcher, keepData, keepRoutingRules, dryRun bool) (*LogRecorder, error) {
 	var err error
 	ts.keepRoutingRules = keepRoutingRules
 	var sw iswitcher
@@ -2618,7 +4323,7 @@ func (s *Server) DropTargets(ctx context.Context, ts *trafficSwitcher, keepData,
 }
 
 func (s *Server) buildTrafficSwitcher(ctx context.Context, targetKeyspace, workflowName string) (*trafficSwitcher, error) {
-	tgtInfo, err := BuildTargets(ctx, s.ts, s.tmc, targetKeyspace, workflowName)
+	tgtInfo, err := BuildTargets(ctx, s.ts, s.tmClient(), targetKeyspace, workflowName)
 	if err != nil {
 		log.Infof("Error building targets: %s", err)
 		return nil, err
@@ -2627,7 +4332,7 @@ func (s *Server) buildTrafficSwitcher(ctx context.Context, targetKeyspace, workf
 
 	ts := &trafficSwitcher{
 		ws:              s,
-		logger:          logutil.NewConsoleLogger(),
+		logger:          s.Logger(),
 		workflow:        workflowName,
 		reverseWorkflow: ReverseWorkflowName(workflowName),
 		id:              HashStreams(targetKeyspace, targets),
@@ -2754,7 +4459,8 @@ func (s *Server) dropRelatedArtifacts(ctx context.Context, keepRoutingRules bool
 
 // dropSources cleans up source tables, shards and denied tables after a
 // MoveTables/Reshard is completed.
-func (s *Server) dropSources(ctx context.Context, ts *trafficSwitcher, removalType TableRemovalType, keepData, keepRoutingRules, force, dryRun bool) (*[]string, error) {
+func (s *Server) dropSources(ctx context.Context, ts *trafficSwitcher, removalType TableRemovalType, keepData, keepRoutingRules, keepSourceVSchema, force, dryRun bool,
+	preDropSourceSQL []string, ignorePreDropErrors bool) (*LogRecorder, error) {
 	var (
 		sw  iswitcher
 		err error
@@ -2790,8 +4496,13 @@ func (s *Server) dropSources(ctx context.Context, ts *trafficSwitcher, removalTy
 	if !keepData {
 		switch ts.MigrationType() {
 		case binlogdatapb.MigrationType_TABLES:
+			if len(preDropSourceSQL) > 0 {
+				if err := sw.executePreDropSourceSQL(ctx, preDropSourceSQL, ignorePreDropErrors); err != nil {
+					return nil, err
+				}
+			}
 			log.Infof("Deleting tables")
-			if err := sw.removeSourceTables(ctx, removalType); err != nil {
+			if err := sw.removeSourceTables(ctx, removalType, keepSourceVSchema); err != nil {
 				return nil, err
 			}
 			if err := sw.dropSourceDeniedTables(ctx); err != nil {
@@ -2958,7 +4669,7 @@ func (s *Server) DeleteShard(ctx context.Context, keyspace, shard string, recurs
 func (s *Server) updateShardRecords(ctx context.Context, keyspace string, shards []*topo.ShardInfo, cells []string,
 	servedType topodatapb.TabletType, isFrom bool, clearSourceShards bool, logger logutil.Logger,
 ) (err error) {
-	return topotools.UpdateShardRecords(ctx, s.ts, s.tmc, keyspace, shards, cells, servedType, isFrom, clearSourceShards, logger)
+	return topotools.UpdateShardRecords(ctx, s.ts, s.tmClient(), keyspace, shards, cells, servedType, isFrom, clearSourceShards, logger)
 }
 
 // refreshPrimaryTablets will just RPC-ping all the primary tablets with RefreshState
@@ -2975,7 +4686,7 @@ func (s *Server) refreshPrimaryTablets(ctx context.Context, shards []*topo.Shard
 				return
 			}
 
-			if err := s.tmc.RefreshState(ctx, ti.Tablet); err != nil {
+			if err := s.tmClient().RefreshState(ctx, ti.Tablet); err != nil {
 				rec.RecordError(err)
 			} else {
 				log.Infof("%v responded", topoproto.TabletAliasString(si.PrimaryAlias))
@@ -2988,7 +4699,7 @@ func (s *Server) refreshPrimaryTablets(ctx context.Context, shards []*topo.Shard
 
 // finalizeMigrateWorkflow deletes the streams for the Migrate workflow.
 // We only cleanup the target for external sources.
-func (s *Server) finalizeMigrateWorkflow(ctx context.Context, ts *trafficSwitcher, tableSpecs string, cancel, keepData, keepRoutingRules, dryRun bool) (*[]string, error) {
+func (s *Server) finalizeMigrateWorkflow(ctx context.Context, ts *trafficSwitcher, tableSpecs string, cancel, keepData, keepRoutingRules, dryRun bool) (*LogRecorder, error) {
 	var (
 		sw  iswitcher
 		err error
@@ -3027,10 +4738,11 @@ func (s *Server) finalizeMigrateWorkflow(ctx context.Context, ts *trafficSwitche
 }
 
 // WorkflowSwitchTraffic switches traffic in the direction passed for specified tablet types.
-func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
+func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, opts ...WorkflowActionOption) (*vtctldatapb.WorkflowSwitchTrafficResponse, error) {
 	var (
 		dryRunResults                     []string
-		rdDryRunResults, wrDryRunResults  *[]string
+		dryRunSteps                       []*vtctldatapb.DryRunStep
+		rdDryRunResults, wrDryRunResults  *LogRecorder
 		hasReplica, hasRdonly, hasPrimary bool
 	)
 	timeout, set, err := protoutil.DurationFromProto(req.Timeout)
@@ -3039,12 +4751,20 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 		return nil, err
 	}
 	if !set {
-		timeout = defaultDuration
+		timeout = s.defaultTimeout(req.Keyspace)
+	}
+	if req.GetCreateReverseWorkflowOnly() && req.GetEnableReverseReplication() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "create_reverse_workflow_only cannot be used with enable_reverse_replication")
 	}
+	if strings.Contains(req.GetIdempotencyKey(), ",") {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "idempotency key %q cannot contain a comma", req.GetIdempotencyKey())
+	}
+	options := s.processWorkflowActionOptions(opts)
 	ts, startState, err := s.getWorkflowState(ctx, req.Keyspace, req.Workflow)
 	if err != nil {
 		return nil, err
 	}
+	ts.logger = options.logger
 
 	if startState.WorkflowType == TypeMigrate {
 		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid action for Migrate workflow: SwitchTraffic")
@@ -3056,7 +4776,7 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 		return nil, err
 	}
 	if !set {
-		maxReplicationLagAllowed = defaultDuration
+		maxReplicationLagAllowed = s.defaultTimeout(req.Keyspace)
 	}
 	direction := TrafficSwitchDirection(req.Direction)
 	if direction == DirectionBackward {
@@ -3064,6 +4784,7 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 		if err != nil {
 			return nil, err
 		}
+		ts.logger = options.logger
 		if ts.IsMultiTenantMigration() {
 			// In a multi-tenant migration, multiple migrations would be writing to the same table, so we can't stop writes like
 			// we do with MoveTables, using denied tables, since it would block all other migrations as well as traffic for
@@ -3071,6 +4792,26 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot reverse traffic for multi-tenant migrations")
 		}
 	}
+	cmd := "SwitchTraffic"
+	if direction == DirectionBackward {
+		cmd = "ReverseTraffic"
+	}
+	if req.IdempotencyKey != "" {
+		done, err := ts.hasSwitchTrafficKey(ctx, req.IdempotencyKey)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			// A previous request with the same key already completed, so we
+			// return the current state rather than switching traffic again.
+			log.Infof("%s was already done for workflow %s.%s with idempotency key %s", cmd, req.Keyspace, req.Workflow, req.IdempotencyKey)
+			return &vtctldatapb.WorkflowSwitchTrafficResponse{
+				Summary:      fmt.Sprintf("%s was already done for workflow %s.%s with idempotency key %s", cmd, req.Keyspace, req.Workflow, req.IdempotencyKey),
+				StartState:   startState.String(),
+				CurrentState: startState.String(),
+			}, nil
+		}
+	}
 	reason, err := s.canSwitch(ctx, ts, startState, direction, int64(maxReplicationLagAllowed.Seconds()), req.Shards)
 	if err != nil {
 		return nil, err
@@ -3090,9 +4831,8 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 		}
 		log.Infof("Switch Reads done for workflow %s.%s", req.Keyspace, req.Workflow)
 	}
-	if rdDryRunResults != nil {
-		dryRunResults = append(dryRunResults, *rdDryRunResults...)
-	}
+	dryRunResults = append(dryRunResults, rdDryRunResults.GetLogs()...)
+	dryRunSteps = append(dryRunSteps, rdDryRunResults.GetSteps()...)
 	if hasPrimary {
 		if _, wrDryRunResults, err = s.switchWrites(ctx, req, ts, timeout, false); err != nil {
 			return nil, err
@@ -3100,24 +4840,31 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 		log.Infof("Switch Writes done for workflow %s.%s", req.Keyspace, req.Workflow)
 	}
 
-	if wrDryRunResults != nil {
-		dryRunResults = append(dryRunResults, *wrDryRunResults...)
-	}
+	dryRunResults = append(dryRunResults, wrDryRunResults.GetLogs()...)
+	dryRunSteps = append(dryRunSteps, wrDryRunResults.GetSteps()...)
 	if req.DryRun && len(dryRunResults) == 0 {
 		dryRunResults = append(dryRunResults, "No changes required")
+		dryRunSteps = append(dryRunSteps, &vtctldatapb.DryRunStep{Details: "No changes required"})
 	}
-	cmd := "SwitchTraffic"
-	if direction == DirectionBackward {
-		cmd = "ReverseTraffic"
+	reverseWorkflowNote := ""
+	if hasPrimary && req.CreateReverseWorkflowOnly {
+		reverseWorkflowNote = fmt.Sprintf("; the reverse workflow %s.%s is stopped and must be started manually before traffic can be reversed",
+			ts.SourceKeyspaceName(), ts.ReverseWorkflowName())
+	}
+	if !req.DryRun && req.IdempotencyKey != "" {
+		if err := ts.recordSwitchTrafficKey(ctx, req.IdempotencyKey); err != nil {
+			return nil, err
+		}
 	}
 	log.Infof("%s done for workflow %s.%s", cmd, req.Keyspace, req.Workflow)
 	resp := &vtctldatapb.WorkflowSwitchTrafficResponse{}
 	if req.DryRun {
-		resp.Summary = fmt.Sprintf("%s dry run results for workflow %s.%s at %v", cmd, req.Keyspace, req.Workflow, time.Now().UTC().Format(time.RFC822))
+		resp.Summary = fmt.Sprintf("%s dry run results for workflow %s.%s at %v%s", cmd, req.Keyspace, req.Workflow, time.Now().UTC().Format(time.RFC822), reverseWorkflowNote)
 		resp.DryRunResults = dryRunResults
+		resp.DryRunSteps = dryRunSteps
 	} else {
 		log.Infof("%s done for workflow %s.%s", cmd, req.Keyspace, req.Workflow)
-		resp.Summary = fmt.Sprintf("%s was successful for workflow %s.%s", cmd, req.Keyspace, req.Workflow)
+		resp.Summary = fmt.Sprintf("%s was successful for workflow %s.%s%s", cmd, req.Keyspace, req.Workflow, reverseWorkflowNote)
 		// Reload the state after the SwitchTraffic operation
 		// and return that as a string.
 		keyspace := req.Keyspace
@@ -3140,7 +4887,7 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 }
 
 // switchReads is a generic way of switching read traffic for a workflow.
-func (s *Server) switchReads(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, ts *trafficSwitcher, state *State, rebuildSrvVSchema bool, direction TrafficSwitchDirection) (*[]string, error) {
+func (s *Server) switchReads(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, ts *trafficSwitcher, state *State, rebuildSrvVSchema bool, direction TrafficSwitchDirection) (*LogRecorder, error) {
 	var roTabletTypes []topodatapb.TabletType
 	// When we are switching all traffic we also get the primary tablet type, which we need to
 	// filter out for switching reads.
@@ -3164,7 +4911,7 @@ func (s *Server) switchReads(ctx context.Context, req *vtctldatapb.WorkflowSwitc
 	cellsStr := strings.Join(req.Cells, ",")
 
 	// Consistently handle errors by logging and returning them.
-	handleError := func(message string, err error) (*[]string, error) {
+	handleError := func(message string, err error) (*LogRecorder, error) {
 		werr := vterrors.Wrapf(err, message)
 		ts.Logger().Error(werr)
 		return nil, werr
@@ -3261,6 +5008,14 @@ func (s *Server) switchReads(ctx context.Context, req *vtctldatapb.WorkflowSwitc
 			if err != nil {
 				return handleError("failed to switch read traffic for the tables", err)
 			}
+			// The SrvVSchema is only rebuilt here when we're not also going
+			// to switch writes, which will rebuild it again.
+			if rebuildSrvVSchema && !req.DryRun {
+				if err := validateSrvVSchemaRoutingRules(ctx, s.ts, req.Cells); err != nil {
+					err2 := vterrors.Wrapf(err, "after switching table reads, found SrvVSchema routing rules are corrupt")
+					return handleError("failed to validate SrvVSchema records", err2)
+				}
+			}
 		}
 		return sw.logs(), nil
 	}
@@ -3278,10 +5033,30 @@ func (s *Server) switchReads(ctx context.Context, req *vtctldatapb.WorkflowSwitc
 	return sw.logs(), nil
 }
 
+// traceSwitchWritesStep runs a single step of switchWrites in its own child
+// trace span, so that the time taken by each step can be seen in a trace.
+func traceSwitchWritesStep(ctx context.Context, step string, f func(ctx context.Context) error) error {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.switchWrites."+step)
+	defer span.Finish()
+
+	err := f(ctx)
+	if err != nil {
+		span.Annotate("error", err.Error())
+	}
+	return err
+}
+
 // switchWrites is a generic way of migrating write traffic for a workflow.
 func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, ts *trafficSwitcher, timeout time.Duration,
 	cancel bool,
-) (journalID int64, dryRunResults *[]string, err error) {
+) (journalID int64, dryRunResults *LogRecorder, err error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.switchWrites")
+	defer span.Finish()
+
+	span.Annotate("keyspace", ts.TargetKeyspaceName())
+	span.Annotate("workflow", ts.WorkflowName())
+	span.Annotate("dry_run", req.DryRun)
+
 	var sw iswitcher
 	if req.DryRun {
 		sw = &switcherDryRun{ts: ts, drLog: NewLogRecorder()}
@@ -3290,7 +5065,7 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 	}
 
 	// Consistently handle errors by logging and returning them.
-	handleError := func(message string, err error) (int64, *[]string, error) {
+	handleError := func(message string, err error) (int64, *LogRecorder, error) {
 		werr := vterrors.Wrapf(err, message)
 		ts.Logger().Error(werr)
 		return 0, nil, werr
@@ -3301,14 +5076,25 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 		return 0, sw.logs(), nil
 	}
 
+	drainTimeout, _, err := protoutil.DurationFromProto(req.GetDrainTimeout())
+	if err != nil {
+		return handleError("unable to parse DrainTimeout into a valid duration", err)
+	}
+
 	if err := ts.validate(ctx); err != nil {
 		return handleError("workflow validation failed", err)
 	}
 
-	if req.EnableReverseReplication {
+	// The reverse workflow is only started when reverse replication is enabled,
+	// but if it is to be created for later use then we should still make sure
+	// that it will be able to run.
+	if req.EnableReverseReplication || req.CreateReverseWorkflowOnly {
 		if err := areTabletsAvailableToStreamFrom(ctx, req, ts, ts.TargetKeyspaceName(), ts.TargetShards()); err != nil {
 			return handleError(fmt.Sprintf("no tablets were available to stream from in the %s keyspace", ts.SourceKeyspaceName()), err)
 		}
+		if err := sw.validateReverseReplication(ctx); err != nil {
+			return handleError("reverse replication cannot be created", err)
+		}
 	}
 
 	// Need to lock both source and target keyspaces.
@@ -3366,7 +5152,9 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 		// the keyspace being resharded, we wait for those to catchup in the stopStreams path before
 		// we actually stop them.
 		ts.Logger().Infof("Stopping source writes")
-		if err := sw.stopSourceWrites(ctx); err != nil {
+		if err := traceSwitchWritesStep(ctx, "stopSourceWrites", func(ctx context.Context) error {
+			return sw.stopSourceWrites(ctx, drainTimeout)
+		}); err != nil {
 			sw.cancelMigration(ctx, sm)
 			return handleError(fmt.Sprintf("failed to stop writes in the %s keyspace", ts.SourceKeyspaceName()), err)
 		}
@@ -3378,7 +5166,11 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 		// with the inter-keyspace workflows.
 		stopCtx, stopCancel := context.WithTimeout(ctx, timeout)
 		defer stopCancel()
-		sourceWorkflows, err = sw.stopStreams(stopCtx, sm)
+		err = traceSwitchWritesStep(stopCtx, "stopStreams", func(ctx context.Context) error {
+			var err error
+			sourceWorkflows, err = sw.stopStreams(ctx, sm)
+			return err
+		})
 		if err != nil {
 			for key, streams := range sm.Streams() {
 				for _, stream := range streams {
@@ -3393,37 +5185,48 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 			ts.Logger().Infof("Executing LOCK TABLES on source tables %d times", lockTablesCycles)
 			// Doing this twice with a pause in-between to catch any writes that may have raced in between
 			// the tablet's deny list check and the first mysqld side table lock.
-			for cnt := 1; cnt <= lockTablesCycles; cnt++ {
-				if err := ts.executeLockTablesOnSource(ctx); err != nil {
-					sw.cancelMigration(ctx, sm)
-					return handleError(fmt.Sprintf("failed to execute LOCK TABLES (attempt %d of %d) on sources", cnt, lockTablesCycles), err)
+			cnt := 0
+			err = traceSwitchWritesStep(ctx, "lockSourceTables", func(ctx context.Context) error {
+				for cnt = 1; cnt <= lockTablesCycles; cnt++ {
+					if err := ts.executeLockTablesOnSource(ctx); err != nil {
+						return err
+					}
+					// No need to UNLOCK the tables as the connection was closed once the locks were acquired
+					// and thus the locks released.
+					time.Sleep(lockTablesCycleDelay)
 				}
-				// No need to UNLOCK the tables as the connection was closed once the locks were acquired
-				// and thus the locks released.
-				time.Sleep(lockTablesCycleDelay)
+				return nil
+			})
+			if err != nil {
+				sw.cancelMigration(ctx, sm)
+				return handleError(fmt.Sprintf("failed to execute LOCK TABLES (attempt %d of %d) on sources", cnt, lockTablesCycles), err)
 			}
 		}
 
 		ts.Logger().Infof("Waiting for streams to catchup")
-		if err := sw.waitForCatchup(ctx, timeout); err != nil {
+		if err := traceSwitchWritesStep(ctx, "waitForCatchup", func(ctx context.Context) error {
+			return sw.waitForCatchup(ctx, timeout)
+		}); err != nil {
 			sw.cancelMigration(ctx, sm)
 			return handleError("failed to sync up replication between the source and target", err)
 		}
 
 		ts.Logger().Infof("Migrating streams")
-		if err := sw.migrateStreams(ctx, sm); err != nil {
+		if err := traceSwitchWritesStep(ctx, "migrateStreams", func(ctx context.Context) error {
+			return sw.migrateStreams(ctx, sm)
+		}); err != nil {
 			sw.cancelMigration(ctx, sm)
 			return handleError("failed to migrate the workflow streams", err)
 		}
 
 		ts.Logger().Infof("Resetting sequences")
-		if err := sw.resetSequences(ctx); err != nil {
+		if err := traceSwitchWritesStep(ctx, "resetSequences", sw.resetSequences); err != nil {
 			sw.cancelMigration(ctx, sm)
 			return handleError("failed to reset the sequences", err)
 		}
 
 		ts.Logger().Infof("Creating reverse streams")
-		if err := sw.createReverseVReplication(ctx); err != nil {
+		if err := traceSwitchWritesStep(ctx, "createReverseVReplication", sw.createReverseVReplication); err != nil {
 			sw.cancelMigration(ctx, sm)
 			return handleError("failed to create the reverse vreplication streams", err)
 		}
@@ -3436,7 +5239,9 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 			// We use at most half of the overall timeout.
 			initSeqCtx, cancel := context.WithTimeout(ctx, timeout/2)
 			defer cancel()
-			if err := sw.initializeTargetSequences(initSeqCtx, sequenceMetadata); err != nil {
+			if err := traceSwitchWritesStep(initSeqCtx, "initializeTargetSequences", func(ctx context.Context) error {
+				return sw.initializeTargetSequences(ctx, sequenceMetadata)
+			}); err != nil {
 				sw.cancelMigration(ctx, sm)
 				return handleError(fmt.Sprintf("failed to initialize the sequences used in the %s keyspace", ts.TargetKeyspaceName()), err)
 			}
@@ -3454,31 +5259,100 @@ func (s *Server) switchWrites(ctx context.Context, req *vtctldatapb.WorkflowSwit
 
 	// This is the point of no return. Once a journal is created,
 	// traffic can be redirected to target shards.
-	if err := sw.createJournals(ctx, sourceWorkflows); err != nil {
+	if err := traceSwitchWritesStep(ctx, "createJournals", func(ctx context.Context) error {
+		return sw.createJournals(ctx, sourceWorkflows)
+	}); err != nil {
 		return handleError("failed to create the journal", err)
 	}
-	if err := sw.allowTargetWrites(ctx); err != nil {
+	if err := traceSwitchWritesStep(ctx, "allowTargetWrites", sw.allowTargetWrites); err != nil {
 		return handleError(fmt.Sprintf("failed to allow writes in the %s keyspace", ts.TargetKeyspaceName()), err)
 	}
-	if err := sw.changeRouting(ctx); err != nil {
+	if err := traceSwitchWritesStep(ctx, "changeRouting", sw.changeRouting); err != nil {
 		return handleError("failed to update the routing rules", err)
 	}
 	if err := sw.streamMigraterfinalize(ctx, ts, sourceWorkflows); err != nil {
 		return handleError("failed to finalize the traffic switch", err)
 	}
 	if req.EnableReverseReplication {
-		if err := sw.startReverseVReplication(ctx); err != nil {
+		if err := traceSwitchWritesStep(ctx, "startReverseVReplication", sw.startReverseVReplication); err != nil {
 			return handleError("failed to start the reverse workflow", err)
 		}
+	} else if req.CreateReverseWorkflowOnly {
+		ts.Logger().Infof("The reverse workflow %s was created in the %s keyspace but was not started", ts.ReverseWorkflowName(), ts.SourceKeyspaceName())
 	}
 
-	if err := sw.freezeTargetVReplication(ctx); err != nil {
+	if err := traceSwitchWritesStep(ctx, "freezeTargetVReplication", sw.freezeTargetVReplication); err != nil {
 		return handleError(fmt.Sprintf("failed to freeze the workflow in the %s keyspace", ts.TargetKeyspaceName()), err)
 	}
 
 	return ts.id, sw.logs(), nil
 }
 
+// GetSwitchableDirections returns the tablet types whose traffic can currently
+// be switched forward, using SwitchTraffic, and backward, using ReverseTraffic,
+// for the given workflow. Traffic can only be switched backward once the
+// reverse workflow exists, and Migrate workflows cannot switch traffic at all.
+func (s *Server) GetSwitchableDirections(ctx context.Context, keyspace, workflow string) (*SwitchableDirections, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetSwitchableDirections")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, state, err := s.getWorkflowState(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	directions := &SwitchableDirections{}
+	if state.WorkflowType == TypeMigrate {
+		return directions, nil
+	}
+
+	canReverse := !ts.IsMultiTenantMigration()
+	if canReverse {
+		if _, err := s.buildTrafficSwitcher(ctx, state.SourceKeyspace, ts.reverseWorkflow); err != nil {
+			if !errors.Is(err, ErrNoStreams) {
+				return nil, err
+			}
+			canReverse = false
+		}
+	}
+
+	if state.IsPartialMigration {
+		// Shard level traffic switching is all or nothing.
+		allTabletTypes := []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY, topodatapb.TabletType_PRIMARY}
+		if len(state.ShardsAlreadySwitched) == 0 || len(state.ShardsNotYetSwitched) > 0 {
+			directions.Forward = allTabletTypes
+		}
+		if canReverse && len(state.ShardsAlreadySwitched) > 0 {
+			directions.Backward = allTabletTypes
+		}
+		return directions, nil
+	}
+
+	if len(state.ReplicaCellsNotSwitched) > 0 {
+		directions.Forward = append(directions.Forward, topodatapb.TabletType_REPLICA)
+	}
+	if len(state.RdonlyCellsNotSwitched) > 0 {
+		directions.Forward = append(directions.Forward, topodatapb.TabletType_RDONLY)
+	}
+	if !state.WritesSwitched {
+		directions.Forward = append(directions.Forward, topodatapb.TabletType_PRIMARY)
+	}
+	if canReverse {
+		if len(state.ReplicaCellsSwitched) > 0 {
+			directions.Backward = append(directions.Backward, topodatapb.TabletType_REPLICA)
+		}
+		if len(state.RdonlyCellsSwitched) > 0 {
+			directions.Backward = append(directions.Backward, topodatapb.TabletType_RDONLY)
+		}
+		if state.WritesSwitched {
+			directions.Backward = append(directions.Backward, topodatapb.TabletType_PRIMARY)
+		}
+	}
+	return directions, nil
+}
+
 func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, state *State, direction TrafficSwitchDirection,
 	maxAllowedReplLagSecs int64, shards []string) (reason string, err error) {
 	if direction == DirectionForward && state.WritesSwitched ||
@@ -3486,6 +5360,13 @@ func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, state *Stat
 		log.Infof("writes already switched no need to check lag")
 		return "", nil
 	}
+	missingShards, err := ts.missingServingTargetShards(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(missingShards) > 0 {
+		return fmt.Sprintf(cannotSwitchMissingShardStreams, strings.Join(missingShards, ",")), nil
+	}
 	wf, err := s.GetWorkflow(ctx, state.TargetKeyspace, state.Workflow, false, shards)
 	if err != nil {
 		return "", err
@@ -3513,12 +5394,12 @@ func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, state *Stat
 	refreshErrors := strings.Builder{}
 	var m sync.Mutex
 	var wg sync.WaitGroup
-	rtbsCtx, cancel := context.WithTimeout(ctx, shardTabletRefreshTimeout)
+	rtbsCtx, cancel := context.WithTimeout(ctx, s.refreshTimeout())
 	defer cancel()
 	refreshTablets := func(shards []*topo.ShardInfo, stype string) {
 		defer wg.Done()
 		for _, si := range shards {
-			if partial, partialDetails, err := topotools.RefreshTabletsByShard(rtbsCtx, s.ts, s.tmc, si, nil, ts.Logger()); err != nil || partial {
+			if partial, partialDetails, err := topotools.RefreshTabletsByShard(rtbsCtx, s.ts, s.tmClient(), si, nil, ts.Logger()); err != nil || partial {
 				m.Lock()
 				refreshErrors.WriteString(fmt.Sprintf("failed to successfully refresh all tablets in the %s/%s %s shard (%v):\n  %v\n",
 					si.Keyspace(), si.ShardName(), stype, err, partialDetails))
@@ -3543,14 +5424,175 @@ func (s *Server) VReplicationExec(ctx context.Context, tabletAlias *topodatapb.T
 	if err != nil {
 		return nil, err
 	}
-	return s.tmc.VReplicationExec(ctx, ti.Tablet, query)
+	return s.tmClient().VReplicationExec(ctx, ti.Tablet, query)
+}
+
+// ValidateWorkflowSchemas compares the schema of each table in the workflow
+// between a source primary tablet and a target primary tablet, so that any
+// drift between them can be caught before switching traffic. It returns the
+// schema differences keyed by table name, only including the tables that
+// have differences.
+func (s *Server) ValidateWorkflowSchemas(ctx context.Context, keyspace, workflow string) (map[string][]string, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ValidateWorkflowSchemas")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	// Use the primaries of the first source and target shards, sorted by
+	// name so that the same tablets are used on each call.
+	sourceShards := maps.Keys(ts.Sources())
+	slices.Sort(sourceShards)
+	targetShards := maps.Keys(ts.Targets())
+	slices.Sort(targetShards)
+	if len(sourceShards) == 0 || len(targetShards) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no source or target shards found for workflow %s.%s", keyspace, workflow)
+	}
+	sourcePrimary := ts.Sources()[sourceShards[0]].GetPrimary()
+	targetPrimary := ts.Targets()[targetShards[0]].GetPrimary()
+	if sourcePrimary == nil || targetPrimary == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet found for source shard %s or target shard %s",
+			sourceShards[0], targetShards[0])
+	}
+
+	var (
+		mu    sync.Mutex
+		diffs = make(map[string][]string)
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(topo.DefaultConcurrency)
+	for _, table := range ts.Tables() {
+		eg.Go(func() error {
+			tableDiffs, err := schematools.CompareSchemas(egCtx, s.ts, s.tmClient(), sourcePrimary.Alias, targetPrimary.Alias,
+				[]string{table}, nil, false)
+			if err != nil {
+				return vterrors.Wrapf(err, "failed to compare the schema for table %s", table)
+			}
+			if len(tableDiffs) > 0 {
+				mu.Lock()
+				defer mu.Unlock()
+				diffs[table] = tableDiffs
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// GetWorkflowSequences returns the sequences used by the workflow's tables
+// in the target keyspace, including where each backing sequence table
+// lives, so that they can be checked before switching writes.
+func (s *Server) GetWorkflowSequences(ctx context.Context, keyspace, workflow string) ([]*WorkflowSequence, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetWorkflowSequences")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	sequenceMetadata, err := ts.getTargetSequenceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return workflowSequences(sequenceMetadata), nil
+}
+
+// PreviewTargetSequenceValues returns the next value that each of the backing
+// sequence tables used by the workflow's tables would be initialized to when
+// switching writes with initialize_target_sequences, keyed by the backing
+// table name. This is one more than the max value currently used in the
+// using table across the target shards. Nothing is changed on the tablets,
+// and a backing table whose next value is already higher is left as is when
+// the sequences are initialized.
+func (s *Server) PreviewTargetSequenceValues(ctx context.Context, keyspace, workflow string) (map[string]int64, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.PreviewTargetSequenceValues")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	ts, err := s.buildTrafficSwitcher(ctx, keyspace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	sequenceMetadata, err := ts.getTargetSequenceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m      sync.Mutex
+		values = make(map[string]int64, len(sequenceMetadata))
+	)
+	eg, ectx := errgroup.WithContext(ctx)
+	for backingTable, sm := range sequenceMetadata {
+		eg.Go(func() error {
+			maxID, err := ts.getMaxUsedSequenceValue(ectx, sm)
+			if err != nil {
+				return err
+			}
+			m.Lock()
+			defer m.Unlock()
+			values[backingTable] = maxID + 1
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ExportWorkflowDefinition returns the definition of a Materialize workflow
+// as MaterializeSettings, which can be saved and later passed to
+// ImportWorkflowDefinition to recreate the workflow, e.g. in another
+// environment.
+func (s *Server) ExportWorkflowDefinition(ctx context.Context, keyspace, workflow string) (*vtctldatapb.MaterializeSettings, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ExportWorkflowDefinition")
+	defer span.Finish()
+
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("workflow", workflow)
+
+	wf, err := s.GetWorkflow(ctx, keyspace, workflow, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	return workflowDefinition(s.env.Parser(), wf)
+}
+
+// ImportWorkflowDefinition creates a workflow from a definition returned by
+// ExportWorkflowDefinition.
+func (s *Server) ImportWorkflowDefinition(ctx context.Context, ms *vtctldatapb.MaterializeSettings) error {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.ImportWorkflowDefinition")
+	defer span.Finish()
+
+	span.Annotate("keyspace", ms.GetTargetKeyspace())
+	span.Annotate("workflow", ms.GetWorkflow())
+
+	if ms.GetMaterializationIntent() != vtctldatapb.MaterializationIntent_CUSTOM {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only Materialize workflow definitions can be imported")
+	}
+	return s.Materialize(ctx, ms)
 }
 
 // CopySchemaShard copies the schema from a source tablet to the
 // specified shard.  The schema is applied directly on the primary of
 // the destination shard, and is propagated to the replicas through
-// binlogs.
-func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify bool) error {
+// binlogs. If allowSchemaDiff is set then any differences found when
+// verifying the copied schema are logged as warnings rather than returned
+// as an error.
+func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitReplicasTimeout time.Duration, skipVerify, allowSchemaDiff bool) error {
 	destShardInfo, err := s.ts.GetShard(ctx, destKeyspace, destShard)
 	if err != nil {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "GetShard(%v, %v) failed: %v", destKeyspace, destShard, err)
@@ -3560,7 +5602,7 @@ func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodat
 		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary in shard record %v/%v. Consider running 'vtctl InitShardPrimary' in case of a new shard or reparenting the shard to fix the topology data", destKeyspace, destShard)
 	}
 
-	diffs, err := schematools.CompareSchemas(ctx, s.ts, s.tmc, sourceTabletAlias, destShardInfo.PrimaryAlias, tables, excludeTables, includeViews)
+	diffs, err := schematools.CompareSchemas(ctx, s.ts, s.tmClient(), sourceTabletAlias, destShardInfo.PrimaryAlias, tables, excludeTables, includeViews)
 	if err != nil {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "CopySchemaShard failed because schemas could not be compared initially: %v", err)
 	}
@@ -3570,7 +5612,7 @@ func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodat
 	}
 
 	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: tables, ExcludeTables: excludeTables, IncludeViews: includeViews}
-	sourceSd, err := schematools.GetSchema(ctx, s.ts, s.tmc, sourceTabletAlias, req)
+	sourceSd, err := schematools.GetSchema(ctx, s.ts, s.tmClient(), sourceTabletAlias, req)
 	if err != nil {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "GetSchema(%v, %v, %v, %v) failed: %v", sourceTabletAlias, tables, excludeTables, includeViews, err)
 	}
@@ -3581,7 +5623,7 @@ func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodat
 	if err != nil {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "GetTablet(%v) failed: %v", destShardInfo.PrimaryAlias, err)
 	}
-	for _, createSQL := range createSQLstmts {
+	for i, createSQL := range createSQLstmts {
 		err = s.applySQLShard(ctx, destTabletInfo, createSQL)
 		if err != nil {
 			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "creating a table failed."+
@@ -3589,10 +5631,13 @@ func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodat
 				" Please remove all to be copied tables from the destination manually and run this command again."+
 				" Full error: %v", err)
 		}
+		if applied := i + 1; s.copySchemaProgressInterval > 0 && (applied%s.copySchemaProgressInterval == 0 || applied == len(createSQLstmts)) {
+			s.Logger().Infof("CopySchemaShard: applied %d of %d statements to %s/%s", applied, len(createSQLstmts), destKeyspace, destShard)
+		}
 	}
 
 	// Remember the replication position after all the above were applied.
-	destPrimaryPos, err := s.tmc.PrimaryPosition(ctx, destTabletInfo.Tablet)
+	destPrimaryPos, err := s.tmClient().PrimaryPosition(ctx, destTabletInfo.Tablet)
 	if err != nil {
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "CopySchemaShard: can't get replication position after schema applied: %v", err)
 	}
@@ -3603,19 +5648,26 @@ func (s *Server) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodat
 	// In that case, MySQL would have skipped our CREATE DATABASE IF NOT EXISTS
 	// statement.
 	if !skipVerify {
-		diffs, err = schematools.CompareSchemas(ctx, s.ts, s.tmc, sourceTabletAlias, destShardInfo.PrimaryAlias, tables, excludeTables, includeViews)
+		diffs, err = schematools.CompareSchemas(ctx, s.ts, s.tmClient(), sourceTabletAlias, destShardInfo.PrimaryAlias, tables, excludeTables, includeViews)
 		if err != nil {
 			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "CopySchemaShard failed because schemas could not be compared finally: %v", err)
 		}
 		if diffs != nil {
-			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "CopySchemaShard was not successful because the schemas between the two tablets %v and %v differ: %v", sourceTabletAlias, destShardInfo.PrimaryAlias, diffs)
+			if !allowSchemaDiff {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "CopySchemaShard was not successful because the schemas between the two tablets %v and %v differ: %v", sourceTabletAlias, destShardInfo.PrimaryAlias, diffs)
+			}
+			s.Logger().Warningf("CopySchemaShard: the schemas between the two tablets %v and %v differ in %d object(s), continuing anyway",
+				topoproto.TabletAliasString(sourceTabletAlias), topoproto.TabletAliasString(destShardInfo.PrimaryAlias), len(diffs))
+			for _, diff := range diffs {
+				s.Logger().Warningf("CopySchemaShard: %s", diff)
+			}
 		}
 	}
 
 	// Notify Replicas to reload schema. This is best-effort.
 	reloadCtx, cancel := context.WithTimeout(ctx, waitReplicasTimeout)
 	defer cancel()
-	_, ok := schematools.ReloadShard(reloadCtx, s.ts, s.tmc, logutil.NewMemoryLogger(), destKeyspace, destShard, destPrimaryPos, nil, true)
+	_, ok := schematools.ReloadShard(reloadCtx, s.ts, s.tmClient(), logutil.NewMemoryLogger(), destKeyspace, destShard, destPrimaryPos, s.copySchemaReloadSemaphore(), true)
 	if !ok {
 		log.Error(vterrors.Errorf(vtrpcpb.Code_INTERNAL, "CopySchemaShard: failed to reload schema on all replicas"))
 	}
@@ -3639,7 +5691,7 @@ func (s *Server) applySQLShard(ctx context.Context, tabletInfo *topo.TabletInfo,
 	defer cancel()
 	// Need to make sure that replication is enabled since we're only applying
 	// the statement on primaries.
-	_, err = s.tmc.ApplySchema(ctx, tabletInfo.Tablet, &tmutils.SchemaChange{
+	_, err = s.tmClient().ApplySchema(ctx, tabletInfo.Tablet, &tmutils.SchemaChange{
 		SQL:              filledChange,
 		Force:            false,
 		AllowReplication: true,
@@ -3850,7 +5902,7 @@ func (s *Server) prepareCreateLookup(ctx context.Context, workflow, keyspace str
 		return nil, nil, nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "source shard %s has no primary", onesource.ShardName())
 	}
 	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: []string{sourceTableName}}
-	tableSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, onesource.PrimaryAlias, req)
+	tableSchema, err := schematools.GetSchema(ctx, s.ts, s.tmClient(), onesource.PrimaryAlias, req)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -4045,6 +6097,25 @@ func (s *Server) MigrateCreate(ctx context.Context, req *vtctldatapb.MigrateCrea
 	return s.moveTablesCreate(ctx, moveTablesCreateRequest, binlogdatapb.VReplicationWorkflowType_Migrate)
 }
 
+// validateTenantIdNotInUse returns an error if any existing workflow in the
+// target keyspace is already migrating the given tenant, as running more than
+// one workflow for the same tenant leads to conflicting data.
+func (s *Server) validateTenantIdNotInUse(ctx context.Context, targetKeyspace, tenantId string) error {
+	resp, err := s.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+		Keyspace: targetKeyspace,
+	})
+	if err != nil {
+		return err
+	}
+	for _, wf := range resp.GetWorkflows() {
+		if wf.GetOptions().GetTenantId() == tenantId {
+			return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "tenant %s is already being migrated to keyspace %s by workflow %s",
+				tenantId, targetKeyspace, wf.GetName())
+		}
+	}
+	return nil
+}
+
 // getWorkflowStatus gets the overall status of the workflow by checking the status of all the streams. If all streams are not
 // in the same state, it returns the unknown state.
 func (s *Server) getWorkflowStatus(ctx context.Context, keyspace string, workflow string) (binlogdatapb.VReplicationWorkflowState, error) {
@@ -18,30 +18,42 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/encoding/prototext"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/textutil"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 type fakeTMC struct {
@@ -126,6 +138,17 @@ func TestCheckReshardingJournalExistsOnTablet(t *testing.T) {
 			}, string(tabletBytes))),
 			shouldErr: true,
 		},
+		{
+			name:   "duplicate journal rows",
+			tablet: tablet,
+			result: sqltypes.ResultToProto3(sqltypes.MakeTestResult([]*querypb.Field{
+				{
+					Name: "val",
+					Type: querypb.Type_BLOB,
+				},
+			}, string(journalBytes), string(journalBytes))),
+			shouldErr: true,
+		},
 		{
 			name: "VReplicationExec fails on tablet",
 			tablet: &topodatapb.Tablet{ // Here we use a different tablet to force the fake to return an error
@@ -145,7 +168,7 @@ func TestCheckReshardingJournalExistsOnTablet(t *testing.T) {
 			tmc := &fakeTMC{
 				vrepQueriesByTablet: map[string]map[string]*querypb.QueryResult{
 					topoproto.TabletAliasString(tablet.Alias): { // always use the tablet shared by these tests cases
-						"select val from _vt.resharding_journal where id=1": tt.result,
+						"select val from _vt.resharding_journal where id=1 limit 2": tt.result,
 					},
 				},
 			}
@@ -174,6 +197,247 @@ func TestCheckReshardingJournalExistsOnTablet(t *testing.T) {
 
 // TestVDiffCreate performs some basic tests of the VDiffCreate function
 // to ensure that it behaves as expected given a specific request.
+func TestWithTMCFactory(t *testing.T) {
+	ctx := context.Background()
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "zone1",
+			Uid:  100,
+		},
+	}
+	tmc := &fakeTMC{
+		vrepQueriesByTablet: map[string]map[string]*querypb.QueryResult{
+			topoproto.TabletAliasString(tablet.Alias): {
+				"select val from _vt.resharding_journal where id=1 limit 2": &querypb.QueryResult{},
+			},
+		},
+	}
+	calls := 0
+	factory := func() tmclient.TabletManagerClient {
+		calls++
+		return tmc
+	}
+	// The client passed in directly must not be used when a factory is given.
+	ws := NewServer(vtenv.NewTestEnv(), nil, &fakeTMC{}, WithTMCFactory(factory))
+
+	_, exists, err := ws.CheckReshardingJournalExistsOnTablet(ctx, tablet, 1)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Equal(t, 1, calls)
+}
+
+func TestWithKeyspaceDefaultTimeouts(t *testing.T) {
+	timeouts := map[string]time.Duration{"ks1": 2 * time.Minute}
+	s := NewServer(vtenv.NewTestEnv(), nil, nil, WithKeyspaceDefaultTimeouts(timeouts))
+	// Later changes to the caller's map must not affect the Server.
+	timeouts["ks2"] = time.Second
+
+	require.Equal(t, 2*time.Minute, s.defaultTimeout("ks1"))
+	require.Equal(t, defaultDuration, s.defaultTimeout("ks2"))
+	require.Equal(t, defaultDuration, NewServer(vtenv.NewTestEnv(), nil, nil).defaultTimeout("ks1"))
+}
+
+func TestWithTabletRefreshTimeout(t *testing.T) {
+	require.Equal(t, shardTabletRefreshTimeout, NewServer(vtenv.NewTestEnv(), nil, nil).refreshTimeout())
+	s := NewServer(vtenv.NewTestEnv(), nil, nil, WithTabletRefreshTimeout(2*time.Minute))
+	require.Equal(t, 2*time.Minute, s.refreshTimeout())
+	ts := &trafficSwitcher{ws: s}
+	require.Equal(t, 2*time.Minute, ts.refreshTimeout())
+}
+
+func TestWithCopySchemaReloadConcurrency(t *testing.T) {
+	require.Nil(t, NewServer(vtenv.NewTestEnv(), nil, nil).copySchemaReloadSemaphore())
+	s := NewServer(vtenv.NewTestEnv(), nil, nil, WithCopySchemaReloadConcurrency(2))
+	sem := s.copySchemaReloadSemaphore()
+	require.NotNil(t, sem)
+	require.True(t, sem.TryAcquire(2))
+	require.False(t, sem.TryAcquire(1))
+}
+
+func TestWithCopyThroughputSampleInterval(t *testing.T) {
+	require.Equal(t, defaultCopyThroughputSampleInterval, NewServer(vtenv.NewTestEnv(), nil, nil).throughputSampleInterval())
+	s := NewServer(vtenv.NewTestEnv(), nil, nil, WithCopyThroughputSampleInterval(time.Second))
+	require.Equal(t, time.Second, s.throughputSampleInterval())
+}
+
+func TestWithLogger(t *testing.T) {
+	s := NewServer(vtenv.NewTestEnv(), nil, nil)
+	require.NotNil(t, s.Logger())
+
+	// Without the option, actions use the Server's logger.
+	options := s.processWorkflowActionOptions(nil)
+	require.Equal(t, s.Logger(), options.logger)
+
+	// With it, they use the logger they were given.
+	logger := logutil.NewMemoryLogger()
+	options = s.processWorkflowActionOptions([]WorkflowActionOption{WithLogger(logger)})
+	require.Equal(t, logger, options.logger)
+	options.logger.Infof("captured")
+	require.Contains(t, logger.String(), "captured")
+}
+
+// TestCopySchemaShardSchemaDiff confirms that differences which remain after
+// the schema is copied fail the copy unless they are allowed, in which case
+// they are logged as warnings with the Server's logger.
+func TestCopySchemaShardSchemaDiff(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	sourceSchema := "CREATE TABLE `t1` (\n  `id` bigint NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+	targetSchema := "CREATE TABLE `t1` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB"
+	schema := func(createTable string) *tabletmanagerdatapb.SchemaDefinition {
+		return &tabletmanagerdatapb.SchemaDefinition{
+			DatabaseSchema: "CREATE DATABASE IF NOT EXISTS {{.DatabaseName}}",
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{Name: "t1", Schema: createTable, Columns: []string{"id"}, PrimaryKeyColumns: []string{"id"}, Type: tmutils.TableBaseTable},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		allowSchemaDiff bool
+		wantErr         string
+	}{
+		{
+			name:    "schema diff rejected",
+			wantErr: "CopySchemaShard was not successful because the schemas between the two tablets",
+		},
+		{
+			name:            "schema diff allowed",
+			allowSchemaDiff: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			logger := logutil.NewMemoryLogger()
+			env.ws.logger = logger
+
+			// The target keeps its own version of the table, as
+			// if it already existed there, so the copy can't fix
+			// the difference.
+			env.tmc.tabletSchemas[startingSourceTabletUID] = schema(sourceSchema)
+			env.tmc.tabletSchemas[startingTargetTabletUID] = schema(targetSchema)
+			env.tmc.expectVRQuery(startingTargetTabletUID, "/CREATE DATABASE IF NOT EXISTS", &sqltypes.Result{})
+			env.tmc.expectVRQuery(startingTargetTabletUID, "/CREATE TABLE `vt_targetks`.`t1`", &sqltypes.Result{})
+
+			err := env.ws.CopySchemaShard(ctx, env.tablets[sourceKeyspace.KeyspaceName][startingSourceTabletUID].Alias, []string{"/.*/"}, nil, false,
+				targetKeyspace.KeyspaceName, "0", time.Second, false, tc.allowSchemaDiff)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				require.NotContains(t, logger.String(), "continuing anyway")
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, logger.String(), fmt.Sprintf("CopySchemaShard: the schemas between the two tablets %s-%010d and %s-%010d differ in 1 object(s), continuing anyway",
+				defaultCellName, startingSourceTabletUID, defaultCellName, startingTargetTabletUID))
+			require.Contains(t, logger.String(), "CopySchemaShard: schemas differ on table t1")
+		})
+	}
+}
+
+// TestCopySchemaShardProgress confirms that CopySchemaShard logs its progress
+// every WithCopySchemaProgressInterval statements, and once all of them have
+// been applied.
+func TestCopySchemaShardProgress(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	tableNames := []string{"t1", "t2", "t3", "t4", "t5"}
+	sourceSchema := &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "CREATE DATABASE IF NOT EXISTS {{.DatabaseName}}",
+	}
+	for _, tableName := range tableNames {
+		sourceSchema.TableDefinitions = append(sourceSchema.TableDefinitions, &tabletmanagerdatapb.TableDefinition{
+			Name:              tableName,
+			Schema:            fmt.Sprintf("CREATE TABLE `%s` (\n  `id` bigint NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB", tableName),
+			Columns:           []string{"id"},
+			PrimaryKeyColumns: []string{"id"},
+			Type:              tmutils.TableBaseTable,
+		})
+	}
+	// The database and each of the tables is created with its own statement.
+	statements := len(tableNames) + 1
+
+	testCases := []struct {
+		name     string
+		interval int
+		want     []int // The number of applied statements in each progress message.
+	}{
+		{
+			name:     "every other statement",
+			interval: 2,
+			want:     []int{2, 4, 6},
+		},
+		{
+			name:     "the last statement is always logged",
+			interval: 4,
+			want:     []int{4, 6},
+		},
+		{
+			name:     "interval larger than the number of statements",
+			interval: 10,
+			want:     []int{6},
+		},
+		{
+			name: "disabled",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			logger := logutil.NewMemoryLogger()
+			env.ws = NewServer(vtenv.NewTestEnv(), env.ts, env.tmc, WithCopySchemaProgressInterval(tc.interval))
+			env.ws.logger = logger
+
+			env.tmc.tabletSchemas[startingSourceTabletUID] = sourceSchema
+			env.tmc.tabletSchemas[startingTargetTabletUID] = &tabletmanagerdatapb.SchemaDefinition{}
+			env.tmc.expectVRQuery(startingTargetTabletUID, "/CREATE DATABASE IF NOT EXISTS", &sqltypes.Result{})
+			for range tableNames {
+				env.tmc.expectVRQuery(startingTargetTabletUID, "/CREATE TABLE `vt_targetks`", &sqltypes.Result{})
+			}
+
+			err := env.ws.CopySchemaShard(ctx, env.tablets[sourceKeyspace.KeyspaceName][startingSourceTabletUID].Alias, []string{"/.*/"}, nil, false,
+				targetKeyspace.KeyspaceName, "0", time.Second, true, false)
+			require.NoError(t, err)
+			env.tmc.mu.Lock()
+			require.Empty(t, env.tmc.vrQueries[startingTargetTabletUID])
+			env.tmc.mu.Unlock()
+
+			var got []string
+			for _, event := range logger.Events {
+				if strings.HasPrefix(event.Value, "CopySchemaShard: applied") {
+					got = append(got, event.Value)
+				}
+			}
+			var want []string
+			for _, applied := range tc.want {
+				want = append(want, fmt.Sprintf("CopySchemaShard: applied %d of %d statements to %s/0", applied, statements, targetKeyspace.KeyspaceName))
+			}
+			require.Equal(t, want, got)
+		})
+	}
+}
+
 func TestVDiffCreate(t *testing.T) {
 	ctx := context.Background()
 	ts := memorytopo.NewServer(ctx, "cell")
@@ -319,83 +583,2222 @@ func TestWorkflowDelete(t *testing.T) {
 					result: &querypb.QueryResult{},
 				},
 			},
-			want: &vtctldatapb.WorkflowDeleteResponse{
-				Summary: fmt.Sprintf("Successfully cancelled the %s workflow in the %s keyspace",
-					workflowName, targetKeyspaceName),
-				Details: []*vtctldatapb.WorkflowDeleteResponse_TabletInfo{
+			want: &vtctldatapb.WorkflowDeleteResponse{
+				Summary: fmt.Sprintf("Successfully cancelled the %s workflow in the %s keyspace",
+					workflowName, targetKeyspaceName),
+				Details: []*vtctldatapb.WorkflowDeleteResponse_TabletInfo{
+					{
+						Tablet:  &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID},
+						Deleted: true,
+					},
+					{
+						Tablet:  &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID + tabletUIDStep},
+						Deleted: true,
+					},
+				},
+			},
+			postFunc: func(t *testing.T, env *testEnv) {
+				for _, shard := range env.targetKeyspace.ShardNames {
+					si, err := env.ts.GetShard(ctx, targetKeyspaceName, shard)
+					require.NoError(t, err)
+					require.NotNil(t, si)
+					tc := si.GetTabletControl(topodatapb.TabletType_PRIMARY)
+					require.NotNil(t, tc)
+					require.EqualValues(t, []string{"t2", "t3"}, tc.DeniedTables)
+				}
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NotNil(t, tc.sourceKeyspace)
+			require.NotNil(t, tc.targetKeyspace)
+			require.NotNil(t, tc.req)
+			env := newTestEnv(t, ctx, defaultCellName, tc.sourceKeyspace, tc.targetKeyspace)
+			defer env.close()
+			env.tmc.schema = schema
+			if tc.expectedSourceQueries != nil {
+				require.NotNil(t, env.tablets[tc.sourceKeyspace.KeyspaceName])
+				for _, eq := range tc.expectedSourceQueries {
+					env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.sourceKeyspace.KeyspaceName, eq)
+				}
+			}
+			if tc.expectedTargetQueries != nil {
+				require.NotNil(t, env.tablets[tc.targetKeyspace.KeyspaceName])
+				for _, eq := range tc.expectedTargetQueries {
+					env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.targetKeyspace.KeyspaceName, eq)
+				}
+			}
+			if tc.preFunc != nil {
+				tc.preFunc(t, env)
+			}
+			got, err := env.ws.WorkflowDelete(ctx, tc.req)
+			if (err != nil) != tc.wantErr {
+				require.Fail(t, "unexpected error value", "Server.WorkflowDelete() error = %v, wantErr %v", err, tc.wantErr)
+				return
+			}
+			require.EqualValues(t, got, tc.want, "Server.WorkflowDelete() = %v, want %v", got, tc.want)
+			if tc.postFunc != nil {
+				tc.postFunc(t, env)
+			} else { // Default post checks
+				// Confirm that we have no routing rules.
+				rr, err := env.ts.GetRoutingRules(ctx)
+				require.NoError(t, err)
+				require.Zero(t, rr.Rules)
+
+				// Confirm that we have no shard tablet controls, which is where
+				// DeniedTables live.
+				for _, keyspace := range []*testKeyspace{tc.sourceKeyspace, tc.targetKeyspace} {
+					for _, shardName := range keyspace.ShardNames {
+						si, err := env.ts.GetShard(ctx, keyspace.KeyspaceName, shardName)
+						require.NoError(t, err)
+						require.Zero(t, si.Shard.TabletControls)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestWorkflowDeleteKeepVdiffData confirms that the workflow's vdiff data is
+// only deleted when it's not being kept.
+func TestWorkflowDeleteKeepVdiffData(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+
+	for _, keepVdiffData := range []bool{true, false} {
+		t.Run(fmt.Sprintf("keep vdiff data %t", keepVdiffData), func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema[tableName] = &tabletmanagerdatapb.SchemaDefinition{
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+					{
+						Name:   tableName,
+						Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+					},
+				},
+			}
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+				query: fmt.Sprintf("delete from _vt.vreplication where db_name = 'vt_%s' and workflow = '%s'",
+					sourceKeyspace.KeyspaceName, ReverseWorkflowName(workflowName)),
+				result: &querypb.QueryResult{},
+			})
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+				query:  fmt.Sprintf("drop table `vt_%s`.`%s`", targetKeyspace.KeyspaceName, tableName),
+				result: &querypb.QueryResult{},
+			})
+
+			_, err := env.ws.WorkflowDelete(ctx, &vtctldatapb.WorkflowDeleteRequest{
+				Keyspace:      targetKeyspace.KeyspaceName,
+				Workflow:      workflowName,
+				KeepVdiffData: keepVdiffData,
+			})
+			require.NoError(t, err)
+
+			env.tmc.mu.Lock()
+			defer env.tmc.mu.Unlock()
+			vdiffRequests := env.tmc.vdiffRequests[startingTargetTabletUID]
+			if keepVdiffData {
+				require.Empty(t, vdiffRequests)
+				return
+			}
+			require.Len(t, vdiffRequests, 1)
+			require.Equal(t, workflowName, vdiffRequests[0].GetWorkflow())
+			require.Equal(t, "delete", vdiffRequests[0].GetAction())
+		})
+	}
+}
+
+// TestDropSourcesPreDropSourceSQL confirms that the pre-drop SQL is run on
+// the source primaries before the source tables are dropped, and that errors
+// from it only stop the tables from being dropped when they're not ignored.
+func TestDropSourcesPreDropSourceSQL(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	preDropSQL := []string{"update t1 set archived = 1", "delete from t1_history"}
+	preDropErr := fmt.Errorf("unknown column 'archived'")
+
+	testcases := []struct {
+		name         string
+		ignoreErrors bool
+		preDropErr   error
+		wantErr      string
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:       "error",
+			preDropErr: preDropErr,
+			wantErr:    fmt.Sprintf("failed to execute pre-drop SQL %q on tablet %s-0000000100: %v", preDropSQL[0], defaultCellName, preDropErr),
+		},
+		{
+			name:         "ignored error",
+			ignoreErrors: true,
+			preDropErr:   preDropErr,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema[tableName] = &tabletmanagerdatapb.SchemaDefinition{
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+					{
+						Name:   tableName,
+						Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+					},
+				},
+			}
+			ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+				query:  preDropSQL[0],
+				result: &querypb.QueryResult{},
+				err:    tc.preDropErr,
+			})
+			if tc.wantErr == "" {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+					query:  preDropSQL[1],
+					result: &querypb.QueryResult{},
+				})
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+					query:  fmt.Sprintf("drop table `vt_%s`.`%s`", sourceKeyspace.KeyspaceName, tableName),
+					result: &querypb.QueryResult{},
+				})
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+					query: fmt.Sprintf("delete from _vt.vreplication where db_name = 'vt_%s' and workflow = '%s'",
+						sourceKeyspace.KeyspaceName, ReverseWorkflowName(workflowName)),
+					result: &querypb.QueryResult{},
+				})
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+					query: fmt.Sprintf("delete from _vt.vreplication where db_name = 'vt_%s' and workflow = '%s'",
+						targetKeyspace.KeyspaceName, workflowName),
+					result: &querypb.QueryResult{},
+				})
+			}
+
+			_, err = env.ws.dropSources(ctx, ts, DropTable, false, false, false, true, false, preDropSQL, tc.ignoreErrors)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			// All of the expected queries were run, and no more.
+			env.tmc.mu.Lock()
+			defer env.tmc.mu.Unlock()
+			require.Empty(t, env.tmc.vrQueries[startingSourceTabletUID])
+			require.Empty(t, env.tmc.vrQueries[startingTargetTabletUID])
+		})
+	}
+}
+
+func TestWorkflowUpdateStateTransition(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	newRequest := func(state binlogdatapb.VReplicationWorkflowState) *vtctldatapb.WorkflowUpdateRequest {
+		return &vtctldatapb.WorkflowUpdateRequest{
+			Keyspace: targetKeyspace.KeyspaceName,
+			TabletRequest: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+				Workflow:    workflowName,
+				Cells:       textutil.SimulatedNullStringSlice,
+				TabletTypes: []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+				OnDdl:       binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+				State:       state,
+			},
+		}
+	}
+
+	// Each state change reads the workflow's current state first.
+	expectReadState := func() {
+		env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+			query:  "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)",
+			result: &querypb.QueryResult{},
+		})
+	}
+
+	// The test workflow's streams are all running, so starting it is a no-op.
+	expectReadState()
+	_, err := env.ws.WorkflowUpdate(ctx, newRequest(binlogdatapb.VReplicationWorkflowState_Running))
+	require.ErrorContains(t, err, "already in the Running state")
+
+	// Stopping it is allowed.
+	expectReadState()
+	res, err := env.ws.WorkflowUpdate(ctx, newRequest(binlogdatapb.VReplicationWorkflowState_Stopped))
+	require.NoError(t, err)
+	require.Len(t, res.Details, 1)
+
+	// Starting it while also changing another field is allowed.
+	req := newRequest(binlogdatapb.VReplicationWorkflowState_Running)
+	req.TabletRequest.OnDdl = binlogdatapb.OnDDLAction_EXEC
+	expectReadState()
+	_, err = env.ws.WorkflowUpdate(ctx, req)
+	require.NoError(t, err)
+
+	// Not changing the state is always allowed.
+	_, err = env.ws.WorkflowUpdate(ctx, newRequest(binlogdatapb.VReplicationWorkflowState(textutil.SimulatedNullInt)))
+	require.NoError(t, err)
+}
+
+func TestValidateRoutingRuleFlags(t *testing.T) {
+	tenantOptions := &vtctldatapb.WorkflowOptions{TenantId: "1"}
+	testCases := []struct {
+		name    string
+		req     *vtctldatapb.MoveTablesCreateRequest
+		wantErr string
+	}{
+		{
+			name: "no routing rules",
+			req:  &vtctldatapb.MoveTablesCreateRequest{NoRoutingRules: true},
+		},
+		{
+			name: "partial",
+			req:  &vtctldatapb.MoveTablesCreateRequest{SourceShards: []string{"-80"}},
+		},
+		{
+			name: "multi-tenant",
+			req:  &vtctldatapb.MoveTablesCreateRequest{WorkflowOptions: tenantOptions},
+		},
+		{
+			name:    "multi-tenant with no routing rules",
+			req:     &vtctldatapb.MoveTablesCreateRequest{WorkflowOptions: tenantOptions, NoRoutingRules: true},
+			wantErr: "cannot use --no-routing-rules in a multi-tenant migration",
+		},
+		{
+			name:    "partial multi-tenant",
+			req:     &vtctldatapb.MoveTablesCreateRequest{WorkflowOptions: tenantOptions, SourceShards: []string{"-80"}},
+			wantErr: "cannot run partial shard migration along with multi-tenant migration",
+		},
+	}
+	ws := &Server{}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ws.validateRoutingRuleFlags(tc.req)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestMoveTablesCompleteKeepSourceVschemaWithRoutingRules confirms that we
+// refuse to keep the routing rules along with the source keyspace vschema.
+func TestMoveTablesCompleteKeepSourceVschemaWithRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	ws := &Server{}
+	_, err := ws.MoveTablesComplete(ctx, &vtctldatapb.MoveTablesCompleteRequest{
+		TargetKeyspace:    "targetks",
+		Workflow:          "wf1",
+		KeepSourceVschema: true,
+		KeepRoutingRules:  true,
+	})
+	require.EqualError(t, err, "cannot keep the routing rules when keeping the source keyspace vschema for the wf1 workflow")
+	require.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(err))
+}
+
+// TestMoveTablesCreateWorkflowAlreadyExists confirms that callers can tell
+// when MoveTablesCreate failed because the workflow already exists.
+func TestMoveTablesCreateWorkflowAlreadyExists(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID+tabletUIDStep] = &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+		Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			{Workflow: workflowName},
+		},
+	}
+
+	_, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+		Workflow:       workflowName,
+		SourceKeyspace: sourceKeyspace.KeyspaceName,
+		TargetKeyspace: targetKeyspace.KeyspaceName,
+		AllTables:      true,
+	})
+	require.ErrorIs(t, err, ErrWorkflowAlreadyExists)
+	require.ErrorContains(t, err, fmt.Sprintf("workflow %s already exists in keyspace %s", workflowName, targetKeyspace.KeyspaceName))
+}
+
+// TestMoveTablesCreateTablesWithoutPrimaryKey confirms that tables without a
+// primary key on the source are skipped or rejected as requested.
+func TestMoveTablesCreateAllTablesExcluded(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{
+			{
+				TargetTable:      "t1",
+				SourceExpression: "select * from t1",
+			},
+			{
+				TargetTable:      "t2",
+				SourceExpression: "select * from t2",
+			},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		allTables     bool
+		includeTables []string
+		excludeTables []string
+		wantErr       string
+	}{
+		{
+			name:          "all tables",
+			allTables:     true,
+			excludeTables: []string{"t2", "t1"},
+			wantErr:       "all 2 candidate tables were excluded from the workflow, excluded tables: t1,t2",
+		},
+		{
+			name:          "included tables",
+			includeTables: []string{"t1"},
+			excludeTables: []string{"t1"},
+			wantErr:       "all 1 candidate tables were excluded from the workflow, excluded tables: t1",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+			defer env.close()
+
+			_, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+				Workflow:       ms.Workflow,
+				SourceKeyspace: ms.SourceKeyspace,
+				TargetKeyspace: ms.TargetKeyspace,
+				AllTables:      tc.allTables,
+				IncludeTables:  tc.includeTables,
+				ExcludeTables:  tc.excludeTables,
+			})
+			require.EqualError(t, err, tc.wantErr)
+			require.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(err))
+		})
+	}
+}
+
+func TestMoveTablesCreateForceIgnorePreviousJournal(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{
+			{
+				TargetTable:      "t1",
+				SourceExpression: "select * from t1",
+			},
+		},
+	}
+	journal := sqltypes.MakeTestResult(sqltypes.MakeTestFields("val", "varbinary"), "migration_type:TABLES")
+
+	testcases := []struct {
+		name    string
+		force   bool
+		journal *sqltypes.Result
+		wantErr string
+	}{
+		{
+			name:    "no journal",
+			journal: &sqltypes.Result{},
+		},
+		{
+			name:    "journal",
+			journal: journal,
+			wantErr: "found an entry from a previous run for migration id",
+		},
+		{
+			name:    "journal ignored",
+			force:   true,
+			journal: journal,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+			defer env.close()
+			env.tmc.expectVRQuery(100, mzCheckJournal, tc.journal)
+			if tc.wantErr == "" {
+				env.tmc.expectVRQuery(200, mzGetCopyState, &sqltypes.Result{})
+				env.tmc.expectVRQuery(200, mzGetLatestCopyState, &sqltypes.Result{})
+			}
+
+			_, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+				Workflow:                   ms.Workflow,
+				SourceKeyspace:             ms.SourceKeyspace,
+				TargetKeyspace:             ms.TargetKeyspace,
+				IncludeTables:              []string{"t1"},
+				ForceIgnorePreviousJournal: tc.force,
+			})
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMoveTablesCreateTablesWithoutPrimaryKey(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{
+			{
+				TargetTable:      "t1",
+				SourceExpression: "select * from t1",
+			},
+			{
+				TargetTable:      "t2",
+				SourceExpression: "select * from t2",
+			},
+		},
+	}
+
+	testcases := []struct {
+		name       string
+		skip, fail bool
+		noPKTables []string
+		wantTables []string
+		wantErr    string
+	}{
+		{
+			name:       "skip",
+			skip:       true,
+			noPKTables: []string{"t2"},
+			wantTables: []string{"t1"},
+		},
+		{
+			name:       "skip with no tables without a primary key",
+			skip:       true,
+			wantTables: []string{"t1", "t2"},
+		},
+		{
+			name:       "skip every table",
+			skip:       true,
+			noPKTables: []string{"t1", "t2"},
+			wantErr:    "no tables to move",
+		},
+		{
+			name:       "fail",
+			fail:       true,
+			noPKTables: []string{"t2", "t1"},
+			wantErr:    "tables without a primary key found in the sourceks keyspace: t1,t2",
+		},
+		{
+			name:       "fail with no tables without a primary key",
+			fail:       true,
+			wantTables: []string{"t1", "t2"},
+		},
+		{
+			name:    "skip and fail",
+			skip:    true,
+			fail:    true,
+			wantErr: "cannot both skip and fail on tables without a primary key",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+			defer env.close()
+			for _, table := range []string{"t1", "t2"} {
+				if !slices.Contains(tc.noPKTables, table) {
+					env.tmc.schema[ms.SourceKeyspace+"."+table].TableDefinitions[0].PrimaryKeyColumns = []string{"id"}
+				}
+			}
+			if tc.wantErr == "" {
+				env.tmc.expectVRQuery(100, mzCheckJournal, &sqltypes.Result{})
+				env.tmc.expectVRQuery(200, mzGetCopyState, &sqltypes.Result{})
+				env.tmc.expectVRQuery(200, mzGetLatestCopyState, &sqltypes.Result{})
+			}
+
+			_, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+				Workflow:                      ms.Workflow,
+				SourceKeyspace:                ms.SourceKeyspace,
+				TargetKeyspace:                ms.TargetKeyspace,
+				AllTables:                     true,
+				SkipTablesWithoutPrimaryKey:   tc.skip,
+				FailOnTablesWithoutPrimaryKey: tc.fail,
+			})
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			vschema, err := env.ws.ts.GetVSchema(ctx, ms.TargetKeyspace)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.wantTables, maps.Keys(vschema.Tables))
+		})
+	}
+}
+
+func TestWorkflowStopStartStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	// The target shard has one stream for each source shard, and only the
+	// requested one is updated.
+	expectUpdate := func(state binlogdatapb.VReplicationWorkflowState, streamID int32) {
+		env.tmc.mu.Lock()
+		defer env.tmc.mu.Unlock()
+		env.tmc.updateVReplicationWorkflowRequests[startingTargetTabletUID] = &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+			Workflow:                  workflowName,
+			Cells:                     textutil.SimulatedNullStringSlice,
+			TabletTypes:               []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN,
+			OnDdl:                     binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+			State:                     state,
+			IncludeIds:                []int32{streamID},
+		}
+	}
+
+	expectUpdate(binlogdatapb.VReplicationWorkflowState_Stopped, 2)
+	require.NoError(t, env.ws.WorkflowStopStream(ctx, targetKeyspace.KeyspaceName, workflowName, "0", 2))
+
+	expectUpdate(binlogdatapb.VReplicationWorkflowState_Running, 2)
+	require.NoError(t, env.ws.WorkflowStartStream(ctx, targetKeyspace.KeyspaceName, workflowName, "0", 2))
+
+	err := env.ws.WorkflowStopStream(ctx, targetKeyspace.KeyspaceName, workflowName, "0", 3)
+	require.EqualError(t, err, "stream 3 of the wf1 workflow does not exist on shard targetks/0")
+}
+
+// TestCancelWorkflowCreateNoStreams confirms that CancelWorkflowCreate is a
+// no-op when the workflow's streams no longer exist.
+func TestCancelWorkflowCreateNoStreams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	for i := range targetKeyspace.ShardNames {
+		env.tmc.readVReplicationWorkflowResponses[uint32(startingTargetTabletUID+(i*tabletUIDStep))] = &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			Workflow:     workflowName,
+			WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+		}
+	}
+
+	err := env.ws.CancelWorkflowCreate(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+}
+
+func TestValidateWorkflowSchemas(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	schema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   tableName,
+				Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+			},
+		},
+	}
+	driftedSchema := &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   tableName,
+				Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(128), PRIMARY KEY (id))", tableName),
+			},
+		},
+	}
+
+	testcases := []struct {
+		name         string
+		targetSchema *tabletmanagerdatapb.SchemaDefinition
+		wantDiff     bool
+	}{
+		{
+			name:         "same schema",
+			targetSchema: schema,
+		},
+		{
+			name:         "schema diff",
+			targetSchema: driftedSchema,
+			wantDiff:     true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema[tableName] = schema
+			env.tmc.tabletSchemas[startingSourceTabletUID] = schema
+			// The first target shard, sorted by name, is compared.
+			env.tmc.tabletSchemas[startingTargetTabletUID] = tc.targetSchema
+
+			diffs, err := env.ws.ValidateWorkflowSchemas(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+			if !tc.wantDiff {
+				require.Empty(t, diffs)
+				return
+			}
+			require.Len(t, diffs, 1)
+			require.NotEmpty(t, diffs[tableName])
+			require.Contains(t, strings.Join(diffs[tableName], "\n"), tableName)
+		})
+	}
+}
+
+func TestClearTargetDeniedTables(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+
+	// Simulate a failed create that left the target denied tables behind.
+	lockCtx, unlock, err := env.ts.LockKeyspace(ctx, targetKeyspace.KeyspaceName, "test")
+	require.NoError(t, err)
+	for _, shard := range targetKeyspace.ShardNames {
+		_, err := env.ts.UpdateShardFields(lockCtx, targetKeyspace.KeyspaceName, shard, func(si *topo.ShardInfo) error {
+			return si.UpdateDeniedTables(lockCtx, topodatapb.TabletType_PRIMARY, nil, false, []string{tableName, "t2"})
+		})
+		require.NoError(t, err)
+	}
+	unlock(&err)
+	require.NoError(t, err)
+
+	err = env.ws.ClearTargetDeniedTables(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+
+	// Only the workflow's tables are removed from the denied tables.
+	for _, shard := range targetKeyspace.ShardNames {
+		si, err := env.ts.GetShard(ctx, targetKeyspace.KeyspaceName, shard)
+		require.NoError(t, err)
+		tc := si.GetTabletControl(topodatapb.TabletType_PRIMARY)
+		require.NotNil(t, tc)
+		require.Equal(t, []string{"t2"}, tc.DeniedTables)
+	}
+}
+
+func TestGetCopyStateRows(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)"
+	env.tmc.expectVRQuery(startingTargetTabletUID+tabletUIDStep, copyStateQuery, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"vrepl_id|table_name|lastpk",
+			"int64|varchar|varbinary",
+		),
+		"1|t1|fields:{name:\"id\" type:INT64} rows:{lengths:2 values:\"42\"}",
+	))
+
+	copyStates, err := env.ws.GetCopyStateRows(ctx, targetKeyspace.KeyspaceName, workflowName, "80-", 1)
+	require.NoError(t, err)
+	require.Len(t, copyStates, 1)
+	require.Equal(t, int64(1), copyStates[0].StreamId)
+	require.Equal(t, "t1", copyStates[0].Table)
+	require.Contains(t, copyStates[0].LastPk, "42")
+
+	_, err = env.ws.GetCopyStateRows(ctx, targetKeyspace.KeyspaceName, workflowName, "80-", 2)
+	require.ErrorContains(t, err, "stream 2 of workflow wf1 does not exist on shard targetks/80-")
+
+	_, err = env.ws.GetCopyStateRows(ctx, targetKeyspace.KeyspaceName, workflowName, "-40", 1)
+	require.Error(t, err)
+}
+
+func TestValidateWorkflowVSchema(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = make(map[string]*tabletmanagerdatapb.SchemaDefinition)
+	for _, tableName := range []string{"t1", "t2", "t3"} {
+		env.tmc.schema[tableName] = &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		}
+	}
+
+	err := env.ts.SaveVSchema(ctx, targetKeyspace.KeyspaceName, &vschemapb.Keyspace{
+		Tables: map[string]*vschemapb.Table{
+			"t1": {},
+			"t2": {},
+			"t3": {},
+		},
+	})
+	require.NoError(t, err)
+	discrepancies, err := env.ws.ValidateWorkflowVSchema(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Empty(t, discrepancies)
+
+	err = env.ts.SaveVSchema(ctx, targetKeyspace.KeyspaceName, &vschemapb.Keyspace{
+		Tables: map[string]*vschemapb.Table{
+			"t1": {},
+			"t3": {
+				ColumnVindexes: []*vschemapb.ColumnVindex{
+					{
+						Name:   "xxhash",
+						Column: "id",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	discrepancies, err = env.ws.ValidateWorkflowVSchema(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"table t2 has no entry in the vschema for keyspace targetks",
+		"table t3 has column vindexes in the vschema for unsharded keyspace targetks",
+	}, discrepancies)
+}
+
+func TestWorkflowRename(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	newWorkflowName := "wf2"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+
+	err := env.ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, "")
+	require.ErrorContains(t, err, "a new name must be provided")
+	err = env.ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, workflowName)
+	require.ErrorContains(t, err, "is already named")
+	err = env.ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, ReverseWorkflowName(newWorkflowName))
+	require.ErrorContains(t, err, "reserved for reverse workflows")
+
+	// The new name is already used by another workflow in the keyspace.
+	env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID] = &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+		Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			{Workflow: newWorkflowName},
+		},
+	}
+	err = env.ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, newWorkflowName)
+	require.ErrorIs(t, err, ErrWorkflowAlreadyExists)
+	delete(env.tmc.readVReplicationWorkflowsResponses, startingTargetTabletUID)
+
+	// All of the workflow's streams are renamed on each target primary.
+	for i := range targetKeyspace.ShardNames {
+		env.tmc.updateVReplicationWorkflowRequests[uint32(startingTargetTabletUID+(i*tabletUIDStep))] = &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+			Workflow:                  workflowName,
+			Cells:                     textutil.SimulatedNullStringSlice,
+			TabletTypes:               []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+			TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN,
+			OnDdl:                     binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+			State:                     binlogdatapb.VReplicationWorkflowState(textutil.SimulatedNullInt),
+			NewWorkflowName:           newWorkflowName,
+		}
+	}
+	err = env.ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, newWorkflowName)
+	require.NoError(t, err)
+
+	// Once writes have been switched the workflow can no longer be renamed.
+	err = env.ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{
+			{FromTable: tableName, ToTables: []string{fmt.Sprintf("%s.%s", targetKeyspace.KeyspaceName, tableName)}},
+		},
+	})
+	require.NoError(t, err)
+	err = env.ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, newWorkflowName)
+	require.ErrorContains(t, err, "writes have been switched")
+}
+
+// renameTrackingTMClient records the workflow renames done on each tablet,
+// fails the renames on the tablets in failRenames, and records whether the
+// target keyspace was locked when the workflows were read.
+type renameTrackingTMClient struct {
+	*testTMClient
+	keyspace    string
+	failRenames map[uint32]bool
+
+	mu            sync.Mutex
+	renames       []string
+	unlockedReads int
+}
+
+func (tmc *renameTrackingTMClient) ReadVReplicationWorkflows(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ReadVReplicationWorkflowsRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse, error) {
+	if err := topo.CheckKeyspaceLocked(ctx, tmc.keyspace); err != nil {
+		tmc.mu.Lock()
+		tmc.unlockedReads++
+		tmc.mu.Unlock()
+	}
+	return tmc.testTMClient.ReadVReplicationWorkflows(ctx, tablet, req)
+}
+
+func (tmc *renameTrackingTMClient) UpdateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateVReplicationWorkflowRequest) (*tabletmanagerdatapb.UpdateVReplicationWorkflowResponse, error) {
+	tmc.mu.Lock()
+	defer tmc.mu.Unlock()
+	tmc.renames = append(tmc.renames, fmt.Sprintf("%d:%s->%s", tablet.Alias.Uid, req.Workflow, req.NewWorkflowName))
+	if tmc.failRenames[tablet.Alias.Uid] {
+		return nil, fmt.Errorf("rename failed on tablet %d", tablet.Alias.Uid)
+	}
+	return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{Result: &querypb.QueryResult{RowsAffected: 1}}, nil
+}
+
+// TestWorkflowRenameFailure confirms that the new name is checked with the
+// target keyspace locked, and that the shards that were renamed are renamed
+// back, or reported, when the rename fails on another shard.
+func TestWorkflowRenameFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	newWorkflowName := "wf2"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	lowTablet := uint32(startingTargetTabletUID)
+	highTablet := uint32(startingTargetTabletUID + tabletUIDStep)
+
+	testCases := []struct {
+		name        string
+		failRenames map[uint32]bool
+		wantRenames []string
+		wantErr     string
+	}{
+		{
+			name: "renamed",
+			wantRenames: []string{
+				fmt.Sprintf("%d:%s->%s", lowTablet, workflowName, newWorkflowName),
+				fmt.Sprintf("%d:%s->%s", highTablet, workflowName, newWorkflowName),
+			},
+		},
+		{
+			name:        "renamed shard is renamed back",
+			failRenames: map[uint32]bool{highTablet: true},
+			wantRenames: []string{
+				fmt.Sprintf("%d:%s->%s", lowTablet, workflowName, newWorkflowName),
+				fmt.Sprintf("%d:%s->%s", highTablet, workflowName, newWorkflowName),
+				fmt.Sprintf("%d:%s->%s", lowTablet, newWorkflowName, workflowName),
+			},
+			wantErr: fmt.Sprintf("failed to rename the %s workflow on shard 80-: rename failed on tablet %d", workflowName, highTablet),
+		},
+		{
+			name:        "no shard is renamed",
+			failRenames: map[uint32]bool{lowTablet: true, highTablet: true},
+			wantRenames: []string{
+				fmt.Sprintf("%d:%s->%s", lowTablet, workflowName, newWorkflowName),
+				fmt.Sprintf("%d:%s->%s", highTablet, workflowName, newWorkflowName),
+			},
+			wantErr: "rename failed on tablet",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				tableName: {
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+						},
+					},
+				},
+			}
+			tmc := &renameTrackingTMClient{
+				testTMClient: env.tmc,
+				keyspace:     targetKeyspace.KeyspaceName,
+				failRenames:  tc.failRenames,
+			}
+			ws := NewServer(vtenv.NewTestEnv(), env.ts, env.tmc, WithTMCFactory(func() tmclient.TabletManagerClient { return tmc }))
+
+			err := ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, newWorkflowName)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+			require.Zero(t, tmc.unlockedReads, "the new workflow name was checked without the target keyspace lock")
+			// The shards are renamed concurrently, so only the renames
+			// back are ordered.
+			require.ElementsMatch(t, tc.wantRenames[:2], tmc.renames[:2])
+			require.Equal(t, tc.wantRenames[2:], tmc.renames[2:])
+		})
+	}
+
+	// The shards that could not be renamed back are reported.
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+	tmc := &revertFailingTMClient{renameTrackingTMClient: &renameTrackingTMClient{
+		testTMClient: env.tmc,
+		keyspace:     targetKeyspace.KeyspaceName,
+		failRenames:  map[uint32]bool{highTablet: true},
+	}}
+	ws := NewServer(vtenv.NewTestEnv(), env.ts, env.tmc, WithTMCFactory(func() tmclient.TabletManagerClient { return tmc }))
+	err := ws.WorkflowRename(ctx, targetKeyspace.KeyspaceName, workflowName, newWorkflowName)
+	require.ErrorContains(t, err, fmt.Sprintf("the workflow is still named %s on shards -80", newWorkflowName))
+	require.ErrorContains(t, err, "failed to rename the wf1 workflow on shard 80-")
+}
+
+// revertFailingTMClient also fails the renames back to the original name.
+type revertFailingTMClient struct {
+	*renameTrackingTMClient
+}
+
+func (tmc *revertFailingTMClient) UpdateVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateVReplicationWorkflowRequest) (*tabletmanagerdatapb.UpdateVReplicationWorkflowResponse, error) {
+	if req.NewWorkflowName == "wf1" {
+		return nil, fmt.Errorf("rename back failed on tablet %d", tablet.Alias.Uid)
+	}
+	return tmc.renameTrackingTMClient.UpdateVReplicationWorkflow(ctx, tablet, req)
+}
+
+func TestGetSwitchableDirections(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+	allTabletTypes := []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY, topodatapb.TabletType_PRIMARY}
+
+	// Nothing has been switched yet so everything can only be switched forward.
+	directions, err := env.ws.GetSwitchableDirections(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Equal(t, allTabletTypes, directions.Forward)
+	require.Empty(t, directions.Backward)
+
+	// Once all traffic has been switched it can only be switched back.
+	env.tmc.reverse.Store(true)
+	env.addTableRoutingRules(t, ctx, allTabletTypes, []string{tableName})
+	directions, err = env.ws.GetSwitchableDirections(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Empty(t, directions.Forward)
+	require.Equal(t, allTabletTypes, directions.Backward)
+}
+
+func TestGetSourceDeniedTables(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+
+	denied, err := env.ws.GetSourceDeniedTables(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Empty(t, denied)
+
+	// Deny the workflow's table, along with an unrelated one, on only one of
+	// the source shards.
+	lockCtx, unlock, err := env.ts.LockKeyspace(ctx, sourceKeyspace.KeyspaceName, "test")
+	require.NoError(t, err)
+	_, err = env.ts.UpdateShardFields(lockCtx, sourceKeyspace.KeyspaceName, "80-", func(si *topo.ShardInfo) error {
+		return si.UpdateDeniedTables(lockCtx, topodatapb.TabletType_PRIMARY, nil, false, []string{"t2", tableName})
+	})
+	require.NoError(t, err)
+	unlock(&err)
+	require.NoError(t, err)
+
+	denied, err = env.ws.GetSourceDeniedTables(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[topodatapb.TabletType][]string{
+		"80-": {
+			topodatapb.TabletType_PRIMARY: {tableName, "t2"},
+		},
+	}, denied)
+}
+
+func TestGetReverseWorkflowStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)"
+	// The forward workflow is read from the target keyspace to find its
+	// source keyspace, and the reverse workflow is then read from there.
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query:  copyStateQuery,
+		result: &querypb.QueryResult{},
+	})
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+		query:  "select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = 1",
+		result: &querypb.QueryResult{},
+	})
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+		query:  copyStateQuery,
+		result: &querypb.QueryResult{},
+	})
+
+	resp, err := env.ws.GetReverseWorkflowStatus(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	// The reverse workflow's streams run on the source keyspace's tablets.
+	require.Len(t, resp.ShardStreams, 1)
+	require.Contains(t, resp.ShardStreams, fmt.Sprintf("%s/0", sourceKeyspace.KeyspaceName))
+}
+
+// TestWorkflowStatusUnknownShards confirms that we return an error naming
+// any requested shards which do not exist in the keyspace.
+func TestWorkflowStatusUnknownShards(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	_, err := env.ws.WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+		Keyspace: targetKeyspace.KeyspaceName,
+		Workflow: "wf1",
+		Shards:   []string{"-80", "-40", "80-c0"},
+	})
+	require.EqualError(t, err, "unknown shard(s) -40, 80-c0 in keyspace targetks, the shards are: -80, 80-")
+}
+
+func TestValidateTenantIdNotInUse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	// An existing workflow on the target keyspace is migrating tenant 1.
+	env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID] = &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+		Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			{
+				Workflow:     "tenant1",
+				WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+				Options:      `{"tenant_id": "1"}`,
+				Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+					{
+						Id:    1,
+						State: binlogdatapb.VReplicationWorkflowState_Running,
+						Bls: &binlogdatapb.BinlogSource{
+							Keyspace: sourceKeyspace.KeyspaceName,
+							Shard:    "0",
+						},
+						Pos:           "MySQL56/" + position,
+						TimeUpdated:   protoutil.TimeToProto(time.Now()),
+						TimeHeartbeat: protoutil.TimeToProto(time.Now()),
+					},
+				},
+			},
+		},
+	}
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)"
+	for range 2 {
+		env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+			query:  copyStateQuery,
+			result: &querypb.QueryResult{},
+		})
+	}
+
+	err := env.ws.validateTenantIdNotInUse(ctx, targetKeyspace.KeyspaceName, "1")
+	require.EqualError(t, err, "tenant 1 is already being migrated to keyspace targetks by workflow tenant1")
+	require.NoError(t, env.ws.validateTenantIdNotInUse(ctx, targetKeyspace.KeyspaceName, "2"))
+}
+
+// TestListFrozenWorkflows confirms that we only return the workflows that
+// have a frozen stream.
+func TestListFrozenWorkflows(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	workflow := func(name string, id int32, message string) *tabletmanagerdatapb.ReadVReplicationWorkflowResponse {
+		return &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			Workflow:     name,
+			WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+			Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+				{
+					Id:    id,
+					State: binlogdatapb.VReplicationWorkflowState_Stopped,
+					Bls: &binlogdatapb.BinlogSource{
+						Keyspace: sourceKeyspace.KeyspaceName,
+						Shard:    "0",
+					},
+					Pos:           "MySQL56/" + position,
+					TimeUpdated:   protoutil.TimeToProto(time.Now()),
+					TimeHeartbeat: protoutil.TimeToProto(time.Now()),
+					Message:       message,
+				},
+			},
+		}
+	}
+	env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID] = &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+		Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			workflow("wf2", 1, Frozen),
+			workflow("wf1", 2, ""),
+			workflow("wf3", 3, Frozen),
+		},
+	}
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1, 2, 3) and id in (select max(id) from _vt.copy_state where vrepl_id in (1, 2, 3) group by vrepl_id, table_name)"
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query:  copyStateQuery,
+		result: &querypb.QueryResult{},
+	})
+
+	frozen, err := env.ws.ListFrozenWorkflows(ctx, targetKeyspace.KeyspaceName)
+	require.NoError(t, err)
+	var names []string
+	for _, wf := range frozen {
+		names = append(names, wf.GetName())
+	}
+	require.Equal(t, []string{"wf2", "wf3"}, names)
+}
+
+// TestGetWorkflowsCellsAndTabletTypes confirms that the cells and tablet
+// types that the workflow's streams use for source tablet selection are
+// combined across the target shards.
+func TestGetWorkflowsCellsAndTabletTypes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	workflow := func(cells string, tabletTypes ...topodatapb.TabletType) *tabletmanagerdatapb.ReadVReplicationWorkflowsResponse {
+		return &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+			Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+				{
+					Workflow:                  "wf1",
+					WorkflowType:              binlogdatapb.VReplicationWorkflowType_MoveTables,
+					Cells:                     cells,
+					TabletTypes:               tabletTypes,
+					TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+					Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+						{
+							Id:    1,
+							State: binlogdatapb.VReplicationWorkflowState_Running,
+							Bls: &binlogdatapb.BinlogSource{
+								Keyspace: sourceKeyspace.KeyspaceName,
+								Shard:    "0",
+							},
+							Pos:           "MySQL56/" + position,
+							TimeUpdated:   protoutil.TimeToProto(time.Now()),
+							TimeHeartbeat: protoutil.TimeToProto(time.Now()),
+						},
+					},
+				},
+			},
+		}
+	}
+	env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID] = workflow("zone2, zone1", topodatapb.TabletType_REPLICA)
+	env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID+tabletUIDStep] = workflow("zone3,zone1,",
+		topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY)
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)"
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query:  copyStateQuery,
+		result: &querypb.QueryResult{},
+	})
+
+	resp, err := env.ws.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+		Keyspace: targetKeyspace.KeyspaceName,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.GetWorkflows(), 1)
+	wf := resp.GetWorkflows()[0]
+	require.Equal(t, []string{"zone1", "zone2", "zone3"}, wf.GetCells())
+	require.ElementsMatch(t, []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}, wf.GetTabletTypes())
+	require.Equal(t, tabletmanagerdatapb.TabletSelectionPreference_INORDER, wf.GetTabletSelectionPreference())
+}
+
+// readWorkflowCountingTMClient counts the ReadVReplicationWorkflow calls,
+// failing them with err if it is set.
+type readWorkflowCountingTMClient struct {
+	*testTMClient
+	reads atomic.Int64
+	err   error
+}
+
+func (tmc *readWorkflowCountingTMClient) ReadVReplicationWorkflow(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ReadVReplicationWorkflowRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowResponse, error) {
+	tmc.reads.Add(1)
+	if tmc.err != nil {
+		return nil, tmc.err
+	}
+	return tmc.testTMClient.ReadVReplicationWorkflow(ctx, tablet, req)
+}
+
+// TestGetWorkflowsReversible confirms that a workflow is only reported as
+// reversible once writes have been switched and the reverse workflow exists,
+// that the reverse workflow is only read when requested, and that an error
+// reading it is returned rather than reported as the workflow not being
+// reversible.
+func TestGetWorkflowsReversible(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+
+	workflow := func(name string, id int32, message string) *tabletmanagerdatapb.ReadVReplicationWorkflowResponse {
+		return &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+			Workflow:     name,
+			WorkflowType: binlogdatapb.VReplicationWorkflowType_MoveTables,
+			Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
+				{
+					Id:    id,
+					State: binlogdatapb.VReplicationWorkflowState_Running,
+					Bls: &binlogdatapb.BinlogSource{
+						Keyspace: sourceKeyspace.KeyspaceName,
+						Shard:    "0",
+					},
+					Pos:           "MySQL56/" + position,
+					TimeUpdated:   protoutil.TimeToProto(time.Now()),
+					TimeHeartbeat: protoutil.TimeToProto(time.Now()),
+					Message:       message,
+				},
+			},
+		}
+	}
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1, 2) and id in (select max(id) from _vt.copy_state where vrepl_id in (1, 2) group by vrepl_id, table_name)"
+
+	testcases := []struct {
+		name              string
+		includeReversible bool
+		readErr           error
+		wantReversible    map[string]bool
+		wantReads         int64
+		wantErr           string
+	}{
+		{
+			name:           "not requested",
+			wantReversible: map[string]bool{"running": false, "switched": false},
+		},
+		{
+			name:              "requested",
+			includeReversible: true,
+			wantReversible:    map[string]bool{"running": false, "switched": true},
+			// Only the switched workflow's reverse workflow is read.
+			wantReads: 1,
+		},
+		{
+			name:              "read error",
+			includeReversible: true,
+			readErr:           errors.New("tablet unreachable"),
+			wantReads:         1,
+			wantErr:           "failed to check if the switched workflow is reversible: failed to read the reverse workflow switched_reverse on tablet cell-0000000100: tablet unreachable",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			tmc := &readWorkflowCountingTMClient{testTMClient: env.tmc, err: tc.readErr}
+			ws := NewServer(vtenv.NewTestEnv(), env.ts, env.tmc, WithTMCFactory(func() tmclient.TabletManagerClient { return tmc }))
+
+			env.tmc.readVReplicationWorkflowsResponses[startingTargetTabletUID] = &tabletmanagerdatapb.ReadVReplicationWorkflowsResponse{
+				Workflows: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+					workflow("running", 1, ""),
+					workflow("switched", 2, Frozen),
+				},
+			}
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+				query:  copyStateQuery,
+				result: &querypb.QueryResult{},
+			})
+
+			resp, err := ws.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+				Keyspace:          targetKeyspace.KeyspaceName,
+				IncludeReversible: tc.includeReversible,
+			})
+			require.Equal(t, tc.wantReads, tmc.reads.Load())
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			reversible := make(map[string]bool, len(resp.GetWorkflows()))
+			for _, wf := range resp.GetWorkflows() {
+				reversible[wf.GetName()] = wf.GetReversible()
+			}
+			require.Equal(t, tc.wantReversible, reversible)
+		})
+	}
+}
+
+func TestGetVReplicationTableSizes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	query := "select table_name, data_length + index_length from information_schema.tables where table_schema = '_vt' and table_name in ('copy_state', 'vreplication', 'vreplication_log', 'vdiff', 'vdiff_log', 'vdiff_table')"
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query: query,
+		result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields(
+			"table_name|size",
+			"varchar|int64"),
+			"copy_state|16384",
+			"vreplication|32768",
+		)),
+	})
+
+	sizes, err := env.ws.GetVReplicationTableSizes(ctx, targetKeyspace.KeyspaceName, nil)
+	require.NoError(t, err)
+	want := make(map[string]map[string]int64)
+	for _, tablet := range env.tablets[targetKeyspace.KeyspaceName] {
+		want[topoproto.TabletAliasString(tablet.Alias)] = map[string]int64{
+			"copy_state":   16384,
+			"vreplication": 32768,
+		}
+	}
+	require.Len(t, want, 2)
+	require.Equal(t, want, sizes)
+
+	_, err = env.ws.GetVReplicationTableSizes(ctx, targetKeyspace.KeyspaceName, []string{"-40"})
+	require.EqualError(t, err, "shard -40 not found in keyspace targetks")
+}
+
+func TestPreviewTargetSequenceValues(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+	err := env.ts.SaveVSchema(ctx, sourceKeyspace.KeyspaceName, &vschemapb.Keyspace{
+		Tables: map[string]*vschemapb.Table{
+			"t1_seq": {
+				Type: "sequence",
+			},
+		},
+	})
+	require.NoError(t, err)
+	err = env.ts.SaveVSchema(ctx, targetKeyspace.KeyspaceName, &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			tableName: {
+				ColumnVindexes: []*vschemapb.ColumnVindex{
 					{
-						Tablet:  &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID},
-						Deleted: true,
+						Name:   "xxhash",
+						Column: "id",
 					},
-					{
-						Tablet:  &topodatapb.TabletAlias{Cell: defaultCellName, Uid: startingTargetTabletUID + tabletUIDStep},
-						Deleted: true,
+				},
+				AutoIncrement: &vschemapb.AutoIncrement{
+					Column:   "id",
+					Sequence: "t1_seq",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// The next value is one more than the max used across all target shards.
+	query := fmt.Sprintf("select max(`id`) as maxval from `vt_%s`.`%s`", targetKeyspace.KeyspaceName, tableName)
+	fields := sqltypes.MakeTestFields("maxval", "int64")
+	env.tmc.expectVRQuery(startingTargetTabletUID, query, sqltypes.MakeTestResult(fields, "42"))
+	env.tmc.expectVRQuery(startingTargetTabletUID+tabletUIDStep, query, sqltypes.MakeTestResult(fields, "1000"))
+
+	values, err := env.ws.PreviewTargetSequenceValues(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"t1_seq": 1001}, values)
+}
+
+func TestGetCopyProgressExactRowCounts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	tableMetricsResult := func(rows string) *querypb.QueryResult {
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name|table_rows|data_length", "varchar|int64|int64"), rows))
+	}
+	countResult := func(count string) *querypb.QueryResult {
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), count))
+	}
+
+	for _, exact := range []bool{false, true} {
+		t.Run(fmt.Sprintf("exact=%t", exact), func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				tableName: {
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+						},
 					},
 				},
+			}
+
+			ts, state, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+				query:  "select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = 1",
+				result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name", "varchar"), tableName)),
+			})
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+				query:  fmt.Sprintf("select table_name, table_rows, data_length from information_schema.tables where table_schema = 'vt_%s' and table_name in ('%s')", targetKeyspace.KeyspaceName, tableName),
+				result: tableMetricsResult(tableName + "|5|100"),
+			})
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+				query:  fmt.Sprintf("select table_name, table_rows, data_length from information_schema.tables where table_schema = 'vt_%s' and table_name in ('%s')", sourceKeyspace.KeyspaceName, tableName),
+				result: tableMetricsResult(tableName + "|10|200"),
+			})
+			wantTargetRows, wantSourceRows := int64(5), int64(10)
+			if exact {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+					query:  fmt.Sprintf("select count(*) from `vt_%s`.`%s`", targetKeyspace.KeyspaceName, tableName),
+					result: countResult("7"),
+				})
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+					query:  fmt.Sprintf("select count(*) from `vt_%s`.`%s`", sourceKeyspace.KeyspaceName, tableName),
+					result: countResult("12"),
+				})
+				wantTargetRows, wantSourceRows = 7, 12
+			}
+
+			progress, err := env.ws.GetCopyProgress(ctx, ts, state, exact)
+			require.NoError(t, err)
+			require.NotNil(t, progress)
+			require.Equal(t, &tableCopyProgress{
+				TargetRowCount:  wantTargetRows,
+				TargetTableSize: 100,
+				SourceRowCount:  wantSourceRows,
+				SourceTableSize: 200,
+			}, (*progress)[tableName])
+		})
+	}
+}
+
+// rowsCopiedTMClient reports that each of the workflow's streams has copied
+// rowsPerRead more rows every time the workflow is read.
+type rowsCopiedTMClient struct {
+	*testTMClient
+	rowsPerRead int64
+	rowsCopied  atomic.Int64
+}
+
+func (tmc *rowsCopiedTMClient) ReadVReplicationWorkflows(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.ReadVReplicationWorkflowsRequest) (*tabletmanagerdatapb.ReadVReplicationWorkflowsResponse, error) {
+	resp, err := tmc.testTMClient.ReadVReplicationWorkflows(ctx, tablet, req)
+	if err != nil {
+		return nil, err
+	}
+	rowsCopied := tmc.rowsCopied.Add(tmc.rowsPerRead)
+	for _, wf := range resp.GetWorkflows() {
+		for _, stream := range wf.GetStreams() {
+			stream.RowsCopied = rowsCopied
+		}
+	}
+	return resp, nil
+}
+
+func TestEstimateWorkflowCompletion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	tableMetricsResult := func(rows string) *querypb.QueryResult {
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name|table_rows|data_length", "varchar|int64|int64"), rows))
+	}
+	copyStateQuery := "select vrepl_id, table_name, lastpk from _vt.copy_state where vrepl_id in (1) and id in (select max(id) from _vt.copy_state where vrepl_id in (1) group by vrepl_id, table_name)"
+
+	testcases := []struct {
+		name        string
+		copying     bool
+		rowsPerRead int64
+		want        *WorkflowCompletionEstimate
+	}{
+		{
+			name: "not copying",
+			want: &WorkflowCompletionEstimate{
+				Unknown:       true,
+				UnknownReason: "workflow is not in the copy phase",
 			},
-			postFunc: func(t *testing.T, env *testEnv) {
-				for _, shard := range env.targetKeyspace.ShardNames {
-					si, err := env.ts.GetShard(ctx, targetKeyspaceName, shard)
-					require.NoError(t, err)
-					require.NotNil(t, si)
-					tc := si.GetTabletControl(topodatapb.TabletType_PRIMARY)
-					require.NotNil(t, tc)
-					require.EqualValues(t, []string{"t2", "t3"}, tc.DeniedTables)
-				}
+		},
+		{
+			name:    "no rows copied",
+			copying: true,
+			want: &WorkflowCompletionEstimate{
+				Unknown:       true,
+				UnknownReason: "no copy throughput sample is available yet",
 			},
 		},
+		{
+			name:        "rows copied",
+			copying:     true,
+			rowsPerRead: 10,
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			require.NotNil(t, tc.sourceKeyspace)
-			require.NotNil(t, tc.targetKeyspace)
-			require.NotNil(t, tc.req)
-			env := newTestEnv(t, ctx, defaultCellName, tc.sourceKeyspace, tc.targetKeyspace)
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
 			defer env.close()
-			env.tmc.schema = schema
-			if tc.expectedSourceQueries != nil {
-				require.NotNil(t, env.tablets[tc.sourceKeyspace.KeyspaceName])
-				for _, eq := range tc.expectedSourceQueries {
-					env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.sourceKeyspace.KeyspaceName, eq)
-				}
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				tableName: {
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+						},
+					},
+				},
 			}
-			if tc.expectedTargetQueries != nil {
-				require.NotNil(t, env.tablets[tc.targetKeyspace.KeyspaceName])
-				for _, eq := range tc.expectedTargetQueries {
-					env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.targetKeyspace.KeyspaceName, eq)
+			tmc := &rowsCopiedTMClient{testTMClient: env.tmc, rowsPerRead: tc.rowsPerRead}
+			ws := NewServer(vtenv.NewTestEnv(), env.ts, env.tmc,
+				WithTMCFactory(func() tmclient.TabletManagerClient { return tmc }),
+				WithCopyThroughputSampleInterval(10*time.Millisecond))
+
+			if !tc.copying {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+					query:  "select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = 1",
+					result: &querypb.QueryResult{},
+				})
+			} else {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+					query:  "select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = 1",
+					result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name", "varchar"), tableName)),
+				})
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+					query:  fmt.Sprintf("select table_name, table_rows, data_length from information_schema.tables where table_schema = 'vt_%s' and table_name in ('%s')", targetKeyspace.KeyspaceName, tableName),
+					result: tableMetricsResult(tableName + "|50|1000"),
+				})
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+					query:  fmt.Sprintf("select table_name, table_rows, data_length from information_schema.tables where table_schema = 'vt_%s' and table_name in ('%s')", sourceKeyspace.KeyspaceName, tableName),
+					result: tableMetricsResult(tableName + "|150|3000"),
+				})
+				// The rows copied are sampled twice.
+				for range 2 {
+					env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+						query:  copyStateQuery,
+						result: &querypb.QueryResult{},
+					})
 				}
 			}
-			if tc.preFunc != nil {
-				tc.preFunc(t, env)
-			}
-			got, err := env.ws.WorkflowDelete(ctx, tc.req)
-			if (err != nil) != tc.wantErr {
-				require.Fail(t, "unexpected error value", "Server.WorkflowDelete() error = %v, wantErr %v", err, tc.wantErr)
-				return
-			}
-			require.EqualValues(t, got, tc.want, "Server.WorkflowDelete() = %v, want %v", got, tc.want)
-			if tc.postFunc != nil {
-				tc.postFunc(t, env)
-			} else { // Default post checks
-				// Confirm that we have no routing rules.
-				rr, err := env.ts.GetRoutingRules(ctx)
-				require.NoError(t, err)
-				require.Zero(t, rr.Rules)
 
-				// Confirm that we have no shard tablet controls, which is where
-				// DeniedTables live.
-				for _, keyspace := range []*testKeyspace{tc.sourceKeyspace, tc.targetKeyspace} {
-					for _, shardName := range keyspace.ShardNames {
-						si, err := env.ts.GetShard(ctx, keyspace.KeyspaceName, shardName)
-						require.NoError(t, err)
-						require.Zero(t, si.Shard.TabletControls)
-					}
-				}
+			estimate, err := ws.EstimateWorkflowCompletion(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+			env.tmc.mu.Lock()
+			require.Empty(t, env.tmc.vrQueries[startingTargetTabletUID])
+			require.Empty(t, env.tmc.vrQueries[startingSourceTabletUID])
+			env.tmc.mu.Unlock()
+			if tc.want != nil {
+				require.Equal(t, tc.want, estimate)
+				return
 			}
+			require.False(t, estimate.Unknown)
+			require.Greater(t, estimate.RowsPerSecond, float64(0))
+			tce := estimate.Tables[tableName]
+			require.NotNil(t, tce)
+			require.Equal(t, int64(100), tce.RowsRemaining)
+			require.Equal(t, int64(2000), tce.BytesRemaining)
+			wantETA := time.Duration(float64(tce.RowsRemaining) / estimate.RowsPerSecond * float64(time.Second))
+			require.Equal(t, wantETA, tce.ETA)
+			require.Equal(t, wantETA, estimate.ETA)
 		})
 	}
 }
 
+// TestGetCopyProgressRowCountExceeded confirms that we retry reading the
+// tables being copied with a larger row limit, rather than failing, when a
+// stream is copying more tables than the default limit.
+func TestGetCopyProgressRowCountExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+	tableMetricsResult := func(rows string) *querypb.QueryResult {
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name|table_rows|data_length", "varchar|int64|int64"), rows))
+	}
+
+	ts, state, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+
+	getTablesQuery := "select distinct table_name from _vt.copy_state cs, _vt.vreplication vr where vr.id = cs.vrepl_id and vr.id = 1"
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query: getTablesQuery,
+		err:   vterrors.Errorf(vtrpcpb.Code_ABORTED, "Row count exceeded %d", copyStateTablesMaxRows),
+	})
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query:  getTablesQuery,
+		result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name", "varchar"), tableName)),
+	})
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query:  fmt.Sprintf("select table_name, table_rows, data_length from information_schema.tables where table_schema = 'vt_%s' and table_name in ('%s')", targetKeyspace.KeyspaceName, tableName),
+		result: tableMetricsResult(tableName + "|5|100"),
+	})
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+		query:  fmt.Sprintf("select table_name, table_rows, data_length from information_schema.tables where table_schema = 'vt_%s' and table_name in ('%s')", sourceKeyspace.KeyspaceName, tableName),
+		result: tableMetricsResult(tableName + "|10|200"),
+	})
+
+	progress, err := env.ws.GetCopyProgress(ctx, ts, state, false)
+	require.NoError(t, err)
+	require.NotNil(t, progress)
+	require.Contains(t, *progress, tableName)
+
+	// Other errors are returned as is.
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, &queryResult{
+		query: getTablesQuery,
+		err:   vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet is not serving"),
+	})
+	_, err = env.ws.GetCopyProgress(ctx, ts, state, false)
+	require.ErrorContains(t, err, "tablet is not serving")
+}
+
+// TestResetWorkflowRoutingRules confirms that we restore the initial table
+// routing rules for a MoveTables workflow, leaving unrelated rules alone.
+func TestResetWorkflowRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+
+	// Only some of the rules for the workflow's table remain, one of them
+	// broken, along with a rule for an unrelated table.
+	err := env.ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{
+			{FromTable: "t1", ToTables: []string{"sourceks.t1"}},
+			{FromTable: "targetks.t1", ToTables: []string{"targetks.t1"}},
+			{FromTable: "t2", ToTables: []string{"otherks.t2"}},
+		},
+	})
+	require.NoError(t, err)
+
+	wantRules := map[string][]string{
+		"t2": {"otherks.t2"},
+	}
+	routeTablesToSource(wantRules, sourceKeyspace.KeyspaceName, targetKeyspace.KeyspaceName, []string{tableName})
+
+	changes, err := env.ws.ResetWorkflowRoutingRules(ctx, targetKeyspace.KeyspaceName, workflowName, true)
+	require.NoError(t, err)
+	require.Contains(t, changes, "- targetks.t1 => targetks.t1")
+	require.Contains(t, changes, "+ targetks.t1 => sourceks.t1")
+	require.Contains(t, changes, "+ sourceks.t1@replica => sourceks.t1")
+	require.NotContains(t, changes, "+ t1 => sourceks.t1")
+	// Nothing is saved for a dry run.
+	rules, err := topotools.GetRoutingRules(ctx, env.ts)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	_, err = env.ws.ResetWorkflowRoutingRules(ctx, targetKeyspace.KeyspaceName, workflowName, false)
+	require.NoError(t, err)
+	rules, err = topotools.GetRoutingRules(ctx, env.ts)
+	require.NoError(t, err)
+	require.Equal(t, wantRules, rules)
+
+	// Now there is nothing left to change.
+	changes, err = env.ws.ResetWorkflowRoutingRules(ctx, targetKeyspace.KeyspaceName, workflowName, false)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestWorkflowSwitchTrafficCreateReverseWorkflowOnly(t *testing.T) {
+	ws := NewServer(vtenv.NewTestEnv(), nil, nil)
+	_, err := ws.WorkflowSwitchTraffic(context.Background(), &vtctldatapb.WorkflowSwitchTrafficRequest{
+		Keyspace:                  "targetks",
+		Workflow:                  "wf1",
+		EnableReverseReplication:  true,
+		CreateReverseWorkflowOnly: true,
+	})
+	require.ErrorContains(t, err, "create_reverse_workflow_only cannot be used with enable_reverse_replication")
+}
+
+func TestExplainTableRouting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	// Replica reads for orders have been switched to the target keyspace.
+	err := ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{
+			{FromTable: "orders", ToTables: []string{"source.orders"}},
+			{FromTable: "source.orders", ToTables: []string{"source.orders"}},
+			{FromTable: "target.orders", ToTables: []string{"source.orders"}},
+			{FromTable: "source.orders@replica", ToTables: []string{"target.orders"}},
+			{FromTable: "target.orders@replica", ToTables: []string{"target.orders"}},
+		},
+	})
+	require.NoError(t, err)
+	// Only zone1 has the latest routing rules in its SrvVSchema.
+	require.NoError(t, ts.RebuildSrvVSchema(ctx, []string{"zone1"}))
+
+	explanation, err := ws.ExplainTableRouting(ctx, "source", "orders", topodatapb.TabletType_REPLICA, "zone1")
+	require.NoError(t, err)
+	require.Equal(t, RoutingRuleSourceTable, explanation.Source)
+	require.Equal(t, "source.orders@replica -> target.orders", explanation.Rule)
+	require.Equal(t, "target", explanation.TargetKeyspace)
+
+	explanation, err = ws.ExplainTableRouting(ctx, "source", "orders", topodatapb.TabletType_PRIMARY, "")
+	require.NoError(t, err)
+	require.Equal(t, RoutingRuleSourceTable, explanation.Source)
+	require.Equal(t, "source.orders -> source.orders", explanation.Rule)
+	require.Equal(t, "source", explanation.TargetKeyspace)
+
+	// There are no rules in zone2's SrvVSchema yet.
+	_, err = ws.ExplainTableRouting(ctx, "source", "orders", topodatapb.TabletType_REPLICA, "zone2")
+	require.Error(t, err)
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone2", &vschemapb.SrvVSchema{}))
+	explanation, err = ws.ExplainTableRouting(ctx, "source", "orders", topodatapb.TabletType_REPLICA, "zone2")
+	require.NoError(t, err)
+	require.Equal(t, RoutingRuleSourceNone, explanation.Source)
+	require.Equal(t, "source", explanation.TargetKeyspace)
+
+	// Shard routing rules take precedence over table routing rules.
+	err = ts.SaveShardRoutingRules(ctx, &vschemapb.ShardRoutingRules{
+		Rules: []*vschemapb.ShardRoutingRule{
+			{FromKeyspace: "source", ToKeyspace: "target", Shard: "80-"},
+			{FromKeyspace: "source", ToKeyspace: "source", Shard: "-80"},
+		},
+	})
+	require.NoError(t, err)
+	explanation, err = ws.ExplainTableRouting(ctx, "source", "orders", topodatapb.TabletType_REPLICA, "")
+	require.NoError(t, err)
+	require.Equal(t, RoutingRuleSourceShard, explanation.Source)
+	require.Equal(t, "source.-80 -> source, source.80- -> target", explanation.Rule)
+	require.Equal(t, map[string]string{"-80": "source", "80-": "target"}, explanation.ShardTargets)
+
+	// And keyspace routing rules take precedence over both.
+	err = ts.SaveKeyspaceRoutingRules(ctx, &vschemapb.KeyspaceRoutingRules{
+		Rules: []*vschemapb.KeyspaceRoutingRule{
+			{FromKeyspace: "source@replica", ToKeyspace: "target"},
+		},
+	})
+	require.NoError(t, err)
+	explanation, err = ws.ExplainTableRouting(ctx, "source", "orders", topodatapb.TabletType_REPLICA, "")
+	require.NoError(t, err)
+	require.Equal(t, RoutingRuleSourceKeyspace, explanation.Source)
+	require.Equal(t, "source@replica -> target", explanation.Rule)
+	require.Equal(t, "target", explanation.TargetKeyspace)
+}
+
+func TestGetTableReadsSwitchedInCell(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	// Replica reads for orders have been switched to the target keyspace.
+	replicaRule := &vschemapb.RoutingRule{FromTable: "target.orders@replica", ToTables: []string{"target.orders"}}
+	err := ts.SaveRoutingRules(ctx, &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{
+			{FromTable: "target.orders", ToTables: []string{"source.orders"}},
+			{FromTable: "target.orders@rdonly", ToTables: []string{"source.orders"}},
+			replicaRule,
+		},
+	})
+	require.NoError(t, err)
+	// Only zone1 has the latest routing rules in its SrvVSchema.
+	require.NoError(t, ts.RebuildSrvVSchema(ctx, []string{"zone1"}))
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone2", &vschemapb.SrvVSchema{}))
+
+	switched, rule, err := ws.GetTableReadsSwitchedInCell(ctx, "target", "orders", topodatapb.TabletType_REPLICA, "zone1")
+	require.NoError(t, err)
+	require.True(t, switched)
+	utils.MustMatch(t, replicaRule, rule)
+
+	switched, rule, err = ws.GetTableReadsSwitchedInCell(ctx, "target", "orders", topodatapb.TabletType_RDONLY, "zone1")
+	require.NoError(t, err)
+	require.False(t, switched)
+	require.Equal(t, []string{"source.orders"}, rule.ToTables)
+
+	switched, rule, err = ws.GetTableReadsSwitchedInCell(ctx, "target", "orders", topodatapb.TabletType_REPLICA, "zone2")
+	require.NoError(t, err)
+	require.False(t, switched)
+	require.Nil(t, rule)
+
+	// The per-cell views agree with GetCellsWithTableReadsSwitched.
+	cellsSwitched, cellsNotSwitched, err := ws.GetCellsWithTableReadsSwitched(ctx, "target", "orders", topodatapb.TabletType_REPLICA)
+	require.NoError(t, err)
+	require.Equal(t, []string{"zone1"}, cellsSwitched)
+	require.Equal(t, []string{"zone2"}, cellsNotSwitched)
+
+	_, _, err = ws.GetTableReadsSwitchedInCell(ctx, "target", "orders", topodatapb.TabletType_REPLICA, "zone3")
+	require.ErrorContains(t, err, "failed to get the SrvVSchema in cell zone3")
+}
+
+func TestServerDiffRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	ws := NewServer(vtenv.NewTestEnv(), ts, nil)
+
+	err := topotools.SaveRoutingRules(ctx, ts, map[string][]string{
+		"orders":                {"source.orders"},
+		"target.orders":         {"source.orders"},
+		"target.orders@replica": {"source.orders"},
+		"customer":              {"source.customer"},
+	})
+	require.NoError(t, err)
+	before, err := topotools.GetRoutingRules(ctx, ts)
+	require.NoError(t, err)
+
+	diff, err := ws.DiffRoutingRules(ctx, before)
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+
+	err = topotools.SaveRoutingRules(ctx, ts, map[string][]string{
+		"orders":                {"source.orders"},
+		"target.orders":         {"source.orders"},
+		"target.orders@replica": {"target.orders"},
+		"source.orders@replica": {"target.orders"},
+	})
+	require.NoError(t, err)
+
+	diff, err = ws.DiffRoutingRules(ctx, before)
+	require.NoError(t, err)
+	require.False(t, diff.IsEmpty())
+	require.Equal(t, map[string][]string{"source.orders@replica": {"target.orders"}}, diff.Added)
+	require.Equal(t, map[string][]string{"customer": {"source.customer"}}, diff.Removed)
+	require.Equal(t, map[string][]string{"target.orders@replica": {"target.orders"}}, diff.Changed)
+}
+
 func TestMoveTablesTrafficSwitching(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -505,6 +2908,29 @@ func TestMoveTablesTrafficSwitching(t *testing.T) {
 				CurrentState: "All Reads Switched. Writes Switched",
 			},
 		},
+		{
+			name: "forward with idempotency key",
+			sourceKeyspace: &testKeyspace{
+				KeyspaceName: sourceKeyspaceName,
+				ShardNames:   []string{"0"},
+			},
+			targetKeyspace: &testKeyspace{
+				KeyspaceName: targetKeyspaceName,
+				ShardNames:   []string{"-80", "80-"},
+			},
+			req: &vtctldatapb.WorkflowSwitchTrafficRequest{
+				Keyspace:       targetKeyspaceName,
+				Workflow:       workflowName,
+				Direction:      int32(DirectionForward),
+				TabletTypes:    tabletTypes,
+				IdempotencyKey: "cutover-1",
+			},
+			want: &vtctldatapb.WorkflowSwitchTrafficResponse{
+				Summary:      fmt.Sprintf("SwitchTraffic was successful for workflow %s.%s", targetKeyspaceName, workflowName),
+				StartState:   "Reads Not Switched. Writes Not Switched",
+				CurrentState: "All Reads Switched. Writes Switched",
+			},
+		},
 		{
 			name: "basic backward",
 			sourceKeyspace: &testKeyspace{
@@ -551,6 +2977,13 @@ func TestMoveTablesTrafficSwitching(t *testing.T) {
 				}
 				env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.sourceKeyspace.KeyspaceName, createJournalQR)
 				env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.targetKeyspace.KeyspaceName, freezeWFQR)
+				if tc.req.IdempotencyKey != "" {
+					env.tmc.expectVRQueryResultOnKeyspaceTablets(tc.targetKeyspace.KeyspaceName, &queryResult{
+						query: fmt.Sprintf("update _vt.vreplication set tags = concat_ws(',', nullif(tags, ''), 'switch_traffic_key:%s') where db_name = 'vt_%s' and workflow = '%s'",
+							tc.req.IdempotencyKey, targetKeyspaceName, workflowName),
+						result: &querypb.QueryResult{},
+					})
+				}
 			} else {
 				env.tmc.reverse.Store(true)
 				// Setup the routing rules as they would be after having previously done SwitchTraffic.
@@ -610,6 +3043,64 @@ func TestMoveTablesTrafficSwitching(t *testing.T) {
 	}
 }
 
+// TestWorkflowSwitchTrafficIdempotencyKey confirms that a SwitchTraffic
+// request whose idempotency key was already recorded on the workflow does
+// not switch traffic again.
+func TestWorkflowSwitchTrafficIdempotencyKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		tableName: {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		},
+	}
+	req := &vtctldatapb.WorkflowSwitchTrafficRequest{
+		Keyspace:       targetKeyspace.KeyspaceName,
+		Workflow:       workflowName,
+		Direction:      int32(DirectionForward),
+		TabletTypes:    []topodatapb.TabletType{topodatapb.TabletType_PRIMARY, topodatapb.TabletType_REPLICA},
+		IdempotencyKey: "a,b",
+	}
+
+	_, err := env.ws.WorkflowSwitchTraffic(ctx, req)
+	require.ErrorContains(t, err, "cannot contain a comma")
+
+	// Only one of the target shards needs to have the key recorded. No
+	// queries are expected as traffic is not switched again.
+	req.IdempotencyKey = "cutover-1"
+	env.tmc.workflowTags[startingTargetTabletUID] = "team-a,switch_traffic_key:cutover-1"
+	got, err := env.ws.WorkflowSwitchTraffic(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("SwitchTraffic was already done for workflow %s.%s with idempotency key cutover-1",
+		targetKeyspace.KeyspaceName, workflowName), got.Summary)
+	require.Equal(t, "Reads Not Switched. Writes Not Switched", got.CurrentState)
+	rr, err := env.ts.GetRoutingRules(ctx)
+	require.NoError(t, err)
+	for _, rule := range rr.Rules {
+		for _, to := range rule.ToTables {
+			require.NotEqual(t, fmt.Sprintf("%s.%s", targetKeyspace.KeyspaceName, tableName), to)
+		}
+	}
+}
+
 func TestMoveTablesTrafficSwitchingDryRun(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -777,6 +3268,47 @@ func TestMoveTablesTrafficSwitchingDryRun(t *testing.T) {
 			require.NoError(t, err)
 
 			require.EqualValues(t, tc.want, got.DryRunResults, "Server.WorkflowSwitchTraffic(DryRun:true) = %v, want %v", got.DryRunResults, tc.want)
+			require.Len(t, got.DryRunSteps, len(got.DryRunResults))
+			for i, step := range got.DryRunSteps {
+				require.Equal(t, got.DryRunResults[i], step.Details)
+				require.NotEmpty(t, step.Action)
+			}
 		})
 	}
 }
+
+func TestValidateKeyspacePrimaries(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+
+	report, err := env.ws.ValidateKeyspacePrimaries(ctx, targetKeyspace.KeyspaceName)
+	require.NoError(t, err)
+	require.Len(t, report, len(targetKeyspace.ShardNames))
+	for _, shard := range targetKeyspace.ShardNames {
+		require.True(t, report[shard].Reachable)
+		require.Equal(t, position, report[shard].Position)
+		require.NoError(t, report[shard].Error)
+	}
+
+	// Remove the tablet record for the primary of the second shard so that it
+	// can no longer be reached.
+	env.deleteTablet(env.tablets[targetKeyspace.KeyspaceName][startingTargetTabletUID+tabletUIDStep])
+	report, err = env.ws.ValidateKeyspacePrimaries(ctx, targetKeyspace.KeyspaceName)
+	require.ErrorContains(t, err, "not all primary tablets in the targetks keyspace are reachable")
+	require.Len(t, report, len(targetKeyspace.ShardNames))
+	require.True(t, report["-80"].Reachable)
+	require.False(t, report["80-"].Reachable)
+	require.Empty(t, report["80-"].Position)
+	require.Error(t, report["80-"].Error)
+}
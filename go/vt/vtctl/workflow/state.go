@@ -19,6 +19,8 @@ package workflow
 import (
 	"fmt"
 	"strings"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // VReplicationWorkflowType specifies whether workflow is
@@ -124,3 +126,14 @@ func (s *State) String() string {
 	}
 	return strings.Join(stateInfo, ". ")
 }
+
+// SwitchableDirections holds the tablet types whose traffic can currently be
+// switched in each direction for a workflow.
+type SwitchableDirections struct {
+	// Forward holds the tablet types whose traffic SwitchTraffic can switch
+	// to the target keyspace.
+	Forward []topodatapb.TabletType
+	// Backward holds the tablet types whose traffic ReverseTraffic can switch
+	// back to the source keyspace.
+	Backward []topodatapb.TabletType
+}
@@ -58,8 +58,16 @@ func (r *switcher) validateWorkflowHasCompleted(ctx context.Context) error {
 	return r.ts.validateWorkflowHasCompleted(ctx)
 }
 
-func (r *switcher) removeSourceTables(ctx context.Context, removalType TableRemovalType) error {
-	return r.ts.removeSourceTables(ctx, removalType)
+func (r *switcher) validateReverseReplication(ctx context.Context) error {
+	return r.ts.validateReverseReplication(ctx)
+}
+
+func (r *switcher) executePreDropSourceSQL(ctx context.Context, queries []string, ignoreErrors bool) error {
+	return r.ts.executePreDropSourceSQL(ctx, queries, ignoreErrors)
+}
+
+func (r *switcher) removeSourceTables(ctx context.Context, removalType TableRemovalType, keepVSchema bool) error {
+	return r.ts.removeSourceTables(ctx, removalType, keepVSchema)
 }
 
 func (r *switcher) dropSourceShards(ctx context.Context) error {
@@ -114,8 +122,8 @@ func (r *switcher) waitForCatchup(ctx context.Context, filteredReplicationWaitTi
 	return r.ts.waitForCatchup(ctx, filteredReplicationWaitTime)
 }
 
-func (r *switcher) stopSourceWrites(ctx context.Context) error {
-	return r.ts.stopSourceWrites(ctx)
+func (r *switcher) stopSourceWrites(ctx context.Context, drainTimeout time.Duration) error {
+	return r.ts.stopSourceWrites(ctx, drainTimeout)
 }
 
 func (r *switcher) stopStreams(ctx context.Context, sm *StreamMigrator) ([]string, error) {
@@ -150,7 +158,7 @@ func (r *switcher) dropTargetShards(ctx context.Context) error {
 	return r.ts.dropTargetShards(ctx)
 }
 
-func (r *switcher) logs() *[]string {
+func (r *switcher) logs() *LogRecorder {
 	return nil
 }
 
@@ -40,25 +40,25 @@ type switcherDryRun struct {
 }
 
 func (dr *switcherDryRun) addParticipatingTablesToKeyspace(ctx context.Context, keyspace, tableSpecs string) error {
-	dr.drLog.Log("All source tables will be added to the target keyspace vschema")
+	dr.drLog.LogStep("add_tables_to_vschema", dr.ts.TargetKeyspaceName(), "All source tables will be added to the target keyspace vschema")
 	return nil
 }
 
 func (dr *switcherDryRun) deleteRoutingRules(ctx context.Context) error {
-	dr.drLog.Log("Routing rules for participating tables will be deleted")
+	dr.drLog.LogStep("delete_routing_rules", dr.ts.TargetKeyspaceName(), "Routing rules for participating tables will be deleted")
 	return nil
 }
 
 func (dr *switcherDryRun) deleteShardRoutingRules(ctx context.Context) error {
 	if dr.ts.isPartialMigration {
-		dr.drLog.Log("Shard routing rules for participating shards will be deleted")
+		dr.drLog.LogStep("delete_shard_routing_rules", dr.ts.TargetKeyspaceName(), "Shard routing rules for participating shards will be deleted")
 	}
 	return nil
 }
 
 func (dr *switcherDryRun) deleteKeyspaceRoutingRules(ctx context.Context) error {
 	if dr.ts.IsMultiTenantMigration() {
-		dr.drLog.Log("Keyspace routing rules will be deleted")
+		dr.drLog.LogStep("delete_keyspace_routing_rules", dr.ts.TargetKeyspaceName(), "Keyspace routing rules will be deleted")
 	}
 	return nil
 }
@@ -68,7 +68,7 @@ func (dr *switcherDryRun) switchKeyspaceReads(ctx context.Context, types []topod
 	for _, servedType := range types {
 		tabletTypes = append(tabletTypes, servedType.String())
 	}
-	dr.drLog.Logf("Switch reads from keyspace %s to keyspace %s for tablet types [%s]",
+	dr.drLog.LogStepf("switch_reads", dr.ts.TargetKeyspaceName(), "Switch reads from keyspace %s to keyspace %s for tablet types [%s]",
 		dr.ts.SourceKeyspaceName(), dr.ts.TargetKeyspaceName(), strings.Join(tabletTypes, ","))
 	return nil
 }
@@ -86,10 +86,10 @@ func (dr *switcherDryRun) switchShardReads(ctx context.Context, cells []string,
 	sort.Strings(sourceShards)
 	sort.Strings(targetShards)
 	if direction == DirectionForward {
-		dr.drLog.Logf("Switch reads from keyspace %s to keyspace %s for shards [%s] to shards [%s]",
+		dr.drLog.LogStepf("switch_reads", dr.ts.TargetKeyspaceName(), "Switch reads from keyspace %s to keyspace %s for shards [%s] to shards [%s]",
 			dr.ts.SourceKeyspaceName(), dr.ts.TargetKeyspaceName(), strings.Join(sourceShards, ","), strings.Join(targetShards, ","))
 	} else {
-		dr.drLog.Logf("Switch reads from keyspace %s to keyspace %s for shards [%s] to shards [%s]",
+		dr.drLog.LogStepf("switch_reads", dr.ts.SourceKeyspaceName(), "Switch reads from keyspace %s to keyspace %s for shards [%s] to shards [%s]",
 			dr.ts.TargetKeyspaceName(), dr.ts.SourceKeyspaceName(), strings.Join(targetShards, ","), strings.Join(sourceShards, ","))
 	}
 	return nil
@@ -106,36 +106,36 @@ func (dr *switcherDryRun) switchTableReads(ctx context.Context, cells []string,
 	}
 	sort.Strings(dr.ts.Tables()) // For deterministic output
 	tables := strings.Join(dr.ts.Tables(), ",")
-	dr.drLog.Logf("Switch reads for tables [%s] to keyspace %s for tablet types [%s]", tables, ks, strings.Join(tabletTypes, ","))
-	dr.drLog.Logf("Routing rules for tables [%s] will be updated", tables)
+	dr.drLog.LogStepf("switch_reads", ks, "Switch reads for tables [%s] to keyspace %s for tablet types [%s]", tables, ks, strings.Join(tabletTypes, ","))
+	dr.drLog.LogStepf("update_routing_rules", ks, "Routing rules for tables [%s] will be updated", tables)
 	if rebuildSrvVSchema {
-		dr.drLog.Logf("Serving VSchema will be rebuilt for the %s keyspace", ks)
+		dr.drLog.LogStepf("rebuild_srv_vschema", ks, "Serving VSchema will be rebuilt for the %s keyspace", ks)
 	}
 	return nil
 }
 
 func (dr *switcherDryRun) createJournals(ctx context.Context, sourceWorkflows []string) error {
-	dr.drLog.Log("Create journal entries on source databases")
+	dr.drLog.LogStep("create_journals", dr.ts.SourceKeyspaceName(), "Create journal entries on source databases")
 	sort.Strings(sourceWorkflows) // For deterministic output
 	if len(sourceWorkflows) > 0 {
-		dr.drLog.Logf("Source workflows found: [%s]", strings.Join(sourceWorkflows, ","))
+		dr.drLog.LogStepf("create_journals", dr.ts.SourceKeyspaceName(), "Source workflows found: [%s]", strings.Join(sourceWorkflows, ","))
 	}
 	return nil
 }
 
 func (dr *switcherDryRun) allowTargetWrites(ctx context.Context) error {
 	sort.Strings(dr.ts.Tables()) // For deterministic output
-	dr.drLog.Logf("Enable writes on keyspace %s for tables [%s]", dr.ts.TargetKeyspaceName(), strings.Join(dr.ts.Tables(), ","))
+	dr.drLog.LogStepf("allow_writes", dr.ts.TargetKeyspaceName(), "Enable writes on keyspace %s for tables [%s]", dr.ts.TargetKeyspaceName(), strings.Join(dr.ts.Tables(), ","))
 	return nil
 }
 
 func (dr *switcherDryRun) changeRouting(ctx context.Context) error {
-	dr.drLog.Logf("Switch routing from keyspace %s to keyspace %s", dr.ts.SourceKeyspaceName(), dr.ts.TargetKeyspaceName())
+	dr.drLog.LogStepf("switch_writes", dr.ts.TargetKeyspaceName(), "Switch routing from keyspace %s to keyspace %s", dr.ts.SourceKeyspaceName(), dr.ts.TargetKeyspaceName())
 	var deleteLogs, addLogs []string
 	if dr.ts.MigrationType() == binlogdatapb.MigrationType_TABLES {
 		sort.Strings(dr.ts.Tables()) // For deterministic output
 		tables := strings.Join(dr.ts.Tables(), ",")
-		dr.drLog.Logf("Routing rules for tables [%s] will be updated", tables)
+		dr.drLog.LogStepf("update_routing_rules", dr.ts.TargetKeyspaceName(), "Routing rules for tables [%s] will be updated", tables)
 		return nil
 	}
 	deleteLogs = nil
@@ -157,8 +157,8 @@ func (dr *switcherDryRun) changeRouting(ctx context.Context) error {
 		addLogs = append(addLogs, fmt.Sprintf("shard:%s;tablet:%d", target.GetShard().ShardName(), target.GetShard().PrimaryAlias.Uid))
 	}
 	if len(deleteLogs) > 0 {
-		dr.drLog.Logf("IsPrimaryServing will be set to false for: [%s]", strings.Join(deleteLogs, ","))
-		dr.drLog.Logf("IsPrimaryServing will be set to true for: [%s]", strings.Join(addLogs, ","))
+		dr.drLog.LogStepf("update_primary_serving", dr.ts.SourceKeyspaceName(), "IsPrimaryServing will be set to false for: [%s]", strings.Join(deleteLogs, ","))
+		dr.drLog.LogStepf("update_primary_serving", dr.ts.TargetKeyspaceName(), "IsPrimaryServing will be set to true for: [%s]", strings.Join(addLogs, ","))
 	}
 	return nil
 }
@@ -173,7 +173,7 @@ func (dr *switcherDryRun) streamMigraterfinalize(ctx context.Context, ts *traffi
 	for _, t := range targets {
 		logs = append(logs, fmt.Sprintf("tablet:%d", t.GetPrimary().Alias.Uid))
 	}
-	dr.drLog.Logf("Switch writes completed, freeze and delete vreplication streams on: [%s]", strings.Join(logs, ","))
+	dr.drLog.LogStepf("finalize_streams", ts.TargetKeyspaceName(), "Switch writes completed, freeze and delete vreplication streams on: [%s]", strings.Join(logs, ","))
 	return nil
 }
 
@@ -187,12 +187,12 @@ func (dr *switcherDryRun) startReverseVReplication(ctx context.Context) error {
 	for _, t := range sources {
 		logs = append(logs, fmt.Sprintf("tablet:%d", t.GetPrimary().Alias.Uid))
 	}
-	dr.drLog.Logf("Start reverse vreplication streams on: [%s]", strings.Join(logs, ","))
+	dr.drLog.LogStepf("start_reverse_replication", dr.ts.SourceKeyspaceName(), "Start reverse vreplication streams on: [%s]", strings.Join(logs, ","))
 	return nil
 }
 
 func (dr *switcherDryRun) createReverseVReplication(ctx context.Context) error {
-	dr.drLog.Logf("Create reverse vreplication workflow %s", dr.ts.ReverseWorkflowName())
+	dr.drLog.LogStepf("create_reverse_workflow", dr.ts.SourceKeyspaceName(), "Create reverse vreplication workflow %s", dr.ts.ReverseWorkflowName())
 	return nil
 }
 
@@ -204,7 +204,7 @@ func (dr *switcherDryRun) migrateStreams(ctx context.Context, sm *StreamMigrator
 	}
 	logs := make([]string, 0)
 
-	dr.drLog.Logf("Migrate streams to %s:", dr.ts.TargetKeyspaceName())
+	dr.drLog.LogStepf("migrate_streams", dr.ts.TargetKeyspaceName(), "Migrate streams to %s:", dr.ts.TargetKeyspaceName())
 	allStreams := sm.Streams()
 	// Sort the keys and slices for deterministic output.
 	shards := maps.Keys(sm.Streams())
@@ -219,7 +219,7 @@ func (dr *switcherDryRun) migrateStreams(ctx context.Context, sm *StreamMigrator
 		}
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Migrate source streams: [%s]", strings.Join(logs, ","))
+		dr.drLog.LogStepf("migrate_streams", dr.ts.SourceKeyspaceName(), "Migrate source streams: [%s]", strings.Join(logs, ","))
 		logs = nil
 	}
 	// Sort the keys and slices for deterministic output.
@@ -238,17 +238,20 @@ func (dr *switcherDryRun) migrateStreams(ctx context.Context, sm *StreamMigrator
 		}
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Create target streams (as stopped): [%s]", strings.Join(logs, ","))
+		dr.drLog.LogStepf("migrate_streams", dr.ts.TargetKeyspaceName(), "Create target streams (as stopped): [%s]", strings.Join(logs, ","))
 	}
 	return nil
 }
 
 func (dr *switcherDryRun) waitForCatchup(ctx context.Context, filteredReplicationWaitTime time.Duration) error {
-	dr.drLog.Logf("Wait for vreplication on stopped streams to catchup for up to %v", filteredReplicationWaitTime)
+	dr.drLog.LogStepf("wait_for_catchup", dr.ts.TargetKeyspaceName(), "Wait for vreplication on stopped streams to catchup for up to %v", filteredReplicationWaitTime)
 	return nil
 }
 
-func (dr *switcherDryRun) stopSourceWrites(ctx context.Context) error {
+func (dr *switcherDryRun) stopSourceWrites(ctx context.Context, drainTimeout time.Duration) error {
+	if drainTimeout > 0 {
+		dr.drLog.LogStepf("drain_writes", dr.ts.SourceKeyspaceName(), "Once writes are stopped, wait for in-flight transactions on keyspace %s to drain for up to %v", dr.ts.SourceKeyspaceName(), drainTimeout)
+	}
 	logs := make([]string, 0)
 	sources := maps.Values(dr.ts.Sources())
 	// Sort the slice for deterministic output.
@@ -261,7 +264,7 @@ func (dr *switcherDryRun) stopSourceWrites(ctx context.Context) error {
 	}
 	sort.Strings(dr.ts.Tables()) // For deterministic output
 	if len(logs) > 0 {
-		dr.drLog.Logf("Stop writes on keyspace %s for tables [%s]: [%s]", dr.ts.SourceKeyspaceName(),
+		dr.drLog.LogStepf("stop_writes", dr.ts.SourceKeyspaceName(), "Stop writes on keyspace %s for tables [%s]: [%s]", dr.ts.SourceKeyspaceName(),
 			strings.Join(dr.ts.Tables(), ","), strings.Join(logs, ","))
 	}
 	return nil
@@ -284,23 +287,52 @@ func (dr *switcherDryRun) stopStreams(ctx context.Context, sm *StreamMigrator) (
 		}
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Stop streams on keyspace %s: [%s]", dr.ts.SourceKeyspaceName(), strings.Join(logs, ","))
+		dr.drLog.LogStepf("stop_streams", dr.ts.SourceKeyspaceName(), "Stop streams on keyspace %s: [%s]", dr.ts.SourceKeyspaceName(), strings.Join(logs, ","))
 	}
 	return nil, nil
 }
 
 func (dr *switcherDryRun) cancelMigration(ctx context.Context, sm *StreamMigrator) {
-	dr.drLog.Log("Cancel migration as requested")
+	dr.drLog.LogStep("cancel_migration", dr.ts.TargetKeyspaceName(), "Cancel migration as requested")
 }
 
 func (dr *switcherDryRun) lockKeyspace(ctx context.Context, keyspace, _ string) (context.Context, func(*error), error) {
-	dr.drLog.Logf("Lock keyspace %s", keyspace)
+	dr.drLog.LogStepf("lock_keyspace", keyspace, "Lock keyspace %s", keyspace)
 	return ctx, func(e *error) {
-		dr.drLog.Logf("Unlock keyspace %s", keyspace)
+		dr.drLog.LogStepf("unlock_keyspace", keyspace, "Unlock keyspace %s", keyspace)
 	}, nil
 }
 
-func (dr *switcherDryRun) removeSourceTables(ctx context.Context, removalType TableRemovalType) error {
+func (dr *switcherDryRun) validateReverseReplication(ctx context.Context) error {
+	// The validation only reads from the tablets so we run it for real.
+	if err := dr.ts.validateReverseReplication(ctx); err != nil {
+		return err
+	}
+	dr.drLog.LogStepf("validate_reverse_replication", dr.ts.SourceKeyspaceName(), "Reverse replication can be created: tablets are available to stream from in keyspace %s, whose primary tablets write row based binary logs with GTIDs, and the primary tablets in keyspace %s can insert into the vreplication table",
+		dr.ts.TargetKeyspaceName(), dr.ts.SourceKeyspaceName())
+	return nil
+}
+
+func (dr *switcherDryRun) executePreDropSourceSQL(ctx context.Context, queries []string, ignoreErrors bool) error {
+	sources := maps.Values(dr.ts.Sources())
+	// Sort the slice for deterministic output.
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].GetPrimary().Alias.Uid < sources[j].GetPrimary().Alias.Uid
+	})
+	tablets := make([]string, 0, len(sources))
+	for _, source := range sources {
+		tablets = append(tablets, fmt.Sprintf("keyspace:%s;shard:%s;tablet:%d",
+			source.GetPrimary().Keyspace, source.GetPrimary().Shard, source.GetPrimary().Alias.Uid))
+	}
+	msg := "Executing these SQL statements on the source primaries before removing the tables"
+	if ignoreErrors {
+		msg += ", ignoring any errors"
+	}
+	dr.drLog.LogStepf("execute_pre_drop_sql", dr.ts.SourceKeyspaceName(), "%s: [%s] on [%s]", msg, strings.Join(queries, ";"), strings.Join(tablets, ","))
+	return nil
+}
+
+func (dr *switcherDryRun) removeSourceTables(ctx context.Context, removalType TableRemovalType, keepVSchema bool) error {
 	logs := make([]string, 0)
 	sort.Strings(dr.ts.Tables()) // For deterministic output
 	sources := maps.Values(dr.ts.Sources())
@@ -319,8 +351,13 @@ func (dr *switcherDryRun) removeSourceTables(ctx context.Context, removalType Ta
 		action = "Renaming"
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("%s these tables from the database and removing them from the vschema for keyspace %s: [%s]",
-			action, dr.ts.SourceKeyspaceName(), strings.Join(logs, ","))
+		if keepVSchema {
+			dr.drLog.LogStepf("remove_tables", dr.ts.SourceKeyspaceName(), "%s these tables from the database and keeping them in the vschema for keyspace %s: [%s]",
+				action, dr.ts.SourceKeyspaceName(), strings.Join(logs, ","))
+		} else {
+			dr.drLog.LogStepf("remove_tables", dr.ts.SourceKeyspaceName(), "%s these tables from the database and removing them from the vschema for keyspace %s: [%s]",
+				action, dr.ts.SourceKeyspaceName(), strings.Join(logs, ","))
+		}
 	}
 	return nil
 }
@@ -347,7 +384,7 @@ func (dr *switcherDryRun) dropSourceShards(ctx context.Context) error {
 			si.Shard.PrimaryAlias.Cell, si.Keyspace(), si.ShardName()), strings.Join(tabletsList[si.ShardName()], ","))
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Delete shards (and all related tablets): [%s]", strings.Join(logs, ","))
+		dr.drLog.LogStepf("delete_shards", dr.ts.SourceKeyspaceName(), "Delete shards (and all related tablets): [%s]", strings.Join(logs, ","))
 	}
 
 	return nil
@@ -368,7 +405,7 @@ func (dr *switcherDryRun) dropTargetVReplicationStreams(ctx context.Context) err
 		logs = append(logs, fmt.Sprintf("keyspace:%s;shard:%s;workflow:%s;dbname:%s;tablet:%d",
 			t.GetShard().Keyspace(), t.GetShard().ShardName(), dr.ts.WorkflowName(), t.GetPrimary().DbName(), t.GetPrimary().Alias.Uid))
 	}
-	dr.drLog.Logf("Delete vreplication streams on targets: [%s]", strings.Join(logs, ","))
+	dr.drLog.LogStepf("delete_streams", dr.ts.TargetKeyspaceName(), "Delete vreplication streams on targets: [%s]", strings.Join(logs, ","))
 	return nil
 }
 
@@ -383,7 +420,7 @@ func (dr *switcherDryRun) dropSourceReverseVReplicationStreams(ctx context.Conte
 		logs = append(logs, fmt.Sprintf("keyspace:%s;shard:%s;workflow:%s;dbname:%s;tablet:%d",
 			t.GetShard().Keyspace(), t.GetShard().ShardName(), ReverseWorkflowName(dr.ts.WorkflowName()), t.GetPrimary().DbName(), t.GetPrimary().Alias.Uid))
 	}
-	dr.drLog.Logf("Delete reverse vreplication streams on sources: [%s]", strings.Join(logs, ","))
+	dr.drLog.LogStepf("delete_streams", dr.ts.SourceKeyspaceName(), "Delete reverse vreplication streams on sources: [%s]", strings.Join(logs, ","))
 	return nil
 }
 
@@ -399,7 +436,7 @@ func (dr *switcherDryRun) freezeTargetVReplication(ctx context.Context) error {
 			target.GetPrimary().Keyspace, target.GetPrimary().Shard, target.GetPrimary().Alias.Uid, dr.ts.WorkflowName(), target.GetPrimary().DbName()))
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Mark vreplication streams frozen on: [%s]", strings.Join(logs, ","))
+		dr.drLog.LogStepf("freeze_streams", dr.ts.TargetKeyspaceName(), "Mark vreplication streams frozen on: [%s]", strings.Join(logs, ","))
 	}
 	return nil
 }
@@ -415,7 +452,7 @@ func (dr *switcherDryRun) dropSourceDeniedTables(ctx context.Context) error {
 		logs = append(logs, fmt.Sprintf("keyspace:%s;shard:%s;tablet:%d", si.Keyspace(), si.ShardName(), si.PrimaryAlias.Uid))
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Denied tables records on [%s] will be removed from: [%s]", strings.Join(dr.ts.Tables(), ","), strings.Join(logs, ","))
+		dr.drLog.LogStepf("remove_denied_tables", dr.ts.SourceKeyspaceName(), "Denied tables records on [%s] will be removed from: [%s]", strings.Join(dr.ts.Tables(), ","), strings.Join(logs, ","))
 	}
 	return nil
 }
@@ -431,13 +468,13 @@ func (dr *switcherDryRun) dropTargetDeniedTables(ctx context.Context) error {
 		logs = append(logs, fmt.Sprintf("keyspace:%s;shard:%s;tablet:%d", si.Keyspace(), si.ShardName(), si.PrimaryAlias.Uid))
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Denied tables records on [%s] will be removed from: [%s]", strings.Join(dr.ts.Tables(), ","), strings.Join(logs, ","))
+		dr.drLog.LogStepf("remove_denied_tables", dr.ts.TargetKeyspaceName(), "Denied tables records on [%s] will be removed from: [%s]", strings.Join(dr.ts.Tables(), ","), strings.Join(logs, ","))
 	}
 	return nil
 }
 
-func (dr *switcherDryRun) logs() *[]string {
-	return &dr.drLog.logs
+func (dr *switcherDryRun) logs() *LogRecorder {
+	return dr.drLog
 }
 
 func (dr *switcherDryRun) removeTargetTables(ctx context.Context) error {
@@ -455,7 +492,7 @@ func (dr *switcherDryRun) removeTargetTables(ctx context.Context) error {
 		}
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Dropping these tables from the database and removing from the vschema for keyspace %s: [%s]",
+		dr.drLog.LogStepf("remove_tables", dr.ts.TargetKeyspaceName(), "Dropping these tables from the database and removing from the vschema for keyspace %s: [%s]",
 			dr.ts.TargetKeyspaceName(), strings.Join(logs, ","))
 	}
 	return nil
@@ -484,7 +521,7 @@ func (dr *switcherDryRun) dropTargetShards(ctx context.Context) error {
 			si.Shard.PrimaryAlias.Cell, si.Keyspace(), si.ShardName()), strings.Join(tabletsList[si.ShardName()], ","))
 	}
 	if len(logs) > 0 {
-		dr.drLog.Logf("Delete shards (and all related tablets): [%s]", strings.Join(logs, ","))
+		dr.drLog.LogStepf("delete_shards", dr.ts.TargetKeyspaceName(), "Delete shards (and all related tablets): [%s]", strings.Join(logs, ","))
 	}
 
 	return nil
@@ -499,7 +536,7 @@ func (dr *switcherDryRun) resetSequences(ctx context.Context) error {
 	if !mustReset {
 		return nil
 	}
-	dr.drLog.Log("The sequence caches will be reset on the source since sequence tables are being moved")
+	dr.drLog.LogStep("reset_sequences", dr.ts.SourceKeyspaceName(), "The sequence caches will be reset on the source since sequence tables are being moved")
 	return nil
 }
 
@@ -507,7 +544,7 @@ func (dr *switcherDryRun) initializeTargetSequences(ctx context.Context, sequenc
 	// Sort keys for deterministic output.
 	sortedBackingTableNames := maps.Keys(sequencesByBackingTable)
 	slices.Sort(sortedBackingTableNames)
-	dr.drLog.Log(fmt.Sprintf("The following sequence backing tables used by tables being moved will be initialized: %s",
+	dr.drLog.LogStep("initialize_sequences", dr.ts.TargetKeyspaceName(), fmt.Sprintf("The following sequence backing tables used by tables being moved will be initialized: %s",
 		strings.Join(sortedBackingTableNames, ",")))
 	return nil
 }
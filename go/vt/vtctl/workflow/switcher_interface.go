@@ -27,9 +27,10 @@ type iswitcher interface {
 	lockKeyspace(ctx context.Context, keyspace, action string) (context.Context, func(*error), error)
 	cancelMigration(ctx context.Context, sm *StreamMigrator)
 	stopStreams(ctx context.Context, sm *StreamMigrator) ([]string, error)
-	stopSourceWrites(ctx context.Context) error
+	stopSourceWrites(ctx context.Context, drainTimeout time.Duration) error
 	waitForCatchup(ctx context.Context, filteredReplicationWaitTime time.Duration) error
 	migrateStreams(ctx context.Context, sm *StreamMigrator) error
+	validateReverseReplication(ctx context.Context) error
 	createReverseVReplication(ctx context.Context) error
 	createJournals(ctx context.Context, sourceWorkflows []string) error
 	allowTargetWrites(ctx context.Context) error
@@ -40,7 +41,8 @@ type iswitcher interface {
 	switchTableReads(ctx context.Context, cells []string, servedType []topodatapb.TabletType, rebuildSrvVSchema bool, direction TrafficSwitchDirection) error
 	switchShardReads(ctx context.Context, cells []string, servedType []topodatapb.TabletType, direction TrafficSwitchDirection) error
 	validateWorkflowHasCompleted(ctx context.Context) error
-	removeSourceTables(ctx context.Context, removalType TableRemovalType) error
+	executePreDropSourceSQL(ctx context.Context, queries []string, ignoreErrors bool) error
+	removeSourceTables(ctx context.Context, removalType TableRemovalType, keepVSchema bool) error
 	dropSourceShards(ctx context.Context) error
 	dropSourceDeniedTables(ctx context.Context) error
 	dropTargetDeniedTables(ctx context.Context) error
@@ -55,5 +57,5 @@ type iswitcher interface {
 	addParticipatingTablesToKeyspace(ctx context.Context, keyspace, tableSpecs string) error
 	resetSequences(ctx context.Context) error
 	initializeTargetSequences(ctx context.Context, sequencesByBackingTable map[string]*sequenceMetadata) error
-	logs() *[]string
+	logs() *LogRecorder
 }
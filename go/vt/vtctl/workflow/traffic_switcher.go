@@ -29,6 +29,7 @@ import (
 	"golang.org/x/exp/maps"
 	"golang.org/x/sync/errgroup"
 
+	"vitess.io/vitess/go/constants/sidecar"
 	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqlescape"
@@ -79,6 +80,33 @@ const (
 	sqlDeleteWorkflow    = "delete from _vt.vreplication where db_name = %s and workflow = %s"
 	sqlGetMaxSequenceVal = "select max(%a) as maxval from %a.%a"
 	sqlInitSequenceTable = "insert into %a.%a (id, next_id, cache) values (0, %d, 1000) on duplicate key update next_id = if(next_id < %d, %d, next_id)"
+
+	sqlSetStreamStartPosition = "update _vt.vreplication set pos = %s where id = %d"
+	sqlAddWorkflowTag         = "update _vt.vreplication set tags = concat_ws(',', nullif(tags, ''), %s) where db_name = %s and workflow = %s"
+
+	// sqlGetActiveTransactionCount counts the open transactions on
+	// connections using the given database, leaving out the connection
+	// running the query.
+	sqlGetActiveTransactionCount = "select count(*) from information_schema.innodb_trx as trx join information_schema.processlist as pl on trx.trx_mysql_thread_id = pl.id where pl.db = %s and pl.id != connection_id()"
+
+	// sqlCheckVReplicationInsertPrivilege reports whether the current user
+	// holds the INSERT privilege on the sidecar database's vreplication
+	// table, globally or on the database or the table itself.
+	sqlCheckVReplicationInsertPrivilege = "select count(*) > 0 as can_insert from (" +
+		"select 1 from information_schema.user_privileges where grantee = %[1]s and privilege_type = 'INSERT' " +
+		"union all select 1 from information_schema.schema_privileges where grantee = %[1]s and %[2]s like table_schema and privilege_type = 'INSERT' " +
+		"union all select 1 from information_schema.table_privileges where grantee = %[1]s and %[2]s like table_schema and table_name = 'vreplication' and privilege_type = 'INSERT'" +
+		") as privileges"
+	// sqlCurrentUserGrantee is the current user in the 'user'@'host' form
+	// used for the grantee in the information_schema privilege tables.
+	sqlCurrentUserGrantee = `concat("'", substring_index(current_user(), '@', 1), "'@'", substring_index(current_user(), '@', -1), "'")`
+	// sqlGetBinlogSettings gets the binary log settings that vreplication
+	// relies on to stream changes from a tablet.
+	sqlGetBinlogSettings = "select @@global.log_bin as log_bin, @@global.binlog_format as binlog_format, @@global.binlog_row_image as binlog_row_image, @@global.gtid_mode as gtid_mode"
+
+	// How often to check the number of in-flight transactions on the source
+	// primaries while waiting for them to drain.
+	drainSourceWritesPollInterval = time.Duration(250 * time.Millisecond)
 )
 
 // accessType specifies the type of access for a shard (allow/disallow writes).
@@ -248,8 +276,10 @@ type trafficSwitcher struct {
 	options          *vtctldatapb.WorkflowOptions
 }
 
-func (ts *trafficSwitcher) TopoServer() *topo.Server                          { return ts.ws.ts }
-func (ts *trafficSwitcher) TabletManagerClient() tmclient.TabletManagerClient { return ts.ws.tmc }
+func (ts *trafficSwitcher) TopoServer() *topo.Server { return ts.ws.ts }
+func (ts *trafficSwitcher) TabletManagerClient() tmclient.TabletManagerClient {
+	return ts.ws.tmClient()
+}
 func (ts *trafficSwitcher) Logger() logutil.Logger {
 	if ts.logger == nil {
 		ts.logger = logutil.NewConsoleLogger()
@@ -273,6 +303,15 @@ func (ts *trafficSwitcher) WorkflowName() string                           { ret
 func (ts *trafficSwitcher) SourceTimeZone() string                         { return ts.sourceTimeZone }
 func (ts *trafficSwitcher) TargetTimeZone() string                         { return ts.targetTimeZone }
 
+// refreshTimeout returns how long to wait for the tablets in a shard to
+// refresh their state.
+func (ts *trafficSwitcher) refreshTimeout() time.Duration {
+	if ts.ws == nil {
+		return shardTabletRefreshTimeout
+	}
+	return ts.ws.refreshTimeout()
+}
+
 func (ts *trafficSwitcher) ForAllSources(f func(source *MigrationSource) error) error {
 	var wg sync.WaitGroup
 	allErrors := &concurrency.AllErrorRecorder{}
@@ -472,7 +511,7 @@ func (ts *trafficSwitcher) dropSourceDeniedTables(ctx context.Context) error {
 		}); err != nil {
 			return err
 		}
-		rtbsCtx, cancel := context.WithTimeout(ctx, shardTabletRefreshTimeout)
+		rtbsCtx, cancel := context.WithTimeout(ctx, ts.refreshTimeout())
 		defer cancel()
 		_, _, err := topotools.RefreshTabletsByShard(rtbsCtx, ts.TopoServer(), ts.TabletManagerClient(), source.GetShard(), nil, ts.Logger())
 		return err
@@ -486,7 +525,7 @@ func (ts *trafficSwitcher) dropTargetDeniedTables(ctx context.Context) error {
 		}); err != nil {
 			return err
 		}
-		rtbsCtx, cancel := context.WithTimeout(ctx, shardTabletRefreshTimeout)
+		rtbsCtx, cancel := context.WithTimeout(ctx, ts.refreshTimeout())
 		defer cancel()
 		_, _, err := topotools.RefreshTabletsByShard(rtbsCtx, ts.TopoServer(), ts.TabletManagerClient(), target.GetShard(), nil, ts.Logger())
 		return err
@@ -516,7 +555,32 @@ func (ts *trafficSwitcher) dropParticipatingTablesFromKeyspace(ctx context.Conte
 	return ts.TopoServer().SaveVSchema(ctx, keyspace, vschema)
 }
 
-func (ts *trafficSwitcher) removeSourceTables(ctx context.Context, removalType TableRemovalType) error {
+// executePreDropSourceSQL executes the given SQL statements on each source
+// primary tablet. Unless ignoreErrors is set, the first error encountered
+// on a tablet is returned.
+func (ts *trafficSwitcher) executePreDropSourceSQL(ctx context.Context, queries []string, ignoreErrors bool) error {
+	return ts.ForAllSources(func(source *MigrationSource) error {
+		primary := source.GetPrimary()
+		for _, query := range queries {
+			ts.Logger().Infof("%s: Executing pre-drop SQL: %s", topoproto.TabletAliasString(primary.GetAlias()), query)
+			_, err := ts.ws.tmClient().ExecuteFetchAsDba(ctx, primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:   []byte(query),
+				DbName:  primary.DbName(),
+				MaxRows: 1,
+			})
+			if err != nil {
+				if ignoreErrors {
+					ts.Logger().Warningf("%s: Ignoring error executing pre-drop SQL %q: %v", topoproto.TabletAliasString(primary.GetAlias()), query, err)
+					continue
+				}
+				return vterrors.Wrapf(err, "failed to execute pre-drop SQL %q on tablet %s", query, topoproto.TabletAliasString(primary.GetAlias()))
+			}
+		}
+		return nil
+	})
+}
+
+func (ts *trafficSwitcher) removeSourceTables(ctx context.Context, removalType TableRemovalType, keepVSchema bool) error {
 	err := ts.ForAllSources(func(source *MigrationSource) error {
 		for _, tableName := range ts.Tables() {
 			primaryDbName, err := sqlescape.EnsureEscaped(source.GetPrimary().DbName())
@@ -541,16 +605,18 @@ func (ts *trafficSwitcher) removeSourceTables(ctx context.Context, removalType T
 					topoproto.TabletAliasString(source.GetPrimary().GetAlias()), source.GetPrimary().DbName(), tableName, source.GetPrimary().DbName(), renameName)
 				query = fmt.Sprintf("rename table %s.%s TO %s.%s", primaryDbName, tableNameEscaped, primaryDbName, renameName)
 			}
-			_, err = ts.ws.tmc.ExecuteFetchAsDba(ctx, source.GetPrimary().Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			_, err = ts.ws.tmClient().ExecuteFetchAsDba(ctx, source.GetPrimary().Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
 				Query:                   []byte(query),
 				MaxRows:                 1,
 				ReloadSchema:            true,
 				DisableForeignKeyChecks: true,
 			})
 			if err != nil {
-				if mysqlErr, ok := err.(*sqlerror.SQLError); ok && mysqlErr.Num == sqlerror.ERNoSuchTable {
+				if isNoSuchTableError(err) {
+					// The table was already removed, e.g. by a previous attempt
+					// that failed part way through, so we move on to the next.
 					ts.Logger().Warningf("%s: Table %s did not exist when attempting to remove it", topoproto.TabletAliasString(source.GetPrimary().GetAlias()), tableName)
-					return nil
+					continue
 				}
 				ts.Logger().Errorf("%s: Error removing table %s: %v", topoproto.TabletAliasString(source.GetPrimary().GetAlias()), tableName, err)
 				return err
@@ -564,21 +630,39 @@ func (ts *trafficSwitcher) removeSourceTables(ctx context.Context, removalType T
 		return err
 	}
 
+	if keepVSchema {
+		ts.Logger().Infof("Keeping the vschema definitions for the tables in the %s keyspace", ts.SourceKeyspaceName())
+		return nil
+	}
 	return ts.dropParticipatingTablesFromKeyspace(ctx, ts.SourceKeyspaceName())
 }
 
-// FIXME: even after dropSourceShards there are still entries in the topo, need to research and fix
+// dropSourceShards deletes the source shards, along with their tablets, from
+// the topo. At most topo.DefaultConcurrency shards are deleted at a time and
+// every shard is attempted even if deleting another one fails. Each shard is
+// only deleted once all of the tablets in its replication graph have been.
 func (ts *trafficSwitcher) dropSourceShards(ctx context.Context) error {
-	return ts.ForAllSources(func(source *MigrationSource) error {
-		ts.Logger().Infof("Deleting shard %s.%s\n", source.GetShard().Keyspace(), source.GetShard().ShardName())
-		err := ts.ws.DeleteShard(ctx, source.GetShard().Keyspace(), source.GetShard().ShardName(), true, false)
-		if err != nil {
-			ts.Logger().Errorf("Error deleting shard %s: %v", source.GetShard().ShardName(), err)
-			return err
-		}
-		ts.Logger().Infof("Deleted shard %s.%s\n", source.GetShard().Keyspace(), source.GetShard().ShardName())
-		return nil
-	})
+	// FIXME: even after dropSourceShards there are still entries in the topo, need to research and fix
+	var (
+		eg        errgroup.Group
+		allErrors = &concurrency.AllErrorRecorder{}
+	)
+	eg.SetLimit(topo.DefaultConcurrency)
+	for _, source := range ts.sources {
+		eg.Go(func() error {
+			ts.Logger().Infof("Deleting shard %s.%s\n", source.GetShard().Keyspace(), source.GetShard().ShardName())
+			err := ts.ws.DeleteShard(ctx, source.GetShard().Keyspace(), source.GetShard().ShardName(), true, false)
+			if err != nil {
+				ts.Logger().Errorf("Error deleting shard %s: %v", source.GetShard().ShardName(), err)
+				allErrors.RecordError(err)
+				return nil
+			}
+			ts.Logger().Infof("Deleted shard %s.%s\n", source.GetShard().Keyspace(), source.GetShard().ShardName())
+			return nil
+		})
+	}
+	_ = eg.Wait() // The errors are collected in allErrors.
+	return allErrors.AggrError(vterrors.Aggregate)
 }
 
 func (ts *trafficSwitcher) switchShardReads(ctx context.Context, cells []string, servedTypes []topodatapb.TabletType, direction TrafficSwitchDirection) error {
@@ -645,6 +729,67 @@ func (ts *trafficSwitcher) switchTableReads(ctx context.Context, cells []string,
 	return nil
 }
 
+// validateReverseReplication confirms that the reverse workflow can be
+// created and run, without making any changes: the user that creates its
+// streams on each source primary tablet must be able to insert into the
+// vreplication table, and each target primary tablet must write the row
+// based, GTID tagged, binary logs that the streams read from.
+func (ts *trafficSwitcher) validateReverseReplication(ctx context.Context) error {
+	err := ts.ForAllSources(func(source *MigrationSource) error {
+		primary := source.GetPrimary()
+		qr, err := ts.ws.tmClient().ExecuteFetchAsDba(ctx, primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(fmt.Sprintf(sqlCheckVReplicationInsertPrivilege, sqlCurrentUserGrantee, encodeString(sidecar.GetName()))),
+			MaxRows: 1,
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to check the privileges on the primary tablet %s in shard %s/%s",
+				topoproto.TabletAliasString(primary.GetAlias()), source.GetShard().Keyspace(), source.GetShard().ShardName())
+		}
+		if row := sqltypes.Proto3ToResult(qr).Named().Row(); row == nil || !row.AsBool("can_insert", false) {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the primary tablet %s in shard %s/%s cannot insert into the %s.vreplication table",
+				topoproto.TabletAliasString(primary.GetAlias()), source.GetShard().Keyspace(), source.GetShard().ShardName(), sidecar.GetName())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ts.ForAllTargets(func(target *MigrationTarget) error {
+		primary := target.GetPrimary()
+		qr, err := ts.ws.tmClient().ExecuteFetchAsDba(ctx, primary.Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query:   []byte(sqlGetBinlogSettings),
+			MaxRows: 1,
+		})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to get the binary log settings on the primary tablet %s in shard %s/%s",
+				topoproto.TabletAliasString(primary.GetAlias()), target.GetShard().Keyspace(), target.GetShard().ShardName())
+		}
+		row := sqltypes.Proto3ToResult(qr).Named().Row()
+		if row == nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no binary log settings were returned by the primary tablet %s in shard %s/%s",
+				topoproto.TabletAliasString(primary.GetAlias()), target.GetShard().Keyspace(), target.GetShard().ShardName())
+		}
+		var problems []string
+		if row.AsInt64("log_bin", 0) != 1 {
+			problems = append(problems, "binary logging is disabled")
+		}
+		if format := row.AsString("binlog_format", ""); !strings.EqualFold(format, "ROW") {
+			problems = append(problems, fmt.Sprintf("binlog_format is %s rather than ROW", format))
+		}
+		if image := row.AsString("binlog_row_image", ""); strings.EqualFold(image, "MINIMAL") {
+			problems = append(problems, "binlog_row_image is MINIMAL")
+		}
+		if mode := row.AsString("gtid_mode", ""); !strings.EqualFold(mode, "ON") {
+			problems = append(problems, fmt.Sprintf("gtid_mode is %s rather than ON", mode))
+		}
+		if len(problems) > 0 {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the reverse workflow cannot stream from the primary tablet %s in shard %s/%s: %s",
+				topoproto.TabletAliasString(primary.GetAlias()), target.GetShard().Keyspace(), target.GetShard().ShardName(), strings.Join(problems, ", "))
+		}
+		return nil
+	})
+}
+
 func (ts *trafficSwitcher) startReverseVReplication(ctx context.Context) error {
 	return ts.ForAllSources(func(source *MigrationSource) error {
 		query := fmt.Sprintf("update _vt.vreplication set state='Running', message='' where db_name=%s and workflow=%s",
@@ -832,7 +977,7 @@ func (ts *trafficSwitcher) deleteReverseVReplication(ctx context.Context) error
 			// vreplication.exec returns no error on delete if the rows do not exist.
 			return err
 		}
-		ts.ws.deleteWorkflowVDiffData(ctx, source.GetPrimary().Tablet, ts.reverseWorkflow)
+		ts.ws.deleteWorkflowVDiffData(ctx, source.GetPrimary().Tablet, ts.reverseWorkflow, nil)
 		ts.ws.optimizeCopyStateTable(source.GetPrimary().Tablet)
 		return nil
 	})
@@ -1010,7 +1155,68 @@ func (ts *trafficSwitcher) waitForCatchup(ctx context.Context, filteredReplicati
 	})
 }
 
-func (ts *trafficSwitcher) stopSourceWrites(ctx context.Context) error {
+// drainSourceWrites waits for the in-flight transactions in the source
+// database on the source primaries to finish, for up to drainTimeout. It is
+// called once writes have been denied on the source, so no new transactions
+// can start while it waits. If they have not all finished by then a warning
+// is logged and nil is returned so that the switch can go ahead as it would
+// have without the wait.
+func (ts *trafficSwitcher) drainSourceWrites(ctx context.Context, drainTimeout time.Duration) error {
+	dctx, cancel := context.WithTimeout(ctx, drainTimeout)
+	defer cancel()
+	err := ts.ForAllSources(func(source *MigrationSource) error {
+		primary := source.GetPrimary()
+		if primary == nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary found for source shard %s", source.GetShard())
+		}
+		ticker := time.NewTicker(drainSourceWritesPollInterval)
+		defer ticker.Stop()
+		for {
+			qr, err := ts.ws.tmClient().ExecuteFetchAsDba(dctx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:   []byte(fmt.Sprintf(sqlGetActiveTransactionCount, encodeString(primary.DbName()))),
+				MaxRows: 1,
+			})
+			if err != nil {
+				if dctx.Err() != nil {
+					return dctx.Err()
+				}
+				return vterrors.Wrapf(err, "failed to get the number of in-flight transactions on source tablet %s",
+					topoproto.TabletAliasString(primary.Alias))
+			}
+			res := sqltypes.Proto3ToResult(qr)
+			if len(res.Rows) != 1 {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result when getting the number of in-flight transactions on source tablet %s: %v",
+					topoproto.TabletAliasString(primary.Alias), res.Rows)
+			}
+			count, err := res.Rows[0][0].ToInt64()
+			if err != nil {
+				return vterrors.Wrapf(err, "failed to get the number of in-flight transactions on source tablet %s",
+					topoproto.TabletAliasString(primary.Alias))
+			}
+			if count == 0 {
+				return nil
+			}
+			select {
+			case <-dctx.Done():
+				ts.Logger().Warningf("%d transaction(s) still in-flight on source tablet %s after waiting %v for them to drain",
+					count, topoproto.TabletAliasString(primary.Alias), drainTimeout)
+				return dctx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+	if err != nil && dctx.Err() != nil && ctx.Err() == nil {
+		ts.Logger().Warningf("In-flight transactions in the %s keyspace did not drain within %v, continuing the traffic switch",
+			ts.SourceKeyspaceName(), drainTimeout)
+		return nil
+	}
+	return err
+}
+
+// stopSourceWrites denies writes on the source and records the source
+// positions. If drainTimeout is set, it first waits up to that long for the
+// transactions that were already in flight when writes were denied.
+func (ts *trafficSwitcher) stopSourceWrites(ctx context.Context, drainTimeout time.Duration) error {
 	var err error
 	if ts.MigrationType() == binlogdatapb.MigrationType_TABLES {
 		err = ts.switchDeniedTables(ctx)
@@ -1021,16 +1227,46 @@ func (ts *trafficSwitcher) stopSourceWrites(ctx context.Context) error {
 		log.Warningf("Error: %s", err)
 		return err
 	}
-	return ts.ForAllSources(func(source *MigrationSource) error {
-		var err error
-		source.Position, err = ts.TabletManagerClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
-		log.Infof("Stopped Source Writes. Position for source %v:%v: %v",
-			ts.SourceKeyspaceName(), source.GetShard().ShardName(), source.Position)
-		if err != nil {
+	if drainTimeout > 0 {
+		ts.Logger().Infof("Waiting up to %v for in-flight source writes to drain", drainTimeout)
+		if err := ts.drainSourceWrites(ctx, drainTimeout); err != nil {
 			log.Warningf("Error: %s", err)
+			return err
 		}
+	}
+	if err := ts.gatherSourcePositions(ctx); err != nil {
+		log.Warningf("Error: %s", err)
 		return err
-	})
+	}
+	return nil
+}
+
+// gatherSourcePositions records the current position of each source shard's
+// primary. This is done while writes are stopped on the source, so the
+// positions are fetched concurrently -- at most topo.DefaultConcurrency at a
+// time -- to keep that window short. Every shard is attempted even if
+// fetching the position for another one fails.
+func (ts *trafficSwitcher) gatherSourcePositions(ctx context.Context) error {
+	var (
+		eg        errgroup.Group
+		allErrors = &concurrency.AllErrorRecorder{}
+	)
+	eg.SetLimit(topo.DefaultConcurrency)
+	for _, source := range ts.sources {
+		eg.Go(func() error {
+			position, err := ts.TabletManagerClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
+			if err != nil {
+				allErrors.RecordError(vterrors.Wrapf(err, "failed to get the primary position for source shard %s/%s",
+					ts.SourceKeyspaceName(), source.GetShard().ShardName()))
+				return nil
+			}
+			source.Position = position
+			ts.Logger().Infof("Position for source %v:%v: %v", ts.SourceKeyspaceName(), source.GetShard().ShardName(), source.Position)
+			return nil
+		})
+	}
+	_ = eg.Wait() // The errors are collected in allErrors.
+	return allErrors.AggrError(vterrors.Aggregate)
 }
 
 // switchDeniedTables switches the denied tables rules for the traffic switch.
@@ -1048,7 +1284,7 @@ func (ts *trafficSwitcher) switchDeniedTables(ctx context.Context) error {
 			}); err != nil {
 				return err
 			}
-			rtbsCtx, cancel := context.WithTimeout(ectx, shardTabletRefreshTimeout)
+			rtbsCtx, cancel := context.WithTimeout(ectx, ts.refreshTimeout())
 			defer cancel()
 			isPartial, partialDetails, err := topotools.RefreshTabletsByShard(rtbsCtx, ts.TopoServer(), ts.TabletManagerClient(), source.GetShard(), nil, ts.Logger())
 			if isPartial {
@@ -1065,7 +1301,7 @@ func (ts *trafficSwitcher) switchDeniedTables(ctx context.Context) error {
 			}); err != nil {
 				return err
 			}
-			rtbsCtx, cancel := context.WithTimeout(ectx, shardTabletRefreshTimeout)
+			rtbsCtx, cancel := context.WithTimeout(ectx, ts.refreshTimeout())
 			defer cancel()
 			isPartial, partialDetails, err := topotools.RefreshTabletsByShard(rtbsCtx, ts.TopoServer(), ts.TabletManagerClient(), target.GetShard(), nil, ts.Logger())
 			if isPartial {
@@ -1136,7 +1372,7 @@ func (ts *trafficSwitcher) dropTargetVReplicationStreams(ctx context.Context) er
 			// vreplication.exec returns no error on delete if the rows do not exist.
 			return err
 		}
-		ts.ws.deleteWorkflowVDiffData(ctx, target.GetPrimary().Tablet, ts.WorkflowName())
+		ts.ws.deleteWorkflowVDiffData(ctx, target.GetPrimary().Tablet, ts.WorkflowName(), nil)
 		ts.ws.optimizeCopyStateTable(target.GetPrimary().Tablet)
 		return nil
 	})
@@ -1150,7 +1386,7 @@ func (ts *trafficSwitcher) dropSourceReverseVReplicationStreams(ctx context.Cont
 			// vreplication.exec returns no error on delete if the rows do not exist.
 			return err
 		}
-		ts.ws.deleteWorkflowVDiffData(ctx, source.GetPrimary().Tablet, ReverseWorkflowName(ts.WorkflowName()))
+		ts.ws.deleteWorkflowVDiffData(ctx, source.GetPrimary().Tablet, ReverseWorkflowName(ts.WorkflowName()), nil)
 		ts.ws.optimizeCopyStateTable(source.GetPrimary().Tablet)
 		return nil
 	})
@@ -1171,7 +1407,7 @@ func (ts *trafficSwitcher) removeTargetTables(ctx context.Context) error {
 			query := fmt.Sprintf("drop table %s.%s", primaryDbName, tableName)
 			ts.Logger().Infof("%s: Dropping table %s.%s\n",
 				topoproto.TabletAliasString(target.GetPrimary().GetAlias()), target.GetPrimary().DbName(), tableName)
-			res, err := ts.ws.tmc.ExecuteFetchAsDba(ctx, target.GetPrimary().Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			res, err := ts.ws.tmClient().ExecuteFetchAsDba(ctx, target.GetPrimary().Tablet, false, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
 				Query:                   []byte(query),
 				MaxRows:                 1,
 				ReloadSchema:            true,
@@ -1179,10 +1415,10 @@ func (ts *trafficSwitcher) removeTargetTables(ctx context.Context) error {
 			})
 			log.Infof("Removed target table with result: %+v", res)
 			if err != nil {
-				if mysqlErr, ok := err.(*sqlerror.SQLError); ok && mysqlErr.Num == sqlerror.ERNoSuchTable {
+				if isNoSuchTableError(err) {
 					// The table was already gone, so we can ignore the error.
 					ts.Logger().Warningf("%s: Table %s did not exist when attempting to remove it", topoproto.TabletAliasString(target.GetPrimary().GetAlias()), tableName)
-					return nil
+					continue
 				}
 				ts.Logger().Errorf("%s: Error removing table %s: %v", topoproto.TabletAliasString(target.GetPrimary().GetAlias()), tableName, err)
 				return err
@@ -1282,44 +1518,183 @@ func (ts *trafficSwitcher) executeLockTablesOnSource(ctx context.Context) error
 	// trim extra trailing comma
 	lockStmt := sb.String()[:sb.Len()-1]
 
+	maxAttempts, retryDelay := ts.ws.lockTablesAttempts(), ts.ws.lockTablesRetryWait()
 	return ts.ForAllSources(func(source *MigrationSource) error {
 		primary := source.GetPrimary()
 		if primary == nil {
 			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary found for source shard %s", source.GetShard())
 		}
 		tablet := primary.Tablet
-		_, err := ts.ws.tmc.ExecuteFetchAsDba(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
-			Query:          []byte(lockStmt),
-			MaxRows:        uint64(1),
-			DisableBinlogs: false,
-			ReloadSchema:   true,
-		})
+		var err error
+		for attempt := 1; ; attempt++ {
+			_, err = ts.ws.tmClient().ExecuteFetchAsDba(ctx, tablet, true, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+				Query:          []byte(lockStmt),
+				MaxRows:        uint64(1),
+				DisableBinlogs: false,
+				ReloadSchema:   true,
+			})
+			if err == nil || attempt >= maxAttempts || !isRetryableLockTablesError(err) {
+				break
+			}
+			ts.Logger().Warningf("Attempt %d of %d to execute %s on source tablet %v failed, retrying: %v",
+				attempt, maxAttempts, lockStmt, topoproto.TabletAliasString(tablet.Alias), err)
+			select {
+			case <-ctx.Done():
+				return vterrors.Wrapf(ctx.Err(), "failed to execute %s on source tablet %v", lockStmt, topoproto.TabletAliasString(tablet.Alias))
+			case <-time.After(retryDelay):
+			}
+		}
 		if err != nil {
 			ts.Logger().Errorf("Error executing %s on source tablet %v: %v", lockStmt, tablet, err)
 			return err
 		}
-		return err
+		return nil
 	})
 }
 
+// isNoSuchTableError returns true if the given error is the MySQL error
+// for a table that does not exist.
+func isNoSuchTableError(err error) bool {
+	sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	return ok && sqlErr.Number() == sqlerror.ERNoSuchTable
+}
+
+// isRetryableLockTablesError returns true if the given error from a LOCK
+// TABLES statement is a transient one that is worth retrying.
+func isRetryableLockTablesError(err error) bool {
+	sqlErr, ok := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	if !ok {
+		return false
+	}
+	switch sqlErr.Number() {
+	case sqlerror.ERLockDeadlock, sqlerror.ERLockWaitTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
 func (ts *trafficSwitcher) gatherPositions(ctx context.Context) error {
+	if err := ts.gatherSourcePositions(ctx); err != nil {
+		return err
+	}
+	return ts.ForAllTargets(func(target *MigrationTarget) error {
+		var err error
+		target.Position, err = ts.ws.tmClient().PrimaryPosition(ctx, target.GetPrimary().Tablet)
+		ts.Logger().Infof("Position for target %v:%v: %v", ts.TargetKeyspaceName(), target.GetShard().ShardName(), target.Position)
+		return err
+	})
+}
+
+// pinStreamPositions captures the current position of each source shard's
+// primary and sets it as the starting position of all of the workflow's
+// streams from that shard. The streams must not have been started yet.
+func (ts *trafficSwitcher) pinStreamPositions(ctx context.Context) error {
 	err := ts.ForAllSources(func(source *MigrationSource) error {
 		var err error
-		source.Position, err = ts.ws.tmc.PrimaryPosition(ctx, source.GetPrimary().Tablet)
+		source.Position, err = ts.ws.tmClient().PrimaryPosition(ctx, source.GetPrimary().Tablet)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to get the primary position for source shard %s/%s",
+				ts.SourceKeyspaceName(), source.GetShard().ShardName())
+		}
 		ts.Logger().Infof("Position for source %v:%v: %v", ts.SourceKeyspaceName(), source.GetShard().ShardName(), source.Position)
-		return err
+		return nil
 	})
 	if err != nil {
 		return err
 	}
 	return ts.ForAllTargets(func(target *MigrationTarget) error {
-		var err error
-		target.Position, err = ts.ws.tmc.PrimaryPosition(ctx, target.GetPrimary().Tablet)
-		ts.Logger().Infof("Position for target %v:%v: %v", ts.TargetKeyspaceName(), target.GetShard().ShardName(), target.Position)
-		return err
+		for id, bls := range target.Sources {
+			source, ok := ts.Sources()[bls.Shard]
+			if !ok {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "source shard %s/%s for stream %d on target shard %s/%s not found",
+					bls.Keyspace, bls.Shard, id, ts.TargetKeyspaceName(), target.GetShard().ShardName())
+			}
+			query := fmt.Sprintf(sqlSetStreamStartPosition, encodeString(source.Position), id)
+			if _, err := ts.TabletManagerClient().VReplicationExec(ctx, target.GetPrimary().Tablet, query); err != nil {
+				return vterrors.Wrapf(err, "failed to set the starting position for stream %d on target shard %s/%s",
+					id, ts.TargetKeyspaceName(), target.GetShard().ShardName())
+			}
+			ts.Logger().Infof("Pinned stream %d on target %v:%v to the position for source %v:%v: %v", id,
+				ts.TargetKeyspaceName(), target.GetShard().ShardName(), ts.SourceKeyspaceName(), bls.Shard, source.Position)
+		}
+		return nil
 	})
 }
 
+// switchTrafficKeyTag returns the workflow tag used to record that a traffic
+// switch with the given idempotency key has completed.
+func switchTrafficKeyTag(key string) string {
+	return "switch_traffic_key:" + key
+}
+
+// hasSwitchTrafficKey returns true if the given idempotency key was recorded
+// on any of the workflow's target shards by a previous traffic switch.
+func (ts *trafficSwitcher) hasSwitchTrafficKey(ctx context.Context, key string) (bool, error) {
+	tag := switchTrafficKeyTag(key)
+	mu := sync.Mutex{}
+	found := false
+	err := ts.ForAllTargets(func(target *MigrationTarget) error {
+		res, err := ts.TabletManagerClient().ReadVReplicationWorkflow(ctx, target.GetPrimary().Tablet,
+			&tabletmanagerdatapb.ReadVReplicationWorkflowRequest{Workflow: ts.WorkflowName()})
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to read the %s workflow on target shard %s/%s",
+				ts.WorkflowName(), ts.TargetKeyspaceName(), target.GetShard().ShardName())
+		}
+		for _, t := range strings.Split(res.GetTags(), ",") {
+			if t == tag {
+				mu.Lock()
+				found = true
+				mu.Unlock()
+				break
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// recordSwitchTrafficKey adds the given idempotency key to the tags of the
+// workflow's streams on all of the target shards.
+func (ts *trafficSwitcher) recordSwitchTrafficKey(ctx context.Context, key string) error {
+	return ts.ForAllTargets(func(target *MigrationTarget) error {
+		query := fmt.Sprintf(sqlAddWorkflowTag, encodeString(switchTrafficKeyTag(key)),
+			encodeString(target.GetPrimary().DbName()), encodeString(ts.WorkflowName()))
+		if _, err := ts.TabletManagerClient().VReplicationExec(ctx, target.GetPrimary().Tablet, query); err != nil {
+			return vterrors.Wrapf(err, "failed to record the idempotency key on target shard %s/%s",
+				ts.TargetKeyspaceName(), target.GetShard().ShardName())
+		}
+		return nil
+	})
+}
+
+// missingServingTargetShards returns the names of the serving shards in the
+// target keyspace that have no streams for the workflow, e.g. because they
+// were added after the workflow was created. Partial migrations are only
+// expected to have streams on some of the shards so nothing is returned
+// for them. Nor is anything returned for shard migrations, as the source
+// shards are in the target keyspace and serving until writes are switched.
+func (ts *trafficSwitcher) missingServingTargetShards(ctx context.Context) ([]string, error) {
+	if ts.isPartialMigration || ts.MigrationType() == binlogdatapb.MigrationType_SHARDS {
+		return nil, nil
+	}
+	shards, err := ts.TopoServer().FindAllShardsInKeyspace(ctx, ts.TargetKeyspaceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for name, si := range shards {
+		if !si.IsPrimaryServing {
+			continue
+		}
+		if _, ok := ts.targets[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
 func (ts *trafficSwitcher) isSequenceParticipating(ctx context.Context) (bool, error) {
 	vschema, err := ts.TopoServer().GetVSchema(ctx, ts.targetKeyspace)
 	if err != nil {
@@ -1543,6 +1918,79 @@ func (ts *trafficSwitcher) findSequenceUsageInKeyspace(vschema *vschemapb.Keyspa
 	return sequencesByBackingTable, allFullyQualified, nil
 }
 
+// getMaxUsedSequenceValue returns the max value currently used for the
+// sequence's auto-increment column in the using table across all of the
+// target shards.
+func (ts *trafficSwitcher) getMaxUsedSequenceValue(ctx context.Context, sm *sequenceMetadata) (int64, error) {
+	shardResults := make([]int64, 0, len(ts.TargetShards()))
+	srMu := sync.Mutex{}
+	err := ts.ForAllTargets(func(target *MigrationTarget) error {
+		primary := target.GetPrimary()
+		if primary == nil || primary.GetAlias() == nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary tablet found for target shard %s/%s",
+				ts.targetKeyspace, target.GetShard().ShardName())
+		}
+		usingCol, err := sqlescape.EnsureEscaped(sm.usingTableDefinition.AutoIncrement.Column)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid column name %s specified for sequence in table %s: %v",
+				sm.usingTableDefinition.AutoIncrement.Column, sm.usingTableName, err)
+		}
+		usingDB, err := sqlescape.EnsureEscaped(sm.usingTableDBName)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid database name %s specified for sequence in table %s: %v",
+				sm.usingTableDBName, sm.usingTableName, err)
+		}
+		usingTable, err := sqlescape.EnsureEscaped(sm.usingTableName)
+		if err != nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence table name specified for sequence in table %s: %v",
+				sm.usingTableName, err)
+		}
+		query := sqlparser.BuildParsedQuery(sqlGetMaxSequenceVal,
+			usingCol,
+			usingDB,
+			usingTable,
+		)
+		qr, terr := ts.ws.tmClient().ExecuteFetchAsApp(ctx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+			Query:   []byte(query.Query),
+			MaxRows: 1,
+		})
+		if terr != nil || len(qr.Rows) != 1 {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for target table %s.%s on tablet %s in order to initialize the backing sequence table: %v",
+				ts.targetKeyspace, sm.usingTableName, topoproto.TabletAliasString(primary.Alias), terr)
+		}
+		rawVal := sqltypes.Proto3ToResult(qr).Rows[0][0]
+		maxID := int64(0)
+		if !rawVal.IsNull() { // If it's NULL then there are no rows and 0 remains the max
+			maxID, terr = rawVal.ToInt64()
+			if terr != nil {
+				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for target table %s.%s on tablet %s in order to initialize the backing sequence table: %v",
+					ts.targetKeyspace, sm.usingTableName, topoproto.TabletAliasString(primary.Alias), terr)
+			}
+		}
+		srMu.Lock()
+		defer srMu.Unlock()
+		shardResults = append(shardResults, maxID)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+	if len(shardResults) == 0 { // This should never happen
+		return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "did not get any results for the max used sequence value for target table %s.%s in order to initialize the backing sequence table",
+			ts.targetKeyspace, sm.usingTableName)
+	}
+	// Sort the values to find the max value across all shards.
+	sort.Slice(shardResults, func(i, j int) bool {
+		return shardResults[i] < shardResults[j]
+	})
+	return shardResults[len(shardResults)-1], nil
+}
+
 // initializeTargetSequences initializes the backing sequence tables
 // using a map keyed by the backing sequence table name.
 //
@@ -1554,76 +2002,13 @@ func (ts *trafficSwitcher) findSequenceUsageInKeyspace(vschema *vschemapb.Keyspa
 // be sure that it does not provide a value that is less than the current max.
 func (ts *trafficSwitcher) initializeTargetSequences(ctx context.Context, sequencesByBackingTable map[string]*sequenceMetadata) error {
 	initSequenceTable := func(ictx context.Context, sequenceMetadata *sequenceMetadata) error {
-		// Now we need to run this query on the target shards in order
-		// to get the max value and set the next id for the sequence to
-		// a higher value.
-		shardResults := make([]int64, 0, len(ts.TargetShards()))
-		srMu := sync.Mutex{}
-		ierr := ts.ForAllTargets(func(target *MigrationTarget) error {
-			primary := target.GetPrimary()
-			if primary == nil || primary.GetAlias() == nil {
-				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "no primary tablet found for target shard %s/%s",
-					ts.targetKeyspace, target.GetShard().ShardName())
-			}
-			usingCol, err := sqlescape.EnsureEscaped(sequenceMetadata.usingTableDefinition.AutoIncrement.Column)
-			if err != nil {
-				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid column name %s specified for sequence in table %s: %v",
-					sequenceMetadata.usingTableDefinition.AutoIncrement.Column, sequenceMetadata.usingTableName, err)
-			}
-			usingDB, err := sqlescape.EnsureEscaped(sequenceMetadata.usingTableDBName)
-			if err != nil {
-				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid database name %s specified for sequence in table %s: %v",
-					sequenceMetadata.usingTableDBName, sequenceMetadata.usingTableName, err)
-			}
-			usingTable, err := sqlescape.EnsureEscaped(sequenceMetadata.usingTableName)
-			if err != nil {
-				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid sequence table name specified for sequence in table %s: %v",
-					sequenceMetadata.usingTableName, err)
-			}
-			query := sqlparser.BuildParsedQuery(sqlGetMaxSequenceVal,
-				usingCol,
-				usingDB,
-				usingTable,
-			)
-			qr, terr := ts.ws.tmc.ExecuteFetchAsApp(ictx, primary.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
-				Query:   []byte(query.Query),
-				MaxRows: 1,
-			})
-			if terr != nil || len(qr.Rows) != 1 {
-				return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for target table %s.%s on tablet %s in order to initialize the backing sequence table: %v",
-					ts.targetKeyspace, sequenceMetadata.usingTableName, topoproto.TabletAliasString(primary.Alias), terr)
-			}
-			rawVal := sqltypes.Proto3ToResult(qr).Rows[0][0]
-			maxID := int64(0)
-			if !rawVal.IsNull() { // If it's NULL then there are no rows and 0 remains the max
-				maxID, terr = rawVal.ToInt64()
-				if terr != nil {
-					return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "failed to get the max used sequence value for target table %s.%s on tablet %s in order to initialize the backing sequence table: %v",
-						ts.targetKeyspace, sequenceMetadata.usingTableName, topoproto.TabletAliasString(primary.Alias), terr)
-				}
-			}
-			srMu.Lock()
-			defer srMu.Unlock()
-			shardResults = append(shardResults, maxID)
-			return nil
-		})
+		// Get the max value currently used on the target shards so that
+		// we can set the next id for the sequence to a higher value.
+		maxID, ierr := ts.getMaxUsedSequenceValue(ictx, sequenceMetadata)
 		if ierr != nil {
 			return ierr
 		}
-		select {
-		case <-ictx.Done():
-			return ictx.Err()
-		default:
-		}
-		if len(shardResults) == 0 { // This should never happen
-			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "did not get any results for the max used sequence value for target table %s.%s in order to initialize the backing sequence table",
-				ts.targetKeyspace, sequenceMetadata.usingTableName)
-		}
-		// Sort the values to find the max value across all shards.
-		sort.Slice(shardResults, func(i, j int) bool {
-			return shardResults[i] < shardResults[j]
-		})
-		nextVal := shardResults[len(shardResults)-1] + 1
+		nextVal := maxID + 1
 		// Now we need to update the sequence table, if needed, in order to
 		// ensure that that the next value it provides is > the current max.
 		sequenceShard, ierr := ts.TopoServer().GetOnlyShard(ictx, sequenceMetadata.backingTableKeyspace)
@@ -1663,7 +2048,7 @@ func (ts *trafficSwitcher) initializeTargetSequences(ctx context.Context, sequen
 		)
 		// Now execute this on the primary tablet of the unsharded keyspace
 		// housing the backing table.
-		qr, ierr := ts.ws.tmc.ExecuteFetchAsApp(ictx, sequenceTablet.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
+		qr, ierr := ts.ws.tmClient().ExecuteFetchAsApp(ictx, sequenceTablet.Tablet, true, &tabletmanagerdatapb.ExecuteFetchAsAppRequest{
 			Query:   []byte(query.Query),
 			MaxRows: 1,
 		})
@@ -18,17 +18,30 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/proto/vschema"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 type testTrafficSwitcher struct {
@@ -361,3 +374,668 @@ func TestGetTargetSequenceMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteLockTablesOnSource(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	lockQuery := fmt.Sprintf("LOCK TABLES `%s` READ", tableName)
+	deadlockErr := sqlerror.NewSQLError(sqlerror.ERLockDeadlock, sqlerror.SSLockDeadlock, "Deadlock found when trying to get lock")
+
+	testcases := []struct {
+		name        string
+		maxAttempts int // If 0, the default is used.
+		results     []*queryResult
+		wantErr     string
+	}{
+		{
+			name: "retry after a deadlock",
+			results: []*queryResult{
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, result: &querypb.QueryResult{}},
+			},
+		},
+		{
+			name: "give up after the default max attempts",
+			results: []*queryResult{
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, err: deadlockErr},
+			},
+			wantErr: "Deadlock found when trying to get lock",
+		},
+		{
+			name:        "retry up to the configured max attempts",
+			maxAttempts: 5,
+			results: []*queryResult{
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, result: &querypb.QueryResult{}},
+			},
+		},
+		{
+			name:        "give up after the configured max attempts",
+			maxAttempts: 2,
+			results: []*queryResult{
+				{query: lockQuery, err: deadlockErr},
+				{query: lockQuery, err: deadlockErr},
+			},
+			wantErr: "Deadlock found when trying to get lock",
+		},
+		{
+			name:        "no retries with a single attempt",
+			maxAttempts: 1,
+			results: []*queryResult{
+				{query: lockQuery, err: deadlockErr},
+			},
+			wantErr: "Deadlock found when trying to get lock",
+		},
+		{
+			name: "non-retryable error",
+			results: []*queryResult{
+				{query: lockQuery, err: sqlerror.NewSQLError(sqlerror.ERNoSuchTable, sqlerror.SSUnknownTable, "Table 't1' doesn't exist")},
+			},
+			wantErr: "Table 't1' doesn't exist",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				tableName: {
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+						},
+					},
+				},
+			}
+			env.ws = NewServer(vtenv.NewTestEnv(), env.ts, env.tmc, WithLockTablesRetries(tc.maxAttempts, time.Millisecond))
+			ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+
+			for _, res := range tc.results {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, res)
+			}
+			err = ts.executeLockTablesOnSource(ctx)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			// All of the expected attempts were made, and no more.
+			env.tmc.mu.Lock()
+			defer env.tmc.mu.Unlock()
+			require.Empty(t, env.tmc.vrQueries[startingSourceTabletUID])
+		})
+	}
+}
+
+// TestRemoveSourceTablesAlreadyRemoved confirms that a table which was
+// already removed from a source, e.g. by a previous MoveTablesComplete that
+// failed part way through, does not stop the remaining tables from being
+// removed.
+func TestRemoveSourceTablesAlreadyRemoved(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableNames := []string{"t1", "t2", "t3"}
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	for _, tableName := range tableNames {
+		env.tmc.schema[tableName] = &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   tableName,
+					Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+				},
+			},
+		}
+	}
+	ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Len(t, ts.Tables(), len(tableNames))
+
+	dropQuery := fmt.Sprintf("/drop table `vt_%s`.`t[1-3]`", sourceKeyspace.KeyspaceName)
+	env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+		query: dropQuery,
+		err:   sqlerror.NewSQLError(sqlerror.ERNoSuchTable, sqlerror.SSUnknownTable, "Table doesn't exist"),
+	})
+	for range tableNames[1:] {
+		env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+			query:  dropQuery,
+			result: &querypb.QueryResult{},
+		})
+	}
+	require.NoError(t, ts.removeSourceTables(ctx, DropTable, true))
+
+	// Every table was attempted.
+	env.tmc.mu.Lock()
+	defer env.tmc.mu.Unlock()
+	require.Empty(t, env.tmc.vrQueries[startingSourceTabletUID])
+}
+
+// TestRemoveSourceTablesKeepVSchema confirms that the vschema definitions
+// for the workflow's tables are only removed from the source keyspace when
+// they are not being kept.
+func TestRemoveSourceTablesKeepVSchema(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+
+	for _, keepVSchema := range []bool{true, false} {
+		t.Run(fmt.Sprintf("keep vschema %t", keepVSchema), func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema[tableName] = &tabletmanagerdatapb.SchemaDefinition{
+				TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+					{
+						Name:   tableName,
+						Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+					},
+				},
+			}
+			err := env.ts.SaveVSchema(ctx, sourceKeyspace.KeyspaceName, &vschema.Keyspace{
+				Tables: map[string]*vschema.Table{
+					tableName: {},
+					"t2":      {},
+				},
+			})
+			require.NoError(t, err)
+			ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, &queryResult{
+				query:  fmt.Sprintf("drop table `vt_%s`.`%s`", sourceKeyspace.KeyspaceName, tableName),
+				result: &querypb.QueryResult{},
+			})
+			require.NoError(t, ts.removeSourceTables(ctx, DropTable, keepVSchema))
+
+			vs, err := env.ts.GetVSchema(ctx, sourceKeyspace.KeyspaceName)
+			require.NoError(t, err)
+			require.Contains(t, vs.Tables, "t2")
+			if keepVSchema {
+				require.Contains(t, vs.Tables, tableName)
+			} else {
+				require.NotContains(t, vs.Tables, tableName)
+			}
+		})
+	}
+}
+
+// TestMissingServingTargetShards confirms that we report the serving target
+// shards which do not have any streams for the workflow.
+func TestMissingServingTargetShards(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-40", "40-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema["t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   "t1",
+				Schema: "CREATE TABLE t1 (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))",
+			},
+		},
+	}
+	ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+
+	missing, err := ts.missingServingTargetShards(ctx)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	// Simulate shards that were added after the workflow was created.
+	delete(ts.targets, "40-80")
+	delete(ts.targets, "80-")
+	missing, err = ts.missingServingTargetShards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"40-80", "80-"}, missing)
+
+	// Shards that are not serving are not expected to have streams.
+	_, err = env.ts.UpdateShardFields(ctx, targetKeyspace.KeyspaceName, "80-", func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+	missing, err = ts.missingServingTargetShards(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"40-80"}, missing)
+
+	// Partial migrations only have streams on some of the shards.
+	ts.isPartialMigration = true
+	missing, err = ts.missingServingTargetShards(ctx)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+// TestMissingServingTargetShardsReshard confirms that the source shards of a
+// Reshard workflow, which are in the target keyspace and serving until
+// writes are switched, are not reported as missing streams.
+func TestMissingServingTargetShardsReshard(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	keyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	targetShards := &testKeyspace{
+		KeyspaceName: keyspace.KeyspaceName,
+		ShardNames:   []string{"-80", "80-"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, keyspace, targetShards)
+	defer env.close()
+	env.tmc.schema["t1"] = &tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   "t1",
+				Schema: "CREATE TABLE t1 (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))",
+			},
+		},
+	}
+	// The source shard has no streams for the workflow.
+	env.tmc.readVReplicationWorkflowResponses[startingSourceTabletUID] = &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+		Workflow:     workflowName,
+		WorkflowType: binlogdatapb.VReplicationWorkflowType_Reshard,
+	}
+	ts, _, err := env.ws.getWorkflowState(ctx, keyspace.KeyspaceName, workflowName)
+	require.NoError(t, err)
+	require.Equal(t, binlogdatapb.MigrationType_SHARDS, ts.MigrationType())
+	si, err := env.ts.GetShard(ctx, keyspace.KeyspaceName, "0")
+	require.NoError(t, err)
+	require.True(t, si.IsPrimaryServing)
+
+	missing, err := ts.missingServingTargetShards(ctx)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+// TestGatherSourcePositions confirms that we record the position of every
+// source shard's primary.
+func TestGatherSourcePositions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"-40", "40-80", "80-c0", "c0-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		"t1": {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   "t1",
+					Schema: "CREATE TABLE t1 (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))",
+				},
+			},
+		},
+	}
+	ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, "wf1")
+	require.NoError(t, err)
+	require.Len(t, ts.Sources(), len(sourceKeyspace.ShardNames))
+
+	require.NoError(t, ts.gatherSourcePositions(ctx))
+	for shard, source := range ts.Sources() {
+		require.Equal(t, position, source.Position, "unexpected position for source shard %s", shard)
+	}
+}
+
+// TestDropSourceShards confirms that every source shard is deleted even when
+// deleting some of them fails, and that the errors for all of the shards
+// that could not be deleted are returned.
+func TestDropSourceShards(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"-40", "40-80", "80-c0", "c0-"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+	defer env.close()
+	env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+		"t1": {
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   "t1",
+					Schema: "CREATE TABLE t1 (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))",
+				},
+			},
+		},
+	}
+	ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, "wf1")
+	require.NoError(t, err)
+	require.Len(t, ts.Sources(), len(sourceKeyspace.ShardNames))
+
+	// Shards that are still serving cannot be deleted.
+	servingShards := []string{"-40", "80-c0"}
+	partition := &topodatapb.SrvKeyspace_KeyspacePartition{ServedType: topodatapb.TabletType_PRIMARY}
+	for _, shard := range servingShards {
+		partition.ShardReferences = append(partition.ShardReferences, &topodatapb.ShardReference{Name: shard})
+	}
+	err = env.ts.UpdateSrvKeyspace(ctx, defaultCellName, sourceKeyspace.KeyspaceName, &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{partition},
+	})
+	require.NoError(t, err)
+
+	err = ts.dropSourceShards(ctx)
+	require.Error(t, err)
+	for _, shard := range sourceKeyspace.ShardNames {
+		_, gerr := env.ts.GetShard(ctx, sourceKeyspace.KeyspaceName, shard)
+		if slices.Contains(servingShards, shard) {
+			require.NoError(t, gerr)
+			require.ErrorContains(t, err, fmt.Sprintf("shard %s/%s is still serving", sourceKeyspace.KeyspaceName, shard))
+		} else {
+			require.True(t, topo.IsErrType(gerr, topo.NoNode), "source shard %s was not deleted: %v", shard, gerr)
+		}
+	}
+}
+
+// drainCheckingTMClient records, for each in-flight transaction count query,
+// whether the source shard's tables were already denied when it ran.
+type drainCheckingTMClient struct {
+	*testTMClient
+	ts       *topo.Server
+	keyspace string
+	table    string
+
+	mu           sync.Mutex
+	countQueries int
+	undenied     int
+}
+
+func (tmc *drainCheckingTMClient) ExecuteFetchAsDba(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsDbaRequest) (*querypb.QueryResult, error) {
+	if strings.Contains(string(req.Query), "information_schema.innodb_trx") {
+		si, err := tmc.ts.GetShard(ctx, tmc.keyspace, tablet.Shard)
+		if err != nil {
+			return nil, err
+		}
+		tmc.mu.Lock()
+		tmc.countQueries++
+		if tc := si.GetTabletControl(topodatapb.TabletType_PRIMARY); tc == nil || !slices.Contains(tc.DeniedTables, tmc.table) {
+			tmc.undenied++
+		}
+		tmc.mu.Unlock()
+	}
+	return tmc.testTMClient.ExecuteFetchAsDba(ctx, tablet, usePool, req)
+}
+
+// TestDrainSourceWrites confirms that stopSourceWrites only counts the
+// in-flight transactions in the source database once writes have been denied
+// on the source, and that it carries on when they do not drain in time.
+func TestDrainSourceWrites(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"0"},
+	}
+	countQuery := fmt.Sprintf(sqlGetActiveTransactionCount, encodeString("vt_"+sourceKeyspace.KeyspaceName))
+	countResult := func(count string) *querypb.QueryResult {
+		return sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("count(*)", "int64"), count))
+	}
+
+	testcases := []struct {
+		name         string
+		drainTimeout time.Duration
+		results      []*queryResult
+		wantQueries  int
+		wantErr      string
+	}{
+		{
+			name:         "drained",
+			drainTimeout: 10 * time.Second,
+			results: []*queryResult{
+				{query: countQuery, result: countResult("2")},
+				{query: countQuery, result: countResult("0")},
+			},
+			wantQueries: 2,
+		},
+		{
+			name:         "continue after the timeout",
+			drainTimeout: 10 * time.Millisecond,
+			results: []*queryResult{
+				{query: countQuery, result: countResult("1")},
+			},
+			wantQueries: 1,
+		},
+		{
+			name:         "query error",
+			drainTimeout: 10 * time.Second,
+			results: []*queryResult{
+				{query: countQuery, err: errors.New("access denied")},
+			},
+			wantQueries: 1,
+			wantErr:     "access denied",
+		},
+		{
+			name: "no drain timeout",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				tableName: {
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+						},
+					},
+				},
+			}
+			tmc := &drainCheckingTMClient{
+				testTMClient: env.tmc,
+				ts:           env.ts,
+				keyspace:     sourceKeyspace.KeyspaceName,
+				table:        tableName,
+			}
+			env.ws = NewServer(vtenv.NewTestEnv(), env.ts, env.tmc, WithTMCFactory(func() tmclient.TabletManagerClient { return tmc }))
+			ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+			require.Equal(t, binlogdatapb.MigrationType_TABLES, ts.MigrationType())
+
+			for _, res := range tc.results {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, res)
+			}
+			lockCtx, sourceUnlock, err := env.ts.LockKeyspace(ctx, sourceKeyspace.KeyspaceName, "test")
+			require.NoError(t, err)
+			defer sourceUnlock(&err)
+			lockCtx, targetUnlock, err := env.ts.LockKeyspace(lockCtx, targetKeyspace.KeyspaceName, "test")
+			require.NoError(t, err)
+			defer targetUnlock(&err)
+
+			err = ts.stopSourceWrites(lockCtx, tc.drainTimeout)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				for shard, source := range ts.Sources() {
+					require.Equal(t, position, source.Position, "unexpected position for source shard %s", shard)
+				}
+			}
+			tmc.mu.Lock()
+			require.Equal(t, tc.wantQueries, tmc.countQueries)
+			require.Zero(t, tmc.undenied, "in-flight transactions were counted before writes were denied")
+			tmc.mu.Unlock()
+			env.tmc.mu.Lock()
+			defer env.tmc.mu.Unlock()
+			require.Empty(t, env.tmc.vrQueries[startingSourceTabletUID])
+		})
+	}
+}
+
+// TestValidateReverseReplication confirms that reverse replication is only
+// reported as possible, for both dry and real runs, when the source primaries
+// can insert into the vreplication table and the target primaries write the
+// binary logs that the reverse streams need.
+func TestValidateReverseReplication(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	workflowName := "wf1"
+	tableName := "t1"
+	sourceKeyspace := &testKeyspace{
+		KeyspaceName: "sourceks",
+		ShardNames:   []string{"0"},
+	}
+	targetKeyspace := &testKeyspace{
+		KeyspaceName: "targetks",
+		ShardNames:   []string{"-80", "80-"},
+	}
+	privilegeQR := func(canInsert string) *queryResult {
+		return &queryResult{
+			query:  "/select count.* > 0 as can_insert from .*information_schema.user_privileges.*'_vt' like table_schema.*table_name = 'vreplication'",
+			result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(sqltypes.MakeTestFields("can_insert", "int64"), canInsert)),
+		}
+	}
+	binlogSettingsQR := func(settings string) *queryResult {
+		return &queryResult{
+			query: sqlGetBinlogSettings,
+			result: sqltypes.ResultToProto3(sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("log_bin|binlog_format|binlog_row_image|gtid_mode", "int64|varchar|varchar|varchar"), settings)),
+		}
+	}
+
+	testcases := []struct {
+		name           string
+		dryRun         bool
+		canInsert      string
+		binlogSettings string
+		wantErr        string
+	}{
+		{
+			name:           "dry run",
+			dryRun:         true,
+			canInsert:      "1",
+			binlogSettings: "1|ROW|FULL|ON",
+		},
+		{
+			name:           "real run",
+			canInsert:      "1",
+			binlogSettings: "1|ROW|NOBLOB|ON",
+		},
+		{
+			name:      "dry run without the insert privilege on the source",
+			dryRun:    true,
+			canInsert: "0",
+			wantErr:   "the primary tablet cell-0000000100 in shard sourceks/0 cannot insert into the _vt.vreplication table",
+		},
+		{
+			name:      "real run without the insert privilege on the source",
+			canInsert: "0",
+			wantErr:   "the primary tablet cell-0000000100 in shard sourceks/0 cannot insert into the _vt.vreplication table",
+		},
+		{
+			name:           "target without row based binary logs with GTIDs",
+			canInsert:      "1",
+			binlogSettings: "1|STATEMENT|MINIMAL|OFF",
+			wantErr:        "the reverse workflow cannot stream from the primary tablet cell-0000000200 in shard targetks/-80: binlog_format is STATEMENT rather than ROW, binlog_row_image is MINIMAL, gtid_mode is OFF rather than ON",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t, ctx, defaultCellName, sourceKeyspace, targetKeyspace)
+			defer env.close()
+			env.tmc.schema = map[string]*tabletmanagerdatapb.SchemaDefinition{
+				tableName: {
+					TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+						{
+							Name:   tableName,
+							Schema: fmt.Sprintf("CREATE TABLE %s (id BIGINT, name VARCHAR(64), PRIMARY KEY (id))", tableName),
+						},
+					},
+				},
+			}
+			ts, _, err := env.ws.getWorkflowState(ctx, targetKeyspace.KeyspaceName, workflowName)
+			require.NoError(t, err)
+			env.tmc.expectVRQueryResultOnKeyspaceTablets(sourceKeyspace.KeyspaceName, privilegeQR(tc.canInsert))
+			if tc.binlogSettings != "" {
+				env.tmc.expectVRQueryResultOnKeyspaceTablets(targetKeyspace.KeyspaceName, binlogSettingsQR(tc.binlogSettings))
+			}
+
+			var sw iswitcher = &switcher{ts: ts, s: env.ws}
+			if tc.dryRun {
+				sw = &switcherDryRun{ts: ts, drLog: NewLogRecorder()}
+			}
+			err = sw.validateReverseReplication(ctx)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				if tc.dryRun {
+					require.Empty(t, sw.logs().GetLogs())
+				}
+				return
+			}
+			require.NoError(t, err)
+			if tc.dryRun {
+				require.Equal(t, []string{"Reverse replication can be created: tablets are available to stream from in keyspace targetks, " +
+					"whose primary tablets write row based binary logs with GTIDs, and the primary tablets in keyspace sourceks can insert into the vreplication table"},
+					sw.logs().GetLogs())
+			}
+		})
+	}
+}
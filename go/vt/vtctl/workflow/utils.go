@@ -23,16 +23,20 @@ import (
 	"fmt"
 	"hash/fnv"
 	"math"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
+	"golang.org/x/exp/maps"
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/sqltypes"
@@ -51,6 +55,7 @@ import (
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
@@ -89,14 +94,57 @@ func getTablesInKeyspace(ctx context.Context, ts *topo.Server, tmc tmclient.Tabl
 	return sourceTables, nil
 }
 
+// getTablesWithoutPrimaryKey returns the subset of the given tables which do
+// not have a primary key defined, based on the schema of the first serving
+// shard's primary tablet in the keyspace.
+func getTablesWithoutPrimaryKey(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, keyspace string, tables []string) ([]string, error) {
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	shards, err := ts.GetServingShards(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("keyspace %s has no shards", keyspace)
+	}
+	primary := shards[0].PrimaryAlias
+	if primary == nil {
+		return nil, fmt.Errorf("shard does not have a primary: %v", shards[0].ShardName())
+	}
+	ti, err := ts.GetTablet(ctx, primary)
+	if err != nil {
+		return nil, err
+	}
+	req := &tabletmanagerdatapb.GetSchemaRequest{Tables: tables}
+	schema, err := tmc.GetSchema(ctx, ti.Tablet, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var noPKTables []string
+	for _, td := range schema.TableDefinitions {
+		if len(td.PrimaryKeyColumns) == 0 {
+			noPKTables = append(noPKTables, td.Name)
+		}
+	}
+	sort.Strings(noPKTables)
+	return noPKTables, nil
+}
+
 // validateNewWorkflow ensures that the specified workflow doesn't already exist
-// in the keyspace.
+// in the keyspace. If it does, then the returned error wraps
+// ErrWorkflowAlreadyExists.
 func validateNewWorkflow(ctx context.Context, ts *topo.Server, tmc tmclient.TabletManagerClient, keyspace, workflow string) error {
 	allshards, err := ts.FindAllShardsInKeyspace(ctx, keyspace, nil)
 	if err != nil {
 		return err
 	}
-	var wg sync.WaitGroup
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		existsErr error
+	)
 	allErrors := &concurrency.AllErrorRecorder{}
 	for _, si := range allshards {
 		if si.PrimaryAlias == nil {
@@ -123,16 +171,192 @@ func validateNewWorkflow(ctx context.Context, ts *topo.Server, tmc tmclient.Tabl
 			}
 			for _, wf := range res.Workflows {
 				if wf.Workflow == workflow {
-					allErrors.RecordError(fmt.Errorf("workflow %s already exists in keyspace %s on tablet %v", workflow, keyspace, primary.Alias))
+					mu.Lock()
+					defer mu.Unlock()
+					existsErr = fmt.Errorf("%w: workflow %s already exists in keyspace %s on tablet %v",
+						ErrWorkflowAlreadyExists, workflow, keyspace, topoproto.TabletAliasString(primary.Alias))
 					return
 				}
 			}
 		}(si)
 	}
 	wg.Wait()
+	if existsErr != nil {
+		return existsErr
+	}
 	return allErrors.AggrError(vterrors.Aggregate)
 }
 
+// diffShardRoutingRules returns the sorted list of changes needed to go from
+// the old to the new shard routing rules, with each rule prefixed by a '-'
+// when it is removed and a '+' when it is added.
+func diffShardRoutingRules(oldRules, newRules map[string]string) []string {
+	var changes []string
+	for from, to := range oldRules {
+		if newRules[from] != to {
+			changes = append(changes, fmt.Sprintf("- %s => %s", from, to))
+		}
+	}
+	for from, to := range newRules {
+		if oldRules[from] != to {
+			changes = append(changes, fmt.Sprintf("+ %s => %s", from, to))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		// Order by the rule, then removals before additions.
+		if changes[i][2:] != changes[j][2:] {
+			return changes[i][2:] < changes[j][2:]
+		}
+		return changes[i][0] == '-'
+	})
+	return changes
+}
+
+// diffRoutingRules returns the sorted list of changes needed to go from the
+// old to the new table routing rules, in the same format as
+// diffShardRoutingRules.
+func diffRoutingRules(oldRules, newRules map[string][]string) []string {
+	oldStrs := make(map[string]string, len(oldRules))
+	for from, to := range oldRules {
+		oldStrs[from] = strings.Join(to, ",")
+	}
+	newStrs := make(map[string]string, len(newRules))
+	for from, to := range newRules {
+		newStrs[from] = strings.Join(to, ",")
+	}
+	return diffShardRoutingRules(oldStrs, newStrs)
+}
+
+// routeTablesToSource adds the table routing rules that route the given
+// tables -- whether unqualified or qualified by the source or target
+// keyspace -- to the source keyspace, for every tablet type. These are the
+// rules that are in place for a MoveTables workflow before any traffic
+// has been switched.
+func routeTablesToSource(rules map[string][]string, sourceKeyspace, targetKeyspace string, tables []string) {
+	for _, table := range tables {
+		route := fmt.Sprintf("%s.%s", sourceKeyspace, table)
+		for _, ks := range []string{globalTableQualifier, targetKeyspace, sourceKeyspace} {
+			key := table
+			if ks != "" {
+				key = fmt.Sprintf("%s.%s", ks, table)
+			}
+			for _, typ := range tabletTypeSuffixes {
+				rules[key+typ] = []string{route}
+			}
+		}
+	}
+}
+
+// compareKeyRangeEndToStart compares the End of KeyRange a to the Start of
+// KeyRange b, using the semantics where an empty End is the maximum value and
+// an empty Start the minimum. It returns 0 if the two are contiguous, -1 if
+// there is a gap between them, and 1 if they overlap.
+func compareKeyRangeEndToStart(a, b *topodatapb.KeyRange) int {
+	if key.Empty(a.GetEnd()) || key.Empty(b.GetStart()) {
+		return 1
+	}
+	return key.Compare(a.GetEnd(), b.GetStart())
+}
+
+// validateKeyspaceShards returns an error naming any of the given shards
+// which do not exist in the keyspace. All of the keyspace's shards are
+// accepted, not only the serving ones, as the target shards of a Reshard
+// workflow do not serve until writes have been switched.
+func validateKeyspaceShards(ctx context.Context, ts *topo.Server, keyspace string, shards []string) error {
+	shardNames, err := ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	sort.Strings(shardNames)
+	var unknown []string
+	for _, shard := range shards {
+		if !slices.Contains(shardNames, shard) {
+			unknown = append(unknown, shard)
+		}
+	}
+	if len(unknown) > 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown shard(s) %s in keyspace %s, the shards are: %s",
+			strings.Join(unknown, ", "), keyspace, strings.Join(shardNames, ", "))
+	}
+	return nil
+}
+
+// validateKeyRangeCover confirms that the target shards form a contiguous,
+// non-overlapping cover of the key range of the source shards, which must be
+// contiguous themselves. The returned error describes every gap and overlap
+// that was found.
+func validateKeyRangeCover(sources, targets []*topo.ShardInfo) error {
+	if len(sources) == 0 || len(targets) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "at least one source and one target shard must be specified")
+	}
+	byKeyRange := func(a, b *topo.ShardInfo) int {
+		return key.KeyRangeCompare(a.GetKeyRange(), b.GetKeyRange())
+	}
+	sources = slices.Clone(sources)
+	slices.SortFunc(sources, byKeyRange)
+	targets = slices.Clone(targets)
+	slices.SortFunc(targets, byKeyRange)
+
+	var problems []string
+	for i := 1; i < len(sources); i++ {
+		if compareKeyRangeEndToStart(sources[i-1].GetKeyRange(), sources[i].GetKeyRange()) != 0 {
+			problems = append(problems, fmt.Sprintf("source shards %s and %s are not contiguous",
+				sources[i-1].ShardName(), sources[i].ShardName()))
+		}
+	}
+	if len(problems) > 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid source shards: %s", strings.Join(problems, "; "))
+	}
+	cover := &topodatapb.KeyRange{
+		Start: sources[0].GetKeyRange().GetStart(),
+		End:   sources[len(sources)-1].GetKeyRange().GetEnd(),
+	}
+
+	first := targets[0]
+	switch key.KeyRangeStartCompare(first.GetKeyRange(), cover) {
+	case -1:
+		problems = append(problems, fmt.Sprintf("target shard %s starts before the source key range %s",
+			first.ShardName(), key.KeyRangeString(cover)))
+	case 1:
+		problems = append(problems, fmt.Sprintf("key range %s is not covered by any target shard",
+			key.KeyRangeString(&topodatapb.KeyRange{Start: cover.GetStart(), End: first.GetKeyRange().GetStart()})))
+	}
+	// reach is the target shard that extends the furthest of those seen so
+	// far, which is where the next target shard needs to start.
+	reach := first
+	for _, target := range targets[1:] {
+		kr := target.GetKeyRange()
+		switch compareKeyRangeEndToStart(reach.GetKeyRange(), kr) {
+		case -1:
+			problems = append(problems, fmt.Sprintf("key range %s is not covered by any target shard",
+				key.KeyRangeString(&topodatapb.KeyRange{Start: reach.GetKeyRange().GetEnd(), End: kr.GetStart()})))
+		case 1:
+			overlapEnd := kr.GetEnd()
+			if key.KeyRangeEndCompare(reach.GetKeyRange(), kr) < 0 {
+				overlapEnd = reach.GetKeyRange().GetEnd()
+			}
+			problems = append(problems, fmt.Sprintf("target shards %s and %s overlap on key range %s",
+				reach.ShardName(), target.ShardName(), key.KeyRangeString(&topodatapb.KeyRange{Start: kr.GetStart(), End: overlapEnd})))
+		}
+		if key.KeyRangeEndCompare(kr, reach.GetKeyRange()) > 0 {
+			reach = target
+		}
+	}
+	switch key.KeyRangeEndCompare(reach.GetKeyRange(), cover) {
+	case -1:
+		problems = append(problems, fmt.Sprintf("key range %s is not covered by any target shard",
+			key.KeyRangeString(&topodatapb.KeyRange{Start: reach.GetKeyRange().GetEnd(), End: cover.GetEnd()})))
+	case 1:
+		problems = append(problems, fmt.Sprintf("target shard %s ends after the source key range %s",
+			reach.ShardName(), key.KeyRangeString(cover)))
+	}
+	if len(problems) > 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the target shards do not exactly cover the source key range %s: %s",
+			key.KeyRangeString(cover), strings.Join(problems, "; "))
+	}
+	return nil
+}
+
 // createDefaultShardRoutingRules creates a reverse routing rule for
 // each shard in a new partial keyspace migration workflow that does
 // not already have an existing routing rule in place.
@@ -554,7 +778,7 @@ func doValidateWorkflowHasCompleted(ctx context.Context, ts *trafficSwitcher) er
 		_ = ts.ForAllTargets(func(target *MigrationTarget) error {
 			wg.Add(1)
 			defer wg.Done()
-			res, err := ts.ws.tmc.ReadVReplicationWorkflow(ctx, target.GetPrimary().Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+			res, err := ts.ws.tmClient().ReadVReplicationWorkflow(ctx, target.GetPrimary().Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
 				Workflow: ts.WorkflowName(),
 			})
 			if err != nil {
@@ -618,6 +842,12 @@ func encodeString(in string) string {
 	return buf.String()
 }
 
+// isRowCountExceededError returns true if the error is the one returned by
+// ExecuteFetch when a query returns more rows than the requested maximum.
+func isRowCountExceededError(err error) bool {
+	return vterrors.Code(err) == vtrpcpb.Code_ABORTED && strings.Contains(err.Error(), "Row count exceeded")
+}
+
 func getRenameFileName(tableName string) string {
 	return fmt.Sprintf(renameTableTemplate, tableName)
 }
@@ -638,6 +868,20 @@ func parseTabletTypes(tabletTypes []topodatapb.TabletType) (hasReplica, hasRdonl
 	return hasReplica, hasRdonly, hasPrimary, nil
 }
 
+// defaultTabletTypes are the tablet types that a vreplication stream uses,
+// in order, when none are configured for it. This matches the default value
+// of vttablet's --vreplication_tablet_type flag.
+var defaultTabletTypes = []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY}
+
+// effectiveTabletTypes returns the tablet types and selection preference
+// that a vreplication stream with the given configuration will use.
+func effectiveTabletTypes(tabletTypes []topodatapb.TabletType, tsp tabletmanagerdatapb.TabletSelectionPreference) ([]topodatapb.TabletType, tabletmanagerdatapb.TabletSelectionPreference) {
+	if len(tabletTypes) == 0 {
+		return slices.Clone(defaultTabletTypes), tabletmanagerdatapb.TabletSelectionPreference_INORDER
+	}
+	return tabletTypes, tsp
+}
+
 func areTabletsAvailableToStreamFrom(ctx context.Context, req *vtctldatapb.WorkflowSwitchTrafficRequest, ts *trafficSwitcher, keyspace string, shards []*topo.ShardInfo) error {
 	// We use the value from the workflow for the TabletPicker.
 	tabletTypesStr := ts.optTabletTypes
@@ -949,3 +1193,251 @@ func getTabletTypeSuffix(tabletType topodatapb.TabletType) string {
 	}
 	return ""
 }
+
+// getStreamThrottledState returns whether or not the given stream is currently
+// being throttled, along with a description of why. When a vreplication
+// component is throttled it records the time in both time_throttled and
+// time_updated, so if the stream has not been updated since it was last
+// throttled then it's still being held back by the throttler.
+func getStreamThrottledState(st *vtctldatapb.Workflow_Stream) (bool, string) {
+	ts := st.GetThrottlerStatus()
+	if ts.GetComponentThrottled() == "" || ts.GetTimeThrottled().GetSeconds() == 0 {
+		return false, ""
+	}
+	if ts.GetTimeThrottled().GetSeconds() < st.GetTimeUpdated().GetSeconds() {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s throttled at %s", ts.GetComponentThrottled(),
+		time.Unix(ts.GetTimeThrottled().GetSeconds(), 0).UTC().Format(time.RFC3339))
+}
+
+// copyRemaining returns how much of a table is still to be copied, and the
+// percentage that has been copied, given the amount copied so far and the
+// (estimated) total. As the total is an estimate it can be lower than what
+// has been copied, in which case nothing remains and the table is 100%
+// copied.
+func copyRemaining(copied, total int64) (int64, float32) {
+	switch {
+	case copied < total:
+		return total - copied, float32(100.0 * float64(max(copied, 0)) / float64(total))
+	case copied > 0:
+		return 0, 100
+	default: // Nothing to copy and nothing copied.
+		return 0, 0
+	}
+}
+
+// filterWorkflowStreamsByState removes the streams from the workflow that are
+// not in one of the given states, along with any shard streams that are left
+// with no streams.
+func filterWorkflowStreamsByState(workflow *vtctldatapb.Workflow, states []binlogdatapb.VReplicationWorkflowState) {
+	for key, shardStream := range workflow.ShardStreams {
+		shardStream.Streams = slices.DeleteFunc(shardStream.Streams, func(stream *vtctldatapb.Workflow_Stream) bool {
+			return !slices.ContainsFunc(states, func(state binlogdatapb.VReplicationWorkflowState) bool {
+				return stream.State == state.String()
+			})
+		})
+		if len(shardStream.Streams) == 0 {
+			delete(workflow.ShardStreams, key)
+		}
+	}
+}
+
+// workflowStateSeverity orders the stream states used when summarizing a
+// workflow, from the most to the least severe.
+var workflowStateSeverity = []string{
+	binlogdatapb.VReplicationWorkflowState_Error.String(),
+	binlogdatapb.VReplicationWorkflowState_Copying.String(),
+	binlogdatapb.VReplicationWorkflowState_Lagging.String(),
+	binlogdatapb.VReplicationWorkflowState_Stopped.String(),
+	binlogdatapb.VReplicationWorkflowState_Running.String(),
+}
+
+// summarizeWorkflow builds the aggregate summary for the given workflow from
+// its shard streams. The workflow's max vreplication lag must already have
+// been computed.
+func summarizeWorkflow(workflow *vtctldatapb.Workflow) *vtctldatapb.Workflow_Summary {
+	summary := &vtctldatapb.Workflow_Summary{
+		WorkflowType:       workflow.WorkflowType,
+		MaxVReplicationLag: workflow.MaxVReplicationLag,
+	}
+	states := sets.New[string]()
+	copied := 0
+	for _, shardStream := range workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			summary.StreamCount++
+			states.Insert(stream.State)
+			if stream.State != binlogdatapb.VReplicationWorkflowState_Copying.String() && len(stream.CopyStates) == 0 {
+				copied++
+			}
+		}
+	}
+	if summary.StreamCount > 0 {
+		summary.CopyPercentage = float32(copied) * 100 / float32(summary.StreamCount)
+	}
+	if states.Len() > 0 {
+		summary.State = sets.List(states)[0]
+	}
+	if states.Len() > 1 {
+		if i := slices.IndexFunc(workflowStateSeverity, states.Has); i >= 0 {
+			summary.State = workflowStateSeverity[i]
+		}
+	}
+	return summary
+}
+
+// validateSrvVSchemaRoutingRules confirms that the routing rules in the
+// SrvVSchema for each of the given cells, or all cells if none are given,
+// match the global routing rules.
+func validateSrvVSchemaRoutingRules(ctx context.Context, ts *topo.Server, cells []string) error {
+	if len(cells) == 0 {
+		var err error
+		cells, err = ts.GetCellInfoNames(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	rules, err := ts.GetRoutingRules(ctx)
+	if err != nil {
+		return err
+	}
+	if rules == nil {
+		rules = &vschemapb.RoutingRules{}
+	}
+	for _, cell := range cells {
+		srvVSchema, err := ts.GetSrvVSchema(ctx, cell)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to get the SrvVSchema in cell %s", cell)
+		}
+		srvRules := srvVSchema.GetRoutingRules()
+		if srvRules == nil {
+			srvRules = &vschemapb.RoutingRules{}
+		}
+		if !proto.Equal(rules, srvRules) {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "the routing rules in the SrvVSchema in cell %s do not match the global routing rules", cell)
+		}
+	}
+	return nil
+}
+
+// workflowSequences converts the sequence metadata, keyed by backing table
+// name, into a list of WorkflowSequences sorted by the using table's name.
+func workflowSequences(sequenceMetadata map[string]*sequenceMetadata) []*WorkflowSequence {
+	sequences := make([]*WorkflowSequence, 0, len(sequenceMetadata))
+	for _, sm := range sequenceMetadata {
+		sequences = append(sequences, &WorkflowSequence{
+			Table:           sm.usingTableName,
+			Column:          sm.usingTableDefinition.GetAutoIncrement().GetColumn(),
+			BackingTable:    sm.backingTableName,
+			BackingKeyspace: sm.backingTableKeyspace,
+		})
+	}
+	sort.Slice(sequences, func(i, j int) bool {
+		return sequences[i].Table < sequences[j].Table
+	})
+	return sequences
+}
+
+// workflowDefinition builds the MaterializeSettings that can be used to
+// recreate the given Materialize workflow. The in_keyrange filters that were
+// added to the table rules for each target shard when the workflow was
+// created are removed, so that they are generated again for the target
+// shards when the workflow is recreated.
+func workflowDefinition(parser *sqlparser.Parser, wf *vtctldatapb.Workflow) (*vtctldatapb.MaterializeSettings, error) {
+	if wf.GetWorkflowType() != binlogdatapb.VReplicationWorkflowType_Materialize.String() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only Materialize workflows can be exported, %s is a %s workflow",
+			wf.GetName(), wf.GetWorkflowType())
+	}
+	targetKeyspace := wf.GetTarget().GetKeyspace()
+	var bls *binlogdatapb.BinlogSource
+	tableSettings := make(map[string]*vtctldatapb.TableMaterializeSettings)
+	for _, shardStream := range wf.GetShardStreams() {
+		for _, stream := range shardStream.GetStreams() {
+			if stream.GetBinlogSource().GetFilter() == nil {
+				continue
+			}
+			if bls == nil {
+				bls = stream.BinlogSource
+			}
+			for _, rule := range stream.BinlogSource.Filter.Rules {
+				if _, ok := tableSettings[rule.Match]; ok {
+					continue
+				}
+				sourceExpression, err := removeTargetKeyRangeFilter(parser, targetKeyspace, rule.Filter)
+				if err != nil {
+					return nil, err
+				}
+				tableSettings[rule.Match] = &vtctldatapb.TableMaterializeSettings{
+					TargetTable:      rule.Match,
+					SourceExpression: sourceExpression,
+					CreateDdl:        createDDLAsCopy,
+				}
+			}
+		}
+	}
+	if bls == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no streams found for workflow %s in keyspace %s",
+			wf.GetName(), targetKeyspace)
+	}
+	tables := maps.Keys(tableSettings)
+	sort.Strings(tables)
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:                  wf.GetName(),
+		SourceKeyspace:            wf.GetSource().GetKeyspace(),
+		TargetKeyspace:            targetKeyspace,
+		StopAfterCopy:             bls.StopAfterCopy,
+		Cell:                      strings.Join(wf.GetCells(), ","),
+		TabletTypes:               topoproto.MakeStringTypeCSV(wf.GetTabletTypes()),
+		ExternalCluster:           bls.ExternalCluster,
+		MaterializationIntent:     vtctldatapb.MaterializationIntent_CUSTOM,
+		SourceTimeZone:            bls.SourceTimeZone,
+		TargetTimeZone:            bls.TargetTimeZone,
+		OnDdl:                     bls.OnDdl.String(),
+		DeferSecondaryKeys:        wf.GetDeferSecondaryKeys(),
+		TabletSelectionPreference: wf.GetTabletSelectionPreference(),
+		WorkflowOptions:           wf.GetOptions(),
+	}
+	for _, table := range tables {
+		ms.TableSettings = append(ms.TableSettings, tableSettings[table])
+	}
+	return ms, nil
+}
+
+// removeTargetKeyRangeFilter removes the in_keyrange filters using a vindex
+// in the target keyspace from the given source expression.
+func removeTargetKeyRangeFilter(parser *sqlparser.Parser, targetKeyspace, filter string) (string, error) {
+	if filter == "" {
+		return "", nil
+	}
+	stmt, err := parser.Parse(filter)
+	if err != nil {
+		return "", err
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Where == nil {
+		return filter, nil
+	}
+	var exprs []sqlparser.Expr
+	for _, expr := range sqlparser.SplitAndExpression(nil, sel.Where.Expr) {
+		if !isTargetKeyRangeFilter(targetKeyspace, expr) {
+			exprs = append(exprs, expr)
+		}
+	}
+	sel.Where = nil
+	if len(exprs) > 0 {
+		sel.Where = sqlparser.NewWhere(sqlparser.WhereClause, sqlparser.AndExpressions(exprs...))
+	}
+	return sqlparser.String(sel), nil
+}
+
+// isTargetKeyRangeFilter returns true if the expression is an in_keyrange
+// filter, like those added by the materializer for sharded targets, that
+// uses a vindex in the target keyspace.
+func isTargetKeyRangeFilter(targetKeyspace string, expr sqlparser.Expr) bool {
+	fn, ok := expr.(*sqlparser.FuncExpr)
+	if !ok || !fn.Name.EqualString("in_keyrange") || len(fn.Exprs) < 3 {
+		return false
+	}
+	vindex, ok := fn.Exprs[len(fn.Exprs)-2].(*sqlparser.Literal)
+	return ok && vindex.Type == sqlparser.StrVal && strings.HasPrefix(vindex.Val, targetKeyspace+".")
+}
@@ -14,12 +14,21 @@ import (
 	"github.com/stretchr/testify/require"
 	clientv3 "go.etcd.io/etcd/client/v3"
 
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/testfiles"
 	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/etcd2topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/topotools"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 )
 
 // TestUpdateKeyspaceRoutingRule confirms that the keyspace routing rules are updated correctly.
@@ -170,3 +179,466 @@ func startEtcd(t *testing.T) string {
 
 	return clientAddr
 }
+
+// TestGetStreamThrottledState confirms that we correctly determine whether or
+// not a stream is currently being throttled.
+func TestGetStreamThrottledState(t *testing.T) {
+	tests := []struct {
+		name       string
+		stream     *vtctldatapb.Workflow_Stream
+		throttled  bool
+		wantReason string
+	}{
+		{
+			name:   "never throttled",
+			stream: &vtctldatapb.Workflow_Stream{TimeUpdated: &vttimepb.Time{Seconds: 100}},
+		},
+		{
+			name: "throttled earlier",
+			stream: &vtctldatapb.Workflow_Stream{
+				TimeUpdated: &vttimepb.Time{Seconds: 200},
+				ThrottlerStatus: &vtctldatapb.Workflow_Stream_ThrottlerStatus{
+					ComponentThrottled: "vplayer",
+					TimeThrottled:      &vttimepb.Time{Seconds: 100},
+				},
+			},
+		},
+		{
+			name: "currently throttled",
+			stream: &vtctldatapb.Workflow_Stream{
+				TimeUpdated: &vttimepb.Time{Seconds: 100},
+				ThrottlerStatus: &vtctldatapb.Workflow_Stream_ThrottlerStatus{
+					ComponentThrottled: "rowstreamer",
+					TimeThrottled:      &vttimepb.Time{Seconds: 100},
+				},
+			},
+			throttled:  true,
+			wantReason: "rowstreamer throttled at 1970-01-01T00:01:40Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttled, reason := getStreamThrottledState(tt.stream)
+			require.Equal(t, tt.throttled, throttled)
+			require.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+// TestDiffShardRoutingRules confirms that we correctly generate the changes
+// between two sets of shard routing rules.
+func TestDiffShardRoutingRules(t *testing.T) {
+	oldRules := map[string]string{
+		"customer.-80": "commerce",
+		"customer.80-": "commerce",
+		"other.0":      "other2",
+	}
+	newRules := map[string]string{
+		"commerce.-80": "customer",
+		"customer.80-": "commerce",
+		"other.0":      "other3",
+	}
+	require.Equal(t, []string{
+		"+ commerce.-80 => customer",
+		"- customer.-80 => commerce",
+		"- other.0 => other2",
+		"+ other.0 => other3",
+	}, diffShardRoutingRules(oldRules, newRules))
+	require.Empty(t, diffShardRoutingRules(oldRules, oldRules))
+}
+
+// TestDiffRoutingRules confirms that we correctly generate the changes
+// between two sets of table routing rules.
+func TestDiffRoutingRules(t *testing.T) {
+	oldRules := map[string][]string{
+		"t1":         {"source.t1"},
+		"target.t1":  {"target.t1"},
+		"t2":         {"source.t2", "other.t2"},
+		"t3@replica": {"source.t3"},
+	}
+	newRules := map[string][]string{
+		"t1":         {"source.t1"},
+		"target.t1":  {"source.t1"},
+		"t2":         {"source.t2"},
+		"t3@replica": {"source.t3"},
+		"source.t1":  {"source.t1"},
+	}
+	require.Equal(t, []string{
+		"+ source.t1 => source.t1",
+		"+ t2 => source.t2",
+		"- t2 => source.t2,other.t2",
+		"+ target.t1 => source.t1",
+		"- target.t1 => target.t1",
+	}, diffRoutingRules(oldRules, newRules))
+	require.Empty(t, diffRoutingRules(oldRules, oldRules))
+}
+
+// TestValidateKeyRangeCover confirms that we correctly describe any gaps or
+// overlaps between the target shards for a Reshard.
+func TestValidateKeyRangeCover(t *testing.T) {
+	shardInfos := func(shards ...string) []*topo.ShardInfo {
+		sis := make([]*topo.ShardInfo, 0, len(shards))
+		for _, shard := range shards {
+			_, kr, err := topo.ValidateShardName(shard)
+			require.NoError(t, err)
+			sis = append(sis, topo.NewShardInfo("ks", shard, &topodatapb.Shard{KeyRange: kr}, nil))
+		}
+		return sis
+	}
+	testCases := []struct {
+		name    string
+		sources []string
+		targets []string
+		wantErr string
+	}{
+		{
+			name:    "split unsharded",
+			sources: []string{"0"},
+			targets: []string{"80-", "-80"},
+		},
+		{
+			name:    "split shard",
+			sources: []string{"-80"},
+			targets: []string{"-40", "40-80"},
+		},
+		{
+			name:    "merge and split",
+			sources: []string{"80-", "-80"},
+			targets: []string{"-40", "40-c0", "c0-"},
+		},
+		{
+			name:    "no targets",
+			sources: []string{"0"},
+			wantErr: "at least one source and one target shard must be specified",
+		},
+		{
+			name:    "sources not contiguous",
+			sources: []string{"-40", "80-"},
+			targets: []string{"-80", "80-"},
+			wantErr: "invalid source shards: source shards -40 and 80- are not contiguous",
+		},
+		{
+			name:    "gaps",
+			sources: []string{"0"},
+			targets: []string{"40-50", "60-80"},
+			wantErr: "the target shards do not exactly cover the source key range -: key range -40 is not covered by any target shard; " +
+				"key range 50-60 is not covered by any target shard; key range 80- is not covered by any target shard",
+		},
+		{
+			name:    "overlaps",
+			sources: []string{"0"},
+			targets: []string{"-80", "40-", "50-60"},
+			wantErr: "the target shards do not exactly cover the source key range -: target shards -80 and 40- overlap on key range 40-80; " +
+				"target shards 40- and 50-60 overlap on key range 50-60",
+		},
+		{
+			name:    "outside the sources",
+			sources: []string{"40-80"},
+			targets: []string{"-60", "60-c0"},
+			wantErr: "the target shards do not exactly cover the source key range 40-80: target shard -60 starts before the source key range 40-80; " +
+				"target shard 60-c0 ends after the source key range 40-80",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateKeyRangeCover(shardInfos(tc.sources...), shardInfos(tc.targets...))
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCopyRemaining(t *testing.T) {
+	tests := []struct {
+		name           string
+		copied, total  int64
+		wantRemaining  int64
+		wantPercentage float32
+	}{
+		{
+			name: "nothing to copy",
+		},
+		{
+			name:           "nothing copied",
+			total:          200,
+			wantRemaining:  200,
+			wantPercentage: 0,
+		},
+		{
+			name:           "partially copied",
+			copied:         50,
+			total:          200,
+			wantRemaining:  150,
+			wantPercentage: 25,
+		},
+		{
+			name:           "fully copied",
+			copied:         200,
+			total:          200,
+			wantRemaining:  0,
+			wantPercentage: 100,
+		},
+		{
+			name:           "copied more than the estimated total",
+			copied:         250,
+			total:          200,
+			wantRemaining:  0,
+			wantPercentage: 100,
+		},
+		{
+			name:           "copied with no estimated total",
+			copied:         10,
+			wantRemaining:  0,
+			wantPercentage: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, percentage := copyRemaining(tt.copied, tt.total)
+			require.Equal(t, tt.wantRemaining, remaining)
+			require.Equal(t, tt.wantPercentage, percentage)
+		})
+	}
+}
+
+func TestEffectiveTabletTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		tabletTypes []topodatapb.TabletType
+		tsp         tabletmanagerdatapb.TabletSelectionPreference
+		wantTypes   []topodatapb.TabletType
+		wantTsp     tabletmanagerdatapb.TabletSelectionPreference
+	}{
+		{
+			name:      "defaults",
+			tsp:       tabletmanagerdatapb.TabletSelectionPreference_ANY,
+			wantTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY},
+			wantTsp:   tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+		},
+		{
+			name:        "configured",
+			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType_RDONLY},
+			tsp:         tabletmanagerdatapb.TabletSelectionPreference_ANY,
+			wantTypes:   []topodatapb.TabletType{topodatapb.TabletType_RDONLY},
+			wantTsp:     tabletmanagerdatapb.TabletSelectionPreference_ANY,
+		},
+		{
+			name:        "configured in order",
+			tabletTypes: []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY},
+			tsp:         tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+			wantTypes:   []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY},
+			wantTsp:     tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tabletTypes, tsp := effectiveTabletTypes(tt.tabletTypes, tt.tsp)
+			require.Equal(t, tt.wantTypes, tabletTypes)
+			require.Equal(t, tt.wantTsp, tsp)
+		})
+	}
+}
+
+func TestFilterWorkflowStreamsByState(t *testing.T) {
+	workflow := &vtctldatapb.Workflow{
+		ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+			"-80/zone1-100": {
+				Streams: []*vtctldatapb.Workflow_Stream{
+					{Id: 1, State: binlogdatapb.VReplicationWorkflowState_Running.String()},
+					{Id: 2, State: binlogdatapb.VReplicationWorkflowState_Error.String()},
+				},
+			},
+			"80-/zone1-200": {
+				Streams: []*vtctldatapb.Workflow_Stream{
+					{Id: 1, State: binlogdatapb.VReplicationWorkflowState_Copying.String()},
+				},
+			},
+		},
+	}
+	filterWorkflowStreamsByState(workflow, []binlogdatapb.VReplicationWorkflowState{binlogdatapb.VReplicationWorkflowState_Error})
+	require.Len(t, workflow.ShardStreams, 1)
+	require.Len(t, workflow.ShardStreams["-80/zone1-100"].Streams, 1)
+	require.Equal(t, int64(2), workflow.ShardStreams["-80/zone1-100"].Streams[0].Id)
+}
+
+func TestSummarizeWorkflow(t *testing.T) {
+	workflow := &vtctldatapb.Workflow{
+		WorkflowType:       binlogdatapb.VReplicationWorkflowType_MoveTables.String(),
+		MaxVReplicationLag: 5,
+		ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+			"-80/zone1-100": {
+				Streams: []*vtctldatapb.Workflow_Stream{
+					{Id: 1, State: binlogdatapb.VReplicationWorkflowState_Running.String()},
+					{Id: 2, State: binlogdatapb.VReplicationWorkflowState_Lagging.String()},
+				},
+			},
+			"80-/zone1-200": {
+				Streams: []*vtctldatapb.Workflow_Stream{
+					{Id: 1, State: binlogdatapb.VReplicationWorkflowState_Running.String()},
+					{
+						Id:         2,
+						State:      binlogdatapb.VReplicationWorkflowState_Copying.String(),
+						CopyStates: []*vtctldatapb.Workflow_Stream_CopyState{{Table: "t1"}},
+					},
+				},
+			},
+		},
+	}
+	summary := summarizeWorkflow(workflow)
+	require.Equal(t, workflow.WorkflowType, summary.WorkflowType)
+	require.Equal(t, binlogdatapb.VReplicationWorkflowState_Copying.String(), summary.State)
+	require.Equal(t, int64(5), summary.MaxVReplicationLag)
+	require.Equal(t, int64(4), summary.StreamCount)
+	require.Equal(t, float32(75), summary.CopyPercentage)
+
+	// All streams share the same state.
+	delete(workflow.ShardStreams, "80-/zone1-200")
+	workflow.ShardStreams["-80/zone1-100"].Streams[1].State = binlogdatapb.VReplicationWorkflowState_Running.String()
+	summary = summarizeWorkflow(workflow)
+	require.Equal(t, binlogdatapb.VReplicationWorkflowState_Running.String(), summary.State)
+	require.Equal(t, float32(100), summary.CopyPercentage)
+
+	require.Empty(t, summarizeWorkflow(&vtctldatapb.Workflow{}).State)
+}
+
+func TestValidateSrvVSchemaRoutingRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	defer ts.Close()
+
+	rules := &vschemapb.RoutingRules{
+		Rules: []*vschemapb.RoutingRule{
+			{FromTable: "t1", ToTables: []string{"ks2.t1"}},
+		},
+	}
+	require.NoError(t, ts.SaveRoutingRules(ctx, rules))
+	require.NoError(t, ts.RebuildSrvVSchema(ctx, nil))
+	require.NoError(t, validateSrvVSchemaRoutingRules(ctx, ts, nil))
+
+	// Make the SrvVSchema in one cell stale.
+	srvVSchema, err := ts.GetSrvVSchema(ctx, "zone2")
+	require.NoError(t, err)
+	srvVSchema.RoutingRules = &vschemapb.RoutingRules{}
+	require.NoError(t, ts.UpdateSrvVSchema(ctx, "zone2", srvVSchema))
+	require.NoError(t, validateSrvVSchemaRoutingRules(ctx, ts, []string{"zone1"}))
+	err = validateSrvVSchemaRoutingRules(ctx, ts, nil)
+	require.ErrorContains(t, err, "cell zone2")
+}
+
+func TestWorkflowSequences(t *testing.T) {
+	sequenceMetadata := map[string]*sequenceMetadata{
+		"t2_seq": {
+			backingTableName:     "t2_seq",
+			backingTableKeyspace: "unsharded",
+			backingTableDBName:   "vt_unsharded",
+			usingTableName:       "t2",
+			usingTableDBName:     "vt_target",
+			usingTableDefinition: &vschemapb.Table{
+				AutoIncrement: &vschemapb.AutoIncrement{
+					Column:   "id2",
+					Sequence: "unsharded.t2_seq",
+				},
+			},
+		},
+		"t1_seq": {
+			backingTableName:     "t1_seq",
+			backingTableKeyspace: "unsharded",
+			backingTableDBName:   "vt_unsharded",
+			usingTableName:       "t1",
+			usingTableDBName:     "vt_target",
+			usingTableDefinition: &vschemapb.Table{
+				AutoIncrement: &vschemapb.AutoIncrement{
+					Column:   "id",
+					Sequence: "t1_seq",
+				},
+			},
+		},
+	}
+	want := []*WorkflowSequence{
+		{Table: "t1", Column: "id", BackingTable: "t1_seq", BackingKeyspace: "unsharded"},
+		{Table: "t2", Column: "id2", BackingTable: "t2_seq", BackingKeyspace: "unsharded"},
+	}
+	require.Equal(t, want, workflowSequences(sequenceMetadata))
+	require.Empty(t, workflowSequences(nil))
+}
+
+func TestWorkflowDefinition(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	stream := func(shard, keyRange string) *vtctldatapb.Workflow_Stream {
+		return &vtctldatapb.Workflow_Stream{
+			Shard: shard,
+			BinlogSource: &binlogdatapb.BinlogSource{
+				Keyspace: "sourceks",
+				Shard:    "0",
+				OnDdl:    binlogdatapb.OnDDLAction_EXEC,
+				Filter: &binlogdatapb.Filter{
+					Rules: []*binlogdatapb.Rule{
+						{
+							Match:  "t2",
+							Filter: fmt.Sprintf("select * from t2 where in_keyrange(id, 'targetks.hash', '%s')", keyRange),
+						},
+						{
+							Match:  "t1",
+							Filter: fmt.Sprintf("select id, sum(val) as val from t1 where in_keyrange(id, 'targetks.hash', '%s') and val > 0 group by id", keyRange),
+						},
+						{
+							Match:  "ref",
+							Filter: "select * from ref where in_keyrange(id, 'sourceks.hash', '-80')",
+						},
+					},
+				},
+			},
+		}
+	}
+	wf := &vtctldatapb.Workflow{
+		Name:         "wf1",
+		WorkflowType: binlogdatapb.VReplicationWorkflowType_Materialize.String(),
+		Source:       &vtctldatapb.Workflow_ReplicationLocation{Keyspace: "sourceks", Shards: []string{"0"}},
+		Target:       &vtctldatapb.Workflow_ReplicationLocation{Keyspace: "targetks", Shards: []string{"-80", "80-"}},
+		Cells:        []string{"zone1", "zone2"},
+		TabletTypes:  []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY},
+		ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+			"-80/zone1-0000000200": {Streams: []*vtctldatapb.Workflow_Stream{stream("-80", "-80")}},
+			"80-/zone1-0000000210": {Streams: []*vtctldatapb.Workflow_Stream{stream("80-", "80-")}},
+		},
+		DeferSecondaryKeys: true,
+	}
+
+	ms, err := workflowDefinition(parser, wf)
+	require.NoError(t, err)
+	want := &vtctldatapb.MaterializeSettings{
+		Workflow:       "wf1",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{
+			{
+				TargetTable:      "ref",
+				SourceExpression: "select * from ref where in_keyrange(id, 'sourceks.hash', '-80')",
+				CreateDdl:        createDDLAsCopy,
+			},
+			{
+				TargetTable:      "t1",
+				SourceExpression: "select id, sum(val) as val from t1 where val > 0 group by id",
+				CreateDdl:        createDDLAsCopy,
+			},
+			{
+				TargetTable:      "t2",
+				SourceExpression: "select * from t2",
+				CreateDdl:        createDDLAsCopy,
+			},
+		},
+		Cell:               "zone1,zone2",
+		TabletTypes:        "replica,primary",
+		OnDdl:              binlogdatapb.OnDDLAction_EXEC.String(),
+		DeferSecondaryKeys: true,
+	}
+	utils.MustMatch(t, want, ms)
+
+	wf.WorkflowType = binlogdatapb.VReplicationWorkflowType_MoveTables.String()
+	_, err = workflowDefinition(parser, wf)
+	require.ErrorContains(t, err, "only Materialize workflows can be exported")
+}
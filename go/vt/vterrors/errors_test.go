@@ -60,6 +60,34 @@ func TestWrap(t *testing.T) {
 	}
 }
 
+func TestWithCode(t *testing.T) {
+	if got := WithCode(nil, vtrpcpb.Code_NOT_FOUND); got != nil {
+		t.Errorf("WithCode(nil, NOT_FOUND): got %#v, expected nil", got)
+	}
+
+	tests := []struct {
+		err      error
+		code     vtrpcpb.Code
+		wantCode vtrpcpb.Code
+	}{
+		{io.EOF, vtrpcpb.Code_NOT_FOUND, vtrpcpb.Code_NOT_FOUND},
+		{New(vtrpcpb.Code_UNKNOWN, "oops"), vtrpcpb.Code_INVALID_ARGUMENT, vtrpcpb.Code_INVALID_ARGUMENT},
+		{NewErrorf(vtrpcpb.Code_UNKNOWN, BadDb, "unknown db"), vtrpcpb.Code_NOT_FOUND, vtrpcpb.Code_NOT_FOUND},
+	}
+
+	for _, tt := range tests {
+		got := WithCode(tt.err, tt.code)
+		assert.Equal(t, tt.err.Error(), got.Error())
+		assert.Equal(t, tt.err.Error(), fmt.Sprintf("%s", got))
+		assert.Equal(t, tt.wantCode, Code(got))
+		assert.Equal(t, ErrState(tt.err), ErrState(got))
+		assert.Equal(t, tt.err, Cause(got))
+		assert.Equal(t, tt.err, RootCause(got))
+		// The code is kept when the error is wrapped further.
+		assert.Equal(t, tt.wantCode, Code(Wrap(got, "more context")))
+	}
+}
+
 func TestUnwrap(t *testing.T) {
 	tests := []struct {
 		err       error
@@ -265,6 +293,23 @@ func TestStackFormat(t *testing.T) {
 	assertContains(t, got, "outer", true)
 }
 
+func TestStackFormatSkipPackages(t *testing.T) {
+	err := outer()
+
+	setLogErrStacks(true)
+	defer func() { setLogErrStacks(false) }()
+	got := fmt.Sprintf("%v", err)
+	assertContains(t, got, "vterrors.innerMost", true)
+	assertContains(t, got, "testing.tRunner", true)
+
+	setLogErrStacksSkipPackages([]string{"testing", "runtime"})
+	defer func() { setLogErrStacksSkipPackages(nil) }()
+	got = fmt.Sprintf("%v", err)
+	assertContains(t, got, "vterrors.innerMost", true)
+	assertContains(t, got, "testing.tRunner", false)
+	assertContains(t, got, "runtime.goexit", false)
+}
+
 // errors.New, etc values are not expected to be compared by value
 // but the change in errors#27 made them incomparable. Assert that
 // various kinds of errors have a functional equality operator, even
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vterrors
+
+import (
+	"net/http"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file contains functions to map errors to HTTP status codes, for use
+// by services that expose Vitess operations over HTTP.
+
+// httpStatusByCode maps each error code to the HTTP status code that best
+// describes it:
+//
+//	OK                  -> 200 OK
+//	CANCELED            -> 499 Client Closed Request
+//	UNKNOWN             -> 500 Internal Server Error
+//	INVALID_ARGUMENT    -> 400 Bad Request
+//	DEADLINE_EXCEEDED   -> 504 Gateway Timeout
+//	NOT_FOUND           -> 404 Not Found
+//	ALREADY_EXISTS      -> 409 Conflict
+//	PERMISSION_DENIED   -> 403 Forbidden
+//	RESOURCE_EXHAUSTED  -> 429 Too Many Requests
+//	FAILED_PRECONDITION -> 412 Precondition Failed
+//	ABORTED             -> 409 Conflict
+//	OUT_OF_RANGE        -> 400 Bad Request
+//	UNIMPLEMENTED       -> 501 Not Implemented
+//	INTERNAL            -> 500 Internal Server Error
+//	UNAVAILABLE         -> 503 Service Unavailable
+//	DATA_LOSS           -> 500 Internal Server Error
+//	UNAUTHENTICATED     -> 401 Unauthorized
+//	CLUSTER_EVENT       -> 503 Service Unavailable
+//	READ_ONLY           -> 503 Service Unavailable
+var httpStatusByCode = map[vtrpcpb.Code]int{
+	vtrpcpb.Code_OK:                  http.StatusOK,
+	vtrpcpb.Code_CANCELED:            499, // There is no standard status for this, so use the nginx one.
+	vtrpcpb.Code_UNKNOWN:             http.StatusInternalServerError,
+	vtrpcpb.Code_INVALID_ARGUMENT:    http.StatusBadRequest,
+	vtrpcpb.Code_DEADLINE_EXCEEDED:   http.StatusGatewayTimeout,
+	vtrpcpb.Code_NOT_FOUND:           http.StatusNotFound,
+	vtrpcpb.Code_ALREADY_EXISTS:      http.StatusConflict,
+	vtrpcpb.Code_PERMISSION_DENIED:   http.StatusForbidden,
+	vtrpcpb.Code_RESOURCE_EXHAUSTED:  http.StatusTooManyRequests,
+	vtrpcpb.Code_FAILED_PRECONDITION: http.StatusPreconditionFailed,
+	vtrpcpb.Code_ABORTED:             http.StatusConflict,
+	vtrpcpb.Code_OUT_OF_RANGE:        http.StatusBadRequest,
+	vtrpcpb.Code_UNIMPLEMENTED:       http.StatusNotImplemented,
+	vtrpcpb.Code_INTERNAL:            http.StatusInternalServerError,
+	vtrpcpb.Code_UNAVAILABLE:         http.StatusServiceUnavailable,
+	vtrpcpb.Code_DATA_LOSS:           http.StatusInternalServerError,
+	vtrpcpb.Code_UNAUTHENTICATED:     http.StatusUnauthorized,
+	vtrpcpb.Code_CLUSTER_EVENT:       http.StatusServiceUnavailable,
+	vtrpcpb.Code_READ_ONLY:           http.StatusServiceUnavailable,
+}
+
+// HTTPStatus returns the HTTP status code that corresponds to the error code
+// of err, as returned by Code. If err is nil, it returns 200 OK. Any code
+// without a mapping results in 500 Internal Server Error.
+func HTTPStatus(err error) int {
+	if status, ok := httpStatusByCode[Code(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vterrors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	testcases := []struct {
+		in   error
+		want int
+	}{{
+		in:   nil,
+		want: http.StatusOK,
+	}, {
+		in:   New(vtrpcpb.Code_INVALID_ARGUMENT, "bad input"),
+		want: http.StatusBadRequest,
+	}, {
+		in:   Wrap(New(vtrpcpb.Code_NOT_FOUND, "no such workflow"), "outer"),
+		want: http.StatusNotFound,
+	}, {
+		in:   New(vtrpcpb.Code_FAILED_PRECONDITION, "not switched"),
+		want: http.StatusPreconditionFailed,
+	}, {
+		in:   New(vtrpcpb.Code_UNAVAILABLE, "down"),
+		want: http.StatusServiceUnavailable,
+	}, {
+		in:   context.DeadlineExceeded,
+		want: http.StatusGatewayTimeout,
+	}, {
+		in:   errors.New("plain error"),
+		want: http.StatusInternalServerError,
+	}}
+	for _, tcase := range testcases {
+		assert.Equal(t, tcase.want, HTTPStatus(tcase.in), "HTTPStatus(%v)", tcase.in)
+	}
+	// Every code must have a mapping.
+	for code := range vtrpcpb.Code_name {
+		_, ok := httpStatusByCode[vtrpcpb.Code(code)]
+		assert.True(t, ok, "no HTTP status for code %v", vtrpcpb.Code(code))
+	}
+}
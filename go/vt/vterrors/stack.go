@@ -55,6 +55,25 @@ func (f Frame) line() int {
 	return line
 }
 
+// inPackages returns true if the function for this Frame's pc is in one of
+// the packages given by their path prefixes, e.g. google.golang.org/grpc.
+func (f Frame) inPackages(pkgs []string) bool {
+	if len(pkgs) == 0 {
+		return false
+	}
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return false
+	}
+	name := fn.Name()
+	for _, pkg := range pkgs {
+		if strings.HasPrefix(name, pkg) {
+			return true
+		}
+	}
+	return false
+}
+
 // Format formats the frame according to the fmt.Formatter interface.
 //
 //	%s    source file
@@ -131,8 +150,12 @@ type stack []uintptr
 func (s *stack) Format(st fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		skipPackages := getLogErrStacksSkipPackages()
 		for _, pc := range *s {
 			f := Frame(pc)
+			if f.inPackages(skipPackages) {
+				continue
+			}
 			fmt.Fprintf(st, "\n%+v", f)
 		}
 	}
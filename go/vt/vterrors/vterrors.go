@@ -115,12 +115,29 @@ func setLogErrStacks(val bool) {
 	logErrStacks = val
 }
 
+// logErrStacksSkipPackages is the list of package path prefixes whose frames
+// are left out of the stack traces that are logged with errors.
+var logErrStacksSkipPackages []string
+
+func getLogErrStacksSkipPackages() []string {
+	muLogErrStacks.Lock()
+	defer muLogErrStacks.Unlock()
+	return logErrStacksSkipPackages
+}
+
+func setLogErrStacksSkipPackages(val []string) {
+	muLogErrStacks.Lock()
+	defer muLogErrStacks.Unlock()
+	logErrStacksSkipPackages = val
+}
+
 // RegisterFlags registers the command-line options that control vterror
 // behavior on the provided FlagSet.
 func RegisterFlags(fs *pflag.FlagSet) {
 	muLogErrStacks.Lock()
 	defer muLogErrStacks.Unlock()
 	fs.BoolVar(&logErrStacks, "log_err_stacks", false, "log stack traces for errors")
+	fs.StringSliceVar(&logErrStacksSkipPackages, "log-err-stacks-skip-packages", nil, "comma-separated list of package path prefixes, e.g. google.golang.org/grpc, whose frames are left out of logged error stack traces")
 }
 
 // New returns an error with the supplied message.
@@ -261,6 +278,21 @@ func Wrapf(err error, format string, args ...any) error {
 	}
 }
 
+// WithCode returns an error annotating err with the given code and a stack
+// trace at the point WithCode is called. The message of the returned error is
+// the same as that of err, and Code returns the given code for it.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code vtrpcpb.Code) error {
+	if err == nil {
+		return nil
+	}
+	return &withCode{
+		cause: err,
+		code:  code,
+		stack: callers(),
+	}
+}
+
 // Unwrap attempts to return the Cause of the given error, if it is indeed the result of a vterrors.Wrapf()
 // The function indicates whether the error was indeed wrapped. If the error was not wrapped, the function
 // returns the original error.
@@ -305,6 +337,32 @@ func (w *wrapping) Format(s fmt.State, verb rune) {
 	}
 }
 
+type withCode struct {
+	cause error
+	code  vtrpcpb.Code
+	stack *stack
+}
+
+func (w *withCode) Error() string           { return w.cause.Error() }
+func (w *withCode) Cause() error            { return w.cause }
+func (w *withCode) ErrorCode() vtrpcpb.Code { return w.code }
+
+func (w *withCode) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		panicIfError(io.WriteString(s, "Code: "+w.code.String()+"\n"))
+		panicIfError(io.WriteString(s, w.cause.Error()+"\n"))
+		if getLogErrStacks() {
+			w.stack.Format(s, verb)
+		}
+		return
+	case 's':
+		panicIfError(io.WriteString(s, w.Error()))
+	case 'q':
+		panicIfError(fmt.Fprintf(s, "%q", w.Error()))
+	}
+}
+
 // since we can't return an error, let's panic if something goes wrong here
 func panicIfError(_ int, err error) {
 	if err != nil {
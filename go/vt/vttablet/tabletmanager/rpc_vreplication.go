@@ -19,6 +19,7 @@ package tabletmanager
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"golang.org/x/exp/maps"
@@ -403,6 +404,9 @@ func isStreamCopying(tm *TabletManager, id int64) (bool, error) {
 // workflow stream when the record is updated, so we also in effect
 // restart the workflow stream via the update.
 func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *tabletmanagerdatapb.UpdateVReplicationWorkflowRequest) (*tabletmanagerdatapb.UpdateVReplicationWorkflowResponse, error) {
+	if req.NewWorkflowName != "" {
+		return tm.renameVReplicationWorkflow(req)
+	}
 	bindVars := map[string]*querypb.BindVariable{
 		"wf": sqltypes.StringBindVariable(req.Workflow),
 	}
@@ -426,6 +430,15 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 	rowsAffected := uint64(0)
 	for _, row := range res.Named().Rows {
 		id := row.AsInt64("id", 0)
+		if len(req.IncludeIds) > 0 && !slices.Contains(req.IncludeIds, int32(id)) {
+			continue
+		}
+		state := row.AsString("state", "")
+		if len(req.IncludeStates) > 0 && !slices.ContainsFunc(req.IncludeStates, func(s binlogdatapb.VReplicationWorkflowState) bool {
+			return s.String() == state
+		}) {
+			continue
+		}
 		cells := strings.Split(row.AsString("cell", ""), ",")
 		for i := range cells {
 			cells[i] = strings.TrimSpace(cells[i])
@@ -436,7 +449,6 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 		}
 		bls := &binlogdatapb.BinlogSource{}
 		source := row.AsBytes("source", []byte{})
-		state := row.AsString("state", "")
 		message := row.AsString("message", "")
 		if req.State == binlogdatapb.VReplicationWorkflowState_Running && strings.ToUpper(message) == workflow.Frozen {
 			return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{Result: nil},
@@ -508,6 +520,52 @@ func (tm *TabletManager) UpdateVReplicationWorkflow(ctx context.Context, req *ta
 	}, nil
 }
 
+// renameVReplicationWorkflow renames all of the workflow's streams on this
+// tablet, along with any vdiffs of the workflow, in a single transaction.
+// A rename cannot be combined with any other change to the workflow, nor
+// limited to some of its streams, as the workflow would otherwise be left
+// partly renamed.
+func (tm *TabletManager) renameVReplicationWorkflow(req *tabletmanagerdatapb.UpdateVReplicationWorkflowRequest) (*tabletmanagerdatapb.UpdateVReplicationWorkflowResponse, error) {
+	if !textutil.ValueIsSimulatedNull(req.Cells) || !textutil.ValueIsSimulatedNull(req.TabletTypes) ||
+		!textutil.ValueIsSimulatedNull(req.OnDdl) || !textutil.ValueIsSimulatedNull(req.State) ||
+		req.TabletSelectionPreference != tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the %s workflow cannot be updated while it is renamed", req.Workflow)
+	}
+	if len(req.IncludeIds) > 0 || len(req.IncludeStates) > 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the %s workflow can only be renamed as a whole", req.Workflow)
+	}
+	bindVars := map[string]*querypb.BindVariable{
+		"wf": sqltypes.StringBindVariable(req.Workflow),
+	}
+	parsed := sqlparser.BuildParsedQuery(sqlSelectVReplicationWorkflowConfig, sidecar.GetIdentifier(), ":wf")
+	stmt, err := parsed.GenerateQuery(bindVars, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := tm.VREngine.Exec(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || len(res.Rows) == 0 {
+		// No streams on this tablet to rename, as is the
+		// case for the source tablets of Reshard workflows.
+		return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{Result: nil}, nil
+	}
+	ids := make([]int32, 0, len(res.Rows))
+	for _, row := range res.Named().Rows {
+		ids = append(ids, int32(row.AsInt64("id", 0)))
+	}
+	res, err = tm.VREngine.RenameWorkflow(ids, req.Workflow, req.NewWorkflowName)
+	if err != nil {
+		return nil, err
+	}
+	return &tabletmanagerdatapb.UpdateVReplicationWorkflowResponse{
+		Result: &querypb.QueryResult{
+			RowsAffected: res.RowsAffected,
+		},
+	}, nil
+}
+
 // UpdateVReplicationWorkflows operates in much the same way that
 // UpdateVReplicationWorkflow does, but it allows you to update the
 // metadata/flow control fields -- state, message, and stop_pos -- for
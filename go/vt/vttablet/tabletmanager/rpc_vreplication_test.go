@@ -255,7 +255,7 @@ func TestCreateVReplicationWorkflow(t *testing.T) {
 		},
 	}
 
-	tenv.tmc.setVReplicationExecResults(sourceTablet.tablet, "select val from _vt.resharding_journal where id=7224776740563431192", &sqltypes.Result{})
+	tenv.tmc.setVReplicationExecResults(sourceTablet.tablet, "select val from _vt.resharding_journal where id=7224776740563431192 limit 2", &sqltypes.Result{})
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -655,6 +655,17 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 			query: fmt.Sprintf(`update _vt.vreplication set state = 'Running', source = 'keyspace:"%s" shard:"%s" filter:{rules:{match:"corder" filter:"select * from corder"} rules:{match:"customer" filter:"select * from customer"}} on_ddl:%s', cell = '', tablet_types = '' where id in (%d)`,
 				keyspace, shard, binlogdatapb.OnDDLAction_EXEC.String(), vreplID),
 		},
+		{
+			name: "update on_ddl for running streams",
+			request: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+				Workflow:      workflow,
+				State:         binlogdatapb.VReplicationWorkflowState(textutil.SimulatedNullInt),
+				OnDdl:         binlogdatapb.OnDDLAction_STOP,
+				IncludeStates: []binlogdatapb.VReplicationWorkflowState{binlogdatapb.VReplicationWorkflowState_Running},
+			},
+			query: fmt.Sprintf(`update _vt.vreplication set state = 'Running', source = 'keyspace:"%s" shard:"%s" filter:{rules:{match:"corder" filter:"select * from corder"} rules:{match:"customer" filter:"select * from customer"}} on_ddl:%s', cell = '', tablet_types = '' where id in (%d)`,
+				keyspace, shard, binlogdatapb.OnDDLAction_STOP.String(), vreplID),
+		},
 		{
 			name: "update cell,tablet_types,on_ddl",
 			request: &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
@@ -733,6 +744,100 @@ func TestUpdateVReplicationWorkflow(t *testing.T) {
 	}
 }
 
+// TestRenameVReplicationWorkflow confirms that a workflow's streams and
+// vdiffs are renamed in a single transaction, which is rolled back if any
+// part of the rename fails, and that a rename cannot be combined with other
+// changes to the workflow.
+func TestRenameVReplicationWorkflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	workflow := "testwf"
+	newWorkflow := "newwf"
+	keyspace := "testks"
+	tabletUID := 100
+
+	tenv := newTestEnv(t, ctx, keyspace, []string{shard})
+	defer tenv.close()
+
+	tablet := tenv.addTablet(t, tabletUID, keyspace, shard)
+	defer tenv.deleteTablet(tablet.tablet)
+
+	selectQuery, err := sqlparser.BuildParsedQuery(sqlSelectVReplicationWorkflowConfig, sidecar.GetIdentifier(), ":wf").GenerateQuery(
+		map[string]*querypb.BindVariable{"wf": sqltypes.StringBindVariable(workflow)}, nil)
+	require.NoError(t, err)
+	blsStr := fmt.Sprintf(`keyspace:"%s" shard:"%s" filter:{rules:{match:"t1" filter:"select * from t1"}}`, keyspace, shard)
+	selectRes := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id|source|cell|tablet_types|state|message",
+			"int64|varchar|varchar|varchar|varchar|varbinary",
+		),
+		fmt.Sprintf("1|%s|zone1|replica|Stopped|", blsStr),
+		fmt.Sprintf("2|%s|zone1|replica|Stopped|", blsStr),
+	)
+	renameStreams := fmt.Sprintf("update _vt.vreplication set workflow = '%s' where id in (1, 2)", newWorkflow)
+	renameVDiffs := fmt.Sprintf("update _vt.vdiff set workflow = '%s' where db_name = '%s' and workflow = '%s'", newWorkflow, tenv.dbName, workflow)
+	streamRow := func(id int, name string) *sqltypes.Result {
+		return sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"id|workflow|source|state|options",
+				"int64|varchar|varchar|varchar|varchar",
+			),
+			fmt.Sprintf("%d|%s|%s|Stopped|", id, name, blsStr),
+		)
+	}
+	renameReq := &tabletmanagerdatapb.UpdateVReplicationWorkflowRequest{
+		Workflow:                  workflow,
+		Cells:                     textutil.SimulatedNullStringSlice,
+		TabletTypes:               []topodatapb.TabletType{topodatapb.TabletType(textutil.SimulatedNullInt)},
+		TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_UNKNOWN,
+		OnDdl:                     binlogdatapb.OnDDLAction(textutil.SimulatedNullInt),
+		State:                     binlogdatapb.VReplicationWorkflowState(textutil.SimulatedNullInt),
+		NewWorkflowName:           newWorkflow,
+	}
+	vrdbClient := tenv.tmc.tablets[tabletUID].vrdbClient
+
+	// The streams and vdiffs are renamed together.
+	vrdbClient.ExpectRequest(fmt.Sprintf("use %s", sidecar.GetIdentifier()), &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest(selectQuery, selectRes, nil)
+	vrdbClient.ExpectRequest(fmt.Sprintf("use %s", sidecar.GetIdentifier()), &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest("begin", &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest(renameStreams, &sqltypes.Result{RowsAffected: 2}, nil)
+	vrdbClient.ExpectRequest(renameVDiffs, &sqltypes.Result{RowsAffected: 1}, nil)
+	vrdbClient.ExpectRequest("commit", &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest("select * from _vt.vreplication where id = 1", streamRow(1, newWorkflow), nil)
+	vrdbClient.ExpectRequest("select * from _vt.vreplication where id = 2", streamRow(2, newWorkflow), nil)
+	res, err := tenv.tmc.tablets[tabletUID].tm.UpdateVReplicationWorkflow(ctx, renameReq)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), res.GetResult().GetRowsAffected())
+	vrdbClient.Wait()
+
+	// A failure to rename the vdiffs rolls back the rename of the streams,
+	// which are restarted with their original name.
+	vrdbClient.ExpectRequest(fmt.Sprintf("use %s", sidecar.GetIdentifier()), &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest(selectQuery, selectRes, nil)
+	vrdbClient.ExpectRequest(fmt.Sprintf("use %s", sidecar.GetIdentifier()), &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest("begin", &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest(renameStreams, &sqltypes.Result{RowsAffected: 2}, nil)
+	vrdbClient.ExpectRequest(renameVDiffs, nil, errShortCircuit)
+	vrdbClient.ExpectRequest("rollback", &sqltypes.Result{}, nil)
+	vrdbClient.ExpectRequest("select * from _vt.vreplication where id = 1", streamRow(1, workflow), nil)
+	vrdbClient.ExpectRequest("select * from _vt.vreplication where id = 2", streamRow(2, workflow), nil)
+	_, err = tenv.tmc.tablets[tabletUID].tm.UpdateVReplicationWorkflow(ctx, renameReq)
+	require.ErrorIs(t, err, errShortCircuit)
+	vrdbClient.Wait()
+
+	// A rename cannot be combined with other changes, nor limited to some
+	// of the workflow's streams.
+	invalidReq := renameReq.CloneVT()
+	invalidReq.State = binlogdatapb.VReplicationWorkflowState_Stopped
+	_, err = tenv.tmc.tablets[tabletUID].tm.UpdateVReplicationWorkflow(ctx, invalidReq)
+	require.ErrorContains(t, err, "cannot be updated while it is renamed")
+	invalidReq = renameReq.CloneVT()
+	invalidReq.IncludeIds = []int32{1}
+	_, err = tenv.tmc.tablets[tabletUID].tm.UpdateVReplicationWorkflow(ctx, invalidReq)
+	require.ErrorContains(t, err, "can only be renamed as a whole")
+}
+
 func TestUpdateVReplicationWorkflows(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
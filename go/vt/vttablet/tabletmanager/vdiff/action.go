@@ -369,7 +369,7 @@ func (vde *Engine) handleStopAction(ctx context.Context, dbClient binlogplayer.D
 func (vde *Engine) handleDeleteAction(ctx context.Context, dbClient binlogplayer.DBClient, action VDiffAction, req *tabletmanagerdatapb.VDiffRequest, resp *tabletmanagerdatapb.VDiffResponse) error {
 	vde.mu.Lock()
 	defer vde.mu.Unlock()
-	var deleteQuery string
+	var deleteQueries []string
 	cleanupController := func(controller *controller) {
 		if controller == nil {
 			return
@@ -399,13 +399,39 @@ func (vde *Engine) handleDeleteAction(ctx context.Context, dbClient binlogplayer
 		for _, row := range res.Named().Rows {
 			cleanupController(vde.controllers[row.AsInt64("id", -1)])
 		}
-		deleteQuery, err = sqlparser.ParseAndBind(sqlDeleteVDiffs,
+		if len(req.KeepTables) == 0 {
+			deleteQuery, err := sqlparser.ParseAndBind(sqlDeleteVDiffs,
+				sqltypes.StringBindVariable(req.Keyspace),
+				sqltypes.StringBindVariable(req.Workflow),
+			)
+			if err != nil {
+				return err
+			}
+			deleteQueries = append(deleteQueries, deleteQuery)
+			break
+		}
+		// Only delete the table records for tables we are not keeping,
+		// and then any vdiff records which no longer have any tables.
+		keepTables, err := sqltypes.BuildBindVariable(req.KeepTables)
+		if err != nil {
+			return err
+		}
+		deleteTablesQuery, err := sqlparser.ParseAndBind(sqlDeleteVDiffTablesExcept,
 			sqltypes.StringBindVariable(req.Keyspace),
 			sqltypes.StringBindVariable(req.Workflow),
+			keepTables,
 		)
 		if err != nil {
 			return err
 		}
+		deleteEmptyQuery, err := sqlparser.ParseAndBind(sqlDeleteVDiffsWithoutTables,
+			sqltypes.StringBindVariable(req.Keyspace),
+			sqltypes.StringBindVariable(req.Workflow),
+		)
+		if err != nil {
+			return err
+		}
+		deleteQueries = append(deleteQueries, deleteTablesQuery, deleteEmptyQuery)
 	default:
 		uuid, err := uuid.Parse(req.ActionArg)
 		if err != nil {
@@ -429,16 +455,19 @@ func (vde *Engine) handleDeleteAction(ctx context.Context, dbClient binlogplayer
 				uuid, vde.thisTablet.Alias)
 		}
 		cleanupController(vde.controllers[row.AsInt64("id", -1)])
-		deleteQuery, err = sqlparser.ParseAndBind(sqlDeleteVDiffByUUID,
+		deleteQuery, err := sqlparser.ParseAndBind(sqlDeleteVDiffByUUID,
 			sqltypes.StringBindVariable(uuid.String()),
 		)
 		if err != nil {
 			return err
 		}
+		deleteQueries = append(deleteQueries, deleteQuery)
 	}
-	// Execute the query which deletes the vdiff record(s).
-	if _, err := dbClient.ExecuteFetch(deleteQuery, 1); err != nil {
-		return err
+	// Execute the queries which delete the vdiff record(s).
+	for _, deleteQuery := range deleteQueries {
+		if _, err := dbClient.ExecuteFetch(deleteQuery, 1); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -180,6 +180,38 @@ func TestPerformVDiffAction(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "delete all except kept tables",
+			req: &tabletmanagerdatapb.VDiffRequest{
+				Action:     string(DeleteAction),
+				ActionArg:  "all",
+				Keyspace:   keyspace,
+				Workflow:   workflow,
+				KeepTables: []string{"t1", "t2"},
+			},
+			expectQueries: []queryAndResult{
+				{
+					query: fmt.Sprintf("select id as id from _vt.vdiff where keyspace = %s and workflow = %s", encodeString(keyspace), encodeString(workflow)),
+					result: sqltypes.MakeTestResult(
+						sqltypes.MakeTestFields(
+							"id",
+							"int64",
+						),
+						"1",
+						"2",
+					),
+				},
+				{
+					query: fmt.Sprintf(`delete from vdt using _vt.vdiff as vd join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+								where vd.keyspace = %s and vd.workflow = %s and vdt.table_name not in ('t1', 't2')`, encodeString(keyspace), encodeString(workflow)),
+				},
+				{
+					query: fmt.Sprintf(`delete from vd, vdl using _vt.vdiff as vd left join _vt.vdiff_log as vdl on (vd.id = vdl.vdiff_id)
+									where vd.keyspace = %s and vd.workflow = %s
+									and not exists (select 1 from _vt.vdiff_table as vdt where vdt.vdiff_id = vd.id)`, encodeString(keyspace), encodeString(workflow)),
+				},
+			},
+		},
 		{
 			name: "show last",
 			req: &tabletmanagerdatapb.VDiffRequest{
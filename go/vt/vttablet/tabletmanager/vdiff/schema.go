@@ -30,6 +30,11 @@ const (
 	sqlDeleteVDiffs                         = `delete from vd, vdt, vdl using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
 										left join _vt.vdiff_log as vdl on (vd.id = vdl.vdiff_id)
 										where vd.keyspace = %a and vd.workflow = %a`
+	sqlDeleteVDiffTablesExcept = `delete from vdt using _vt.vdiff as vd join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
+								where vd.keyspace = %a and vd.workflow = %a and vdt.table_name not in %a`
+	sqlDeleteVDiffsWithoutTables = `delete from vd, vdl using _vt.vdiff as vd left join _vt.vdiff_log as vdl on (vd.id = vdl.vdiff_id)
+									where vd.keyspace = %a and vd.workflow = %a
+									and not exists (select 1 from _vt.vdiff_table as vdt where vdt.vdiff_id = vd.id)`
 	sqlDeleteVDiffByUUID = `delete from vd, vdt using _vt.vdiff as vd left join _vt.vdiff_table as vdt on (vd.id = vdt.vdiff_id)
 							where vd.vdiff_uuid = %a`
 	sqlVDiffSummary = `select vd.state as vdiff_state, vd.last_error as last_error, vdt.table_name as table_name,
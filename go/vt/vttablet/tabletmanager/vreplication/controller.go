@@ -58,6 +58,7 @@ type controller struct {
 	id           int32
 	workflow     string
 	source       *binlogdatapb.BinlogSource
+	options      *workflowOptions
 	stopPos      string
 	tabletPicker *discovery.TabletPicker
 
@@ -101,6 +102,9 @@ func newController(ctx context.Context, params map[string]string, dbClientFactor
 	if err := prototext.Unmarshal([]byte(params["source"]), ct.source); err != nil {
 		return nil, err
 	}
+	if ct.options, err = parseWorkflowOptions(params["options"]); err != nil {
+		return nil, err
+	}
 
 	// Nothing to do if replication is stopped or is known to have an unrecoverable error.
 	if state == binlogdatapb.VReplicationWorkflowState_Stopped.String() || state == binlogdatapb.VReplicationWorkflowState_Error.String() {
@@ -254,6 +258,7 @@ func (ct *controller) runBlp(ctx context.Context) (err error) {
 		defer vsClient.Close(ctx)
 
 		vr := newVReplicator(ct.id, ct.source, vsClient, ct.blpStats, dbClient, ct.mysqld, ct.vre)
+		vr.options = ct.options
 		err = vr.Replicate(ctx)
 		ct.lastWorkflowError.Record(err)
 
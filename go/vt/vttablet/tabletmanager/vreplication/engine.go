@@ -35,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -54,6 +55,11 @@ const (
 	postCopyActionTableName    = "post_copy_action"
 
 	maxRows = 10000
+
+	// Rename the given vreplication streams, and any vdiffs of their
+	// workflow, as part of renaming the workflow.
+	sqlRenameVReplicationStreams = "update %s.vreplication set workflow = %a where id in %a"
+	sqlRenameVDiffs              = "update %s.vdiff set workflow = %a where db_name = %a and workflow = %a"
 )
 
 const (
@@ -532,6 +538,97 @@ func (vre *Engine) exec(query string, runAsAdmin bool) (*sqltypes.Result, error)
 	panic("unreachable")
 }
 
+// RenameWorkflow renames the streams with the given ids from the workflow
+// to newWorkflow, along with any vdiffs of the workflow, in a single
+// transaction so that either all of them are renamed or none are. The
+// streams' controllers are restarted afterwards, whether or not the rename
+// succeeded, so that they pick up the current name.
+func (vre *Engine) RenameWorkflow(ids []int32, workflow, newWorkflow string) (*sqltypes.Result, error) {
+	vre.mu.Lock()
+	defer vre.mu.Unlock()
+	if !vre.isOpen {
+		return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "vreplication engine is closed")
+	}
+	if vre.cancelRetry != nil {
+		return nil, vterrors.New(vtrpcpb.Code_UNAVAILABLE, "engine is still trying to open")
+	}
+	defer vre.updateStats()
+
+	idsbv, err := sqltypes.BuildBindVariable(ids)
+	if err != nil {
+		return nil, err
+	}
+	renameStreams, err := sqlparser.BuildParsedQuery(sqlRenameVReplicationStreams, sidecar.GetIdentifier(), ":wf", "::ids").GenerateQuery(
+		map[string]*querypb.BindVariable{
+			"wf":  sqltypes.StringBindVariable(newWorkflow),
+			"ids": idsbv,
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+	renameVDiffs, err := sqlparser.BuildParsedQuery(sqlRenameVDiffs, sidecar.GetIdentifier(), ":wf", ":db", ":oldwf").GenerateQuery(
+		map[string]*querypb.BindVariable{
+			"wf":    sqltypes.StringBindVariable(newWorkflow),
+			"db":    sqltypes.StringBindVariable(vre.dbName),
+			"oldwf": sqltypes.StringBindVariable(workflow),
+		}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dbClient := vre.getDBClient(false)
+	if err := dbClient.Connect(); err != nil {
+		return nil, err
+	}
+	defer dbClient.Close()
+	if _, err := dbClient.ExecuteFetch(fmt.Sprintf("use %s", sidecar.GetIdentifier()), 1); err != nil {
+		return nil, err
+	}
+
+	blpStats := make(map[int32]*binlogplayer.Stats)
+	for _, id := range ids {
+		if ct := vre.controllers[id]; ct != nil {
+			ct.Stop()
+			blpStats[id] = ct.blpStats
+		}
+	}
+
+	qr, renameErr := func() (*sqltypes.Result, error) {
+		if err := dbClient.Begin(); err != nil {
+			return nil, err
+		}
+		qr, err := dbClient.ExecuteFetch(renameStreams, maxRows)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := dbClient.ExecuteFetch(renameVDiffs, maxRows); err != nil {
+			return nil, err
+		}
+		if err := dbClient.Commit(); err != nil {
+			return nil, err
+		}
+		return qr, nil
+	}()
+	if renameErr != nil {
+		dbClient.Rollback()
+	}
+
+	for _, id := range ids {
+		params, err := readRow(dbClient, id)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to restart stream %d after renaming the %s workflow", id, workflow)
+		}
+		// Create a new controller in place of the old one.
+		// For continuity, the new controller inherits the previous stats.
+		ct, err := newController(vre.ctx, params, vre.dbClientFactoryFiltered, vre.mysqld, vre.ts, vre.cell, tabletTypesStr, blpStats[id], vre, discovery.TabletPickerOptions{})
+		if err != nil {
+			return nil, err
+		}
+		vre.controllers[id] = ct
+	}
+	return qr, renameErr
+}
+
 func (vre *Engine) fetchIDs(dbClient binlogplayer.DBClient, selector string) (ids []int32, bv map[string]*querypb.BindVariable, err error) {
 	qr, err := dbClient.ExecuteFetch(selector, 10000)
 	if err != nil {
@@ -68,6 +68,28 @@ const (
 	LogError = "Error"
 )
 
+// workflowOptions contains the workflow options used by the workflow's
+// streams. The workflow server stores the options as JSON in the options
+// column of the vreplication table.
+type workflowOptions struct {
+	// CopyParallelInsertWorkers overrides the
+	// vreplication-parallel-insert-workers flag when set.
+	CopyParallelInsertWorkers int `json:"copy_parallel_insert_workers,omitempty"`
+}
+
+// parseWorkflowOptions parses the value of the options column of the
+// vreplication table.
+func parseWorkflowOptions(options string) (*workflowOptions, error) {
+	opts := &workflowOptions{}
+	if options == "" {
+		return opts, nil
+	}
+	if err := json.Unmarshal([]byte(options), opts); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to parse the workflow options %q", options)
+	}
+	return opts, nil
+}
+
 func getLastLog(dbClient *vdbClient, vreplID int32) (id int64, typ, state, message string, err error) {
 	var qr *sqltypes.Result
 	query := fmt.Sprintf("select id, type, state, message from %s.vreplication_log where vrepl_id = %d order by id desc limit 1",
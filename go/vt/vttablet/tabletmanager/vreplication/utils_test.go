@@ -97,3 +97,50 @@ func TestInsertLogTruncation(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkflowOptionsInsertParallelism(t *testing.T) {
+	oldVreplicationParallelInsertWorkers := vreplicationParallelInsertWorkers
+	defer func() {
+		vreplicationParallelInsertWorkers = oldVreplicationParallelInsertWorkers
+	}()
+	vreplicationParallelInsertWorkers = 4
+
+	testCases := []struct {
+		name        string
+		options     string
+		parallelism int
+		wantErr     string
+	}{
+		{
+			name:        "no options",
+			parallelism: 4,
+		},
+		{
+			name:        "no override",
+			options:     `{"tenant_id":"t1"}`,
+			parallelism: 4,
+		},
+		{
+			name:        "override",
+			options:     `{"tenant_id":"t1","copy_parallel_insert_workers":2}`,
+			parallelism: 2,
+		},
+		{
+			name:    "invalid",
+			options: `{"copy_parallel_insert_workers":`,
+			wantErr: "failed to parse the workflow options",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseWorkflowOptions(tc.options)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			vr := &vreplicator{options: opts}
+			require.Equal(t, tc.parallelism, vr.getInsertParallelism())
+		})
+	}
+}
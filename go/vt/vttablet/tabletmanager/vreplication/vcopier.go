@@ -405,7 +405,7 @@ func (vc *vcopier) copyTable(ctx context.Context, tableName string, copyState ma
 	copyStateGCTicker := time.NewTicker(copyStateGCInterval)
 	defer copyStateGCTicker.Stop()
 
-	parallelism := getInsertParallelism()
+	parallelism := vc.vr.getInsertParallelism()
 	copyWorkerFactory := vc.newCopyWorkerFactory(parallelism)
 	copyWorkQueue := vc.newCopyWorkQueue(parallelism, copyWorkerFactory)
 	defer copyWorkQueue.close()
@@ -1203,7 +1203,12 @@ func vcopierCopyTaskGetNextState(vts vcopierCopyTaskState) vcopierCopyTaskState
 }
 
 // getInsertParallelism returns the number of parallel workers to use for inserting batches during the copy phase.
-func getInsertParallelism() int {
-	parallelism := int(math.Max(1, float64(vreplicationParallelInsertWorkers)))
+// The workflow's copy_parallel_insert_workers option takes precedence over the flag when set.
+func (vr *vreplicator) getInsertParallelism() int {
+	workers := vreplicationParallelInsertWorkers
+	if vr.options.CopyParallelInsertWorkers > 0 {
+		workers = vr.options.CopyParallelInsertWorkers
+	}
+	parallelism := int(math.Max(1, float64(workers)))
 	return parallelism
 }
@@ -97,6 +97,7 @@ type vreplicator struct {
 	// source
 	source          *binlogdatapb.BinlogSource
 	sourceVStreamer VStreamerClient
+	options         *workflowOptions
 	state           binlogdatapb.VReplicationWorkflowState
 	stats           *binlogplayer.Stats
 	// mysqld is used to fetch the local schema.
@@ -145,6 +146,7 @@ func newVReplicator(id int32, source *binlogdatapb.BinlogSource, sourceVStreamer
 		id:              id,
 		source:          source,
 		sourceVStreamer: sourceVStreamer,
+		options:         &workflowOptions{},
 		stats:           stats,
 		dbClient:        newVDBClient(dbClient, stats),
 		mysqld:          mysqld,
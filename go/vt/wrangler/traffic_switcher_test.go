@@ -319,8 +319,8 @@ func TestTableMigrateMainflow(t *testing.T) {
 	})
 
 	checkJournals := func() {
-		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
-		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
 	}
 	checkJournals()
 
@@ -625,8 +625,8 @@ func TestShardMigrateMainflow(t *testing.T) {
 	checkIfPrimaryServing(t, tme.ts, "ks:80-", false)
 
 	checkJournals := func() {
-		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=6432976123657117097", &sqltypes.Result{}, nil)
-		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=6432976123657117097", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=6432976123657117097 limit 2", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=6432976123657117097 limit 2", &sqltypes.Result{}, nil)
 	}
 	checkJournals()
 
@@ -1127,8 +1127,8 @@ func TestMigrateFailJournal(t *testing.T) {
 	require.NoError(t, err)
 
 	// mi.checkJournals
-	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
-	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
 
 	// mi.waitForCatchup-> mi.wr.tmc.VReplicationWaitForPos
 	state := sqltypes.MakeTestResult(sqltypes.MakeTestFields(
@@ -1227,8 +1227,8 @@ func TestTableMigrateJournalExists(t *testing.T) {
 		t.Fatal(err)
 	}
 	// mi.checkJournals: Show one journal as created.
-	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", sqltypes.MakeTestResult(sqltypes.MakeTestFields("val", "varbinary"), ""), nil)
-	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", sqltypes.MakeTestResult(sqltypes.MakeTestFields("val", "varbinary"), ""), nil)
+	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
 
 	// mi.createJournals: Create the missing journal.
 	journal2 := "insert into _vt.resharding_journal.*7672494164556733923,.*tables.*t1.*t2.*local_position.*MariaDB/5-456-892.*shard_gtids.*80.*MariaDB/5-456-893.*80.*participants.*40.*40"
@@ -1306,8 +1306,8 @@ func TestShardMigrateJournalExists(t *testing.T) {
 	}
 
 	// mi.checkJournals
-	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=6432976123657117097", sqltypes.MakeTestResult(sqltypes.MakeTestFields("val", "varbinary"), ""), nil)
-	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=6432976123657117097", &sqltypes.Result{}, nil)
+	tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=6432976123657117097 limit 2", sqltypes.MakeTestResult(sqltypes.MakeTestFields("val", "varbinary"), ""), nil)
+	tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=6432976123657117097 limit 2", &sqltypes.Result{}, nil)
 
 	// mi.creaetJournals: Create the missing journal.
 	journal2 := "insert into _vt.resharding_journal.*6432976123657117097.*migration_type:SHARDS.*local_position.*MariaDB/5-456-892.*shard_gtids.*80.*MariaDB/5-456-893.*shard_gtids.*80.*MariaDB/5-456-893.*participants.*40.*40"
@@ -1368,8 +1368,8 @@ func TestTableMigrateCancel(t *testing.T) {
 	}
 
 	checkJournals := func() {
-		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
-		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
 	}
 	checkJournals()
 
@@ -1430,8 +1430,8 @@ func TestTableMigrateCancelDryRun(t *testing.T) {
 	}
 
 	checkJournals := func() {
-		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
-		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
 	}
 	checkJournals()
 
@@ -1482,8 +1482,8 @@ func TestTableMigrateNoReverse(t *testing.T) {
 	}
 
 	checkJournals := func() {
-		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
-		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=7672494164556733923 limit 2", &sqltypes.Result{}, nil)
 	}
 	checkJournals()
 
@@ -1938,8 +1938,8 @@ func TestShardMigrateNoAvailableTabletsForReverseReplication(t *testing.T) {
 	checkIfPrimaryServing(t, tme.ts, "ks:80-", false)
 
 	checkJournals := func() {
-		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=6432976123657117097", &sqltypes.Result{}, nil)
-		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=6432976123657117097", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[0].addQuery("select val from _vt.resharding_journal where id=6432976123657117097 limit 2", &sqltypes.Result{}, nil)
+		tme.dbSourceClients[1].addQuery("select val from _vt.resharding_journal where id=6432976123657117097 limit 2", &sqltypes.Result{}, nil)
 	}
 	checkJournals()
 
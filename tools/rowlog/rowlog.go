@@ -1,16 +1,20 @@
 package main
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -38,7 +42,7 @@ import (
 )
 
 type RowLogConfig struct {
-	ids, cells []string
+	ids, cells, ops []string
 
 	sourceKeyspace, targetKeyspace, table, vtgate, vtctld, pk string
 }
@@ -46,14 +50,21 @@ type RowLogConfig struct {
 func (rlc *RowLogConfig) String() string {
 	s := fmt.Sprintf("\tsource:%s, target:%s, table:%s, ids:%s, pk:%s\n",
 		rlc.sourceKeyspace, rlc.targetKeyspace, rlc.table, strings.Join(rlc.ids, ","), rlc.pk)
-	s += fmt.Sprintf("\tvtgate:%s, vtctld:%s, cells:%s", rlc.vtgate, rlc.vtctld, strings.Join(rlc.cells, ","))
+	s += fmt.Sprintf("\tvtgate:%s, vtctld:%s, cells:%s, ops:%s", rlc.vtgate, rlc.vtctld, strings.Join(rlc.cells, ","), strings.Join(rlc.ops, ","))
 	return s
 }
 
 func (rlc *RowLogConfig) Validate() bool {
-	if rlc.table == "" || len(rlc.cells) == 0 || rlc.vtctld == "" || rlc.vtgate == "" || len(rlc.ids) == 0 || rlc.targetKeyspace == "" || rlc.sourceKeyspace == "" || rlc.pk == "" {
+	if rlc.table == "" || len(rlc.cells) == 0 || rlc.vtctld == "" || rlc.vtgate == "" || len(rlc.ids) == 0 || rlc.targetKeyspace == "" || rlc.sourceKeyspace == "" || rlc.pk == "" || len(rlc.ops) == 0 {
 		return false
 	}
+	for _, op := range rlc.ops {
+		switch op {
+		case "insert", "update", "delete":
+		default:
+			return false
+		}
+	}
 	return true
 }
 
@@ -64,14 +75,22 @@ func usage() {
 		logger.Printf("Rowlog Usage:\n")
 		s := "rowlog --ids <id list csv> --table <table_name> --pk <primary_key_only_ints> --source <source_keyspace> --target <target_keyspace> "
 		s += "--vtctld <vtctl url> --vtgate <vtgate url> --cells <cell names csv> --topo_implementation <topo type, eg: etcd2> "
-		s += "--topo_global_server_address <top url> --topo_global_root <topo root dir>\n"
+		s += "--topo_global_server_address <top url> --topo_global_root <topo root dir> [--ops <insert,update,delete>] [--compress]\n"
 		logger.Printf(s)
 	}
 }
 
 func main() {
 	usage()
-	ctx := context.Background()
+	// Stop streaming on an interrupt so that the log files are still closed,
+	// which completes the gzip stream when compression is enabled. A second
+	// interrupt kills the process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 	config := parseCommandLine()
 	if !config.Validate() {
 		pflag.Usage()
@@ -87,6 +106,7 @@ func main() {
 	var wg sync.WaitGroup
 	var stream = func(keyspace, tablet string) {
 		defer wg.Done()
+		defer closeOutputFilesOnPanic()
 		var startPos, stopPos string
 		var i int
 		var done, fieldsPrinted bool
@@ -98,7 +118,11 @@ func main() {
 				return
 			}
 			log.Infof("%s Iteration:%d", keyspace, i)
-			startPos, stopPos, done, fieldsPrinted, err = startStreaming(ctx, config.vtgate, config.vtctld, keyspace, tablet, config.table, config.pk, config.ids, startPos, stopPos, fieldsPrinted)
+			startPos, stopPos, done, fieldsPrinted, err = startStreaming(ctx, config.vtgate, config.vtctld, keyspace, tablet, config.table, config.pk, config.ids, config.ops, startPos, stopPos, fieldsPrinted)
+			if ctx.Err() != nil {
+				log.Infof("Stopped streaming keyspace %s: %v", keyspace, ctx.Err())
+				return
+			}
 			if done {
 				log.Infof("Finished streaming all events for keyspace %s", keyspace)
 				fmt.Printf("Finished streaming all events for keyspace %s\n", keyspace)
@@ -120,13 +144,20 @@ func main() {
 	go stream(config.targetKeyspace, targetTablet)
 
 	wg.Wait()
+	closeOutputFiles()
+	if ctx.Err() != nil {
+		log.Infof("rowlog interrupted before streaming from both source and target was done")
+		fmt.Printf("\n\nRowlog interrupted\nThe log files contain the binlog entries seen so far: %s and %s\n",
+			outputFileName(config.sourceKeyspace), outputFileName(config.targetKeyspace))
+		return
+	}
 
 	log.Infof("rowlog done streaming from both source and target")
-	fmt.Printf("\n\nRowlog completed\nIf the program worked you should see two log files with the related binlog entries: %s.log and %s.log\n",
-		config.sourceKeyspace, config.targetKeyspace)
+	fmt.Printf("\n\nRowlog completed\nIf the program worked you should see two log files with the related binlog entries: %s and %s\n",
+		outputFileName(config.sourceKeyspace), outputFileName(config.targetKeyspace))
 }
 
-func startStreaming(ctx context.Context, vtgate, vtctld, keyspace, tablet, table, pk string, ids []string, startPos, stopPos string, fieldsPrinted bool) (string, string, bool, bool, error) {
+func startStreaming(ctx context.Context, vtgate, vtctld, keyspace, tablet, table, pk string, ids, ops []string, startPos, stopPos string, fieldsPrinted bool) (string, string, bool, bool, error) {
 	var err error
 	if startPos == "" {
 		flavor := getFlavor(ctx, vtctld, keyspace)
@@ -156,6 +187,9 @@ func startStreaming(ctx context.Context, vtgate, vtctld, keyspace, tablet, table
 	}
 	conn, err := vtgateconn.Dial(ctx, vtgate)
 	if err != nil {
+		// log.Fatal exits without running deferred calls, so close the log
+		// files first.
+		closeOutputFiles()
 		log.Fatal(err)
 	}
 	defer conn.Close()
@@ -163,15 +197,15 @@ func startStreaming(ctx context.Context, vtgate, vtctld, keyspace, tablet, table
 	var fields []*querypb.Field
 	var gtid string
 	var plan *TablePlan
-	var lastLoggedAt int64
+	var lastLoggedAt time.Time
 	var totalRowsForTable, filteredRows int
 	for {
 		evs, err := reader.Recv()
 		switch err {
 		case nil:
 			for _, ev := range evs {
-				now := time.Now().Unix()
-				if now-lastLoggedAt > 60 && ev.Timestamp != 0 { // every minute
+				now := time.Now()
+				if now.Sub(lastLoggedAt) > progressInterval && ev.Timestamp != 0 {
 					lastLoggedAt = now
 					log.Infof("%s Progress: %d/%d rows, %s: %s", keyspace, filteredRows, totalRowsForTable,
 						time.Unix(ev.Timestamp, 0).Format(time.RFC3339), gtid)
@@ -182,7 +216,7 @@ func startStreaming(ctx context.Context, vtgate, vtctld, keyspace, tablet, table
 					gtid = ev.Vgtid.ShardGtids[0].Gtid
 				case binlogdatapb.VEventType_FIELD:
 					fields = ev.FieldEvent.Fields
-					plan = getTablePlan(keyspace, fields, ev.FieldEvent.TableName, pk, ids)
+					plan = getTablePlan(keyspace, fields, ev.FieldEvent.TableName, pk, ids, ops)
 					if !fieldsPrinted {
 						outputHeader(plan)
 						fieldsPrinted = true
@@ -227,17 +261,98 @@ func startStreaming(ctx context.Context, vtgate, vtctld, keyspace, tablet, table
 	}
 }
 
-func output(filename, s string) {
-	f, err := os.OpenFile(filename+".log",
+// outputFile is an open log file that rows are written to. When compression
+// is enabled, writes go through a gzip writer and a buffered writer, so
+// compressed data only reaches the file in blocks and the gzip stream is only
+// complete once the file is closed. The files are closed when rowlog exits,
+// including on a fatal error, a panic or an interrupt.
+type outputFile struct {
+	f  *os.File
+	bw *bufio.Writer
+	gz *gzip.Writer
+}
+
+func (of *outputFile) WriteString(s string) (int, error) {
+	if of.gz != nil {
+		return of.gz.Write([]byte(s))
+	}
+	return of.f.WriteString(s)
+}
+
+func (of *outputFile) Close() error {
+	if of.gz != nil {
+		if err := of.gz.Close(); err != nil {
+			return err
+		}
+		if err := of.bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return of.f.Close()
+}
+
+var (
+	outputFilesMu sync.Mutex
+	outputFiles   = make(map[string]*outputFile)
+)
+
+func outputFileName(filename string) string {
+	if compressOutput {
+		return filename + ".log.gz"
+	}
+	return filename + ".log"
+}
+
+func getOutputFile(filename string) (*outputFile, error) {
+	outputFilesMu.Lock()
+	defer outputFilesMu.Unlock()
+	if of, ok := outputFiles[filename]; ok {
+		return of, nil
+	}
+	f, err := os.OpenFile(outputFileName(filename),
 		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	of := &outputFile{f: f}
+	if compressOutput {
+		of.bw = bufio.NewWriter(f)
+		of.gz = gzip.NewWriter(of.bw)
+	}
+	outputFiles[filename] = of
+	return of, nil
+}
+
+func closeOutputFiles() {
+	outputFilesMu.Lock()
+	defer outputFilesMu.Unlock()
+	for filename, of := range outputFiles {
+		if err := of.Close(); err != nil {
+			log.Errorf("Error closing %s: %v", outputFileName(filename), err)
+		}
+		delete(outputFiles, filename)
+	}
+}
+
+// closeOutputFilesOnPanic closes the log files if the calling goroutine
+// panics, and then re-panics.
+func closeOutputFilesOnPanic() {
+	if r := recover(); r != nil {
+		closeOutputFiles()
+		panic(r)
+	}
+}
+
+func output(filename, s string) {
+	f, err := getOutputFile(filename)
 	if err != nil {
 		log.Errorf(err.Error())
+		return
 	}
-	defer f.Close()
 	if _, err := f.WriteString(s + "\n"); err != nil {
 		log.Errorf(err.Error())
 	}
-	log.Infof("Writing to %s.log: %s", filename, s)
+	log.Infof("Writing to %s: %s", outputFileName(filename), s)
 }
 
 func outputHeader(plan *TablePlan) {
@@ -287,9 +402,9 @@ type RowLog struct {
 }
 
 func processRowEvent(plan *TablePlan, gtid string, ev *binlogdatapb.VEvent) []*RowLog {
-	op := "insert"
 	var rowLogs []*RowLog
 	for _, change := range ev.RowEvent.RowChanges {
+		op := "insert"
 		var after, before []sqltypes.Value
 		var afterVals, beforeVals []string
 		if change.After != nil {
@@ -322,6 +437,9 @@ func processRowEvent(plan *TablePlan, gtid string, ev *binlogdatapb.VEvent) []*R
 			op = "delete"
 			afterVals = beforeVals
 		}
+		if !plan.allowedOps[op] {
+			continue
+		}
 
 		rowLog := &RowLog{
 			op:     op,
@@ -334,11 +452,15 @@ func processRowEvent(plan *TablePlan, gtid string, ev *binlogdatapb.VEvent) []*R
 	return rowLogs
 }
 
-func getTablePlan(keyspace string, fields []*querypb.Field, table, pk string, ids []string) *TablePlan {
+func getTablePlan(keyspace string, fields []*querypb.Field, table, pk string, ids, ops []string) *TablePlan {
 	allowedIds := make(map[string]bool)
 	for _, id := range ids {
 		allowedIds[id] = true
 	}
+	allowedOps := make(map[string]bool)
+	for _, op := range ops {
+		allowedOps[op] = true
+	}
 	var pkIndex int64
 	for i, field := range fields {
 		if field.Name == pk {
@@ -350,6 +472,7 @@ func getTablePlan(keyspace string, fields []*querypb.Field, table, pk string, id
 		table:      table,
 		pk:         pk,
 		allowedIds: allowedIds,
+		allowedOps: allowedOps,
 		pkIndex:    pkIndex,
 		fields:     fields,
 		keyspace:   keyspace,
@@ -359,6 +482,7 @@ func getTablePlan(keyspace string, fields []*querypb.Field, table, pk string, id
 type TablePlan struct {
 	table, pk  string
 	allowedIds map[string]bool
+	allowedOps map[string]bool
 	pkIndex    int64
 	fields     []*querypb.Field
 	keyspace   string
@@ -407,7 +531,11 @@ func trickGlog() {
 	flag.Parse()
 }
 
-var testResumability bool
+var (
+	testResumability bool
+	compressOutput   bool
+	progressInterval = 60 * time.Second
+)
 
 func parseCommandLine() *RowLogConfig {
 	trickGlog()
@@ -419,8 +547,11 @@ func parseCommandLine() *RowLogConfig {
 	vtgate := pflag.String("vtgate", "", "")
 	vtctld := pflag.String("vtctld", "", "")
 	cells := pflag.StringSlice("cells", nil, "")
+	ops := pflag.StringSlice("ops", []string{"insert", "update", "delete"}, "operations to log: csv of insert, update and delete")
 
 	pflag.BoolVar(&testResumability, "test_resumability", testResumability, "set to test stream resumability")
+	pflag.DurationVar(&progressInterval, "progress-interval", progressInterval, "how often to log streaming progress")
+	pflag.BoolVar(&compressOutput, "compress", compressOutput, "write gzip-compressed <keyspace>.log.gz files instead of plaintext <keyspace>.log files")
 
 	pflag.Parse()
 
@@ -433,6 +564,7 @@ func parseCommandLine() *RowLogConfig {
 		vtctld:         *vtctld,
 		vtgate:         *vtgate,
 		cells:          *cells,
+		ops:            *ops,
 	}
 }
 